@@ -25,10 +25,16 @@
 package main
 
 import (
+	"os"
+
 	"app/internal/app/app"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(app.Command(os.Args[1:]))
+	}
+
 	// TODO: init app
 	app.Run()
 }
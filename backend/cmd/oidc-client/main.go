@@ -0,0 +1,352 @@
+// Command oidc-client is a minimal OpenID Connect relying party for trying
+// out the provider locally. It also serves the login and consent page the
+// provider forwards authorization requests to, standing in for the frontend.
+//
+// Register it and point the provider's oidc.loginUrl at it:
+//
+//	api clients create -name "Test Client" -redirect-uri http://localhost:9090/callback
+//	go run ./cmd/oidc-client -client-id ... -client-secret ...
+//
+// then open http://localhost:9090.
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"app/domain"
+	"app/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type client struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURI  string
+	scope        string
+
+	config domain.OpenIDConfiguration
+	keys   map[string]interface{}
+
+	mu      sync.Mutex
+	pending map[string]pending
+}
+
+// pending is an authorization request waiting for its callback.
+type pending struct {
+	nonce    string
+	verifier string
+}
+
+func main() {
+	issuer := flag.String("issuer", "http://localhost:9000", "provider issuer URL")
+	clientID := flag.String("client-id", "", "registered client ID")
+	clientSecret := flag.String("client-secret", "", "client secret; empty for a public client")
+	addr := flag.String("addr", "localhost:9090", "listen address")
+	scope := flag.String("scope", "openid profile email offline_access", "requested scopes")
+	flag.Parse()
+
+	if *clientID == "" {
+		log.Fatal("-client-id is required")
+	}
+
+	c := &client{
+		issuer:       strings.TrimSuffix(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		redirectURI:  "http://" + *addr + "/callback",
+		scope:        *scope,
+		pending:      make(map[string]pending),
+	}
+	if err := c.discover(); err != nil {
+		log.Fatal(err)
+	}
+
+	http.HandleFunc("GET /{$}", c.start)
+	http.HandleFunc("GET /callback", c.callback)
+	http.HandleFunc("GET /login", c.loginPage)
+	http.HandleFunc("POST /login", c.login)
+	http.HandleFunc("POST /consent", c.consent)
+
+	log.Printf("open http://%s to sign in with %s", *addr, c.issuer)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+// discover loads the provider metadata and its signing keys.
+func (c *client) discover() error {
+	if err := getJSON(c.issuer+"/.well-known/openid-configuration", "", &c.config); err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+
+	var jwks auth.JWKS
+	if err := getJSON(c.config.JWKSURI, "", &jwks); err != nil {
+		return fmt.Errorf("jwks: %w", err)
+	}
+	c.keys = make(map[string]interface{})
+	for _, k := range jwks.Keys {
+		pub, err := k.PublicKey()
+		if err != nil {
+			return fmt.Errorf("jwks: key %s: %w", k.Kid, err)
+		}
+		c.keys[k.Kid] = pub
+	}
+	if len(c.keys) == 0 {
+		log.Print("the provider publishes no keys (HMAC signing?), ID tokens cannot be verified")
+	}
+	return nil
+}
+
+// start sends the browser to the authorization endpoint.
+func (c *client) start(w http.ResponseWriter, r *http.Request) {
+	state, nonce, verifier := random(), random(), random()
+	c.mu.Lock()
+	c.pending[state] = pending{nonce: nonce, verifier: verifier}
+	c.mu.Unlock()
+
+	challenge := sha256.Sum256([]byte(verifier))
+	q := url.Values{
+		"client_id":             {c.clientID},
+		"redirect_uri":          {c.redirectURI},
+		"response_type":         {"code"},
+		"scope":                 {c.scope},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	http.Redirect(w, r, c.config.AuthorizationEndpoint+"?"+q.Encode(), http.StatusFound)
+}
+
+// callback exchanges the authorization code and shows the result.
+func (c *client) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	c.mu.Lock()
+	p, ok := c.pending[q.Get("state")]
+	delete(c.pending, q.Get("state"))
+	c.mu.Unlock()
+	if !ok {
+		render(w, "Error", "unknown state")
+		return
+	}
+	if q.Get("iss") != c.config.Issuer {
+		render(w, "Error", "unexpected issuer "+q.Get("iss"))
+		return
+	}
+	if e := q.Get("error"); e != "" {
+		render(w, "Error", e+": "+q.Get("error_description"))
+		return
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {q.Get("code")},
+		"redirect_uri":  {c.redirectURI},
+		"code_verifier": {p.verifier},
+	}
+	if c.clientSecret == "" {
+		form.Set("client_id", c.clientID)
+	}
+	req, _ := http.NewRequest(http.MethodPost, c.config.TokenEndpoint, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+	}
+
+	var tokens domain.OAuthTokenResponse
+	if err := doJSON(req, &tokens); err != nil {
+		render(w, "Token request failed", err.Error())
+		return
+	}
+
+	idClaims, err := c.verifyIDToken(tokens.IDToken, p.nonce)
+	if err != nil {
+		render(w, "Invalid ID token", err.Error())
+		return
+	}
+
+	var info domain.UserInfoResponse
+	if err := getJSON(c.config.UserInfoEndpoint, tokens.AccessToken, &info); err != nil {
+		render(w, "UserInfo request failed", err.Error())
+		return
+	}
+
+	render(w, "Signed in", pretty(map[string]interface{}{
+		"tokens":   tokens,
+		"id_token": idClaims,
+		"userinfo": info,
+	}))
+}
+
+// verifyIDToken checks the signature, issuer, audience and nonce of an ID
+// token and returns its claims.
+func (c *client) verifyIDToken(raw string, nonce string) (*auth.IDTokenClaims, error) {
+	var claims auth.IDTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := c.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		return key, nil
+	}, jwt.WithIssuer(c.config.Issuer), jwt.WithAudience(c.clientID), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+	return &claims, nil
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!doctype html>
+<title>Sign in</title>
+<h1>Sign in</h1>
+{{if .Error}}<p style="color:red">{{.Error}}</p>{{end}}
+<form method="post" action="/login">
+<input type="hidden" name="request" value="{{.Request}}">
+<p><input name="email" type="email" placeholder="Email" required></p>
+<p><input name="password" type="password" placeholder="Password" required></p>
+<p><button>Sign in</button></p>
+</form>`))
+
+var consentTemplate = template.Must(template.New("consent").Parse(`<!doctype html>
+<title>Consent</title>
+<h1>{{.ClientName}} wants to access your account</h1>
+<p>Scopes: {{range .Scopes}}<code>{{.}}</code> {{end}}</p>
+<form method="post" action="/consent">
+<input type="hidden" name="request" value="{{.Request}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<button name="decision" value="allow">Allow</button>
+<button name="decision" value="deny">Deny</button>
+</form>`))
+
+// loginPage is where the provider forwards authorization requests to.
+func (c *client) loginPage(w http.ResponseWriter, r *http.Request) {
+	loginTemplate.Execute(w, map[string]string{"Request": r.URL.RawQuery})
+}
+
+// login signs the user in with the regular API and completes the request.
+func (c *client) login(w http.ResponseWriter, r *http.Request) {
+	body, _ := json.Marshal(domain.LoginDTO{Email: r.FormValue("email"), Password: r.FormValue("password")})
+	req, _ := http.NewRequest(http.MethodPost, c.issuer+"/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp domain.AuthResponse
+	if err := doJSON(req, &resp); err != nil {
+		loginTemplate.Execute(w, map[string]string{"Request": r.FormValue("request"), "Error": err.Error()})
+		return
+	}
+	if resp.AccessToken == "" {
+		loginTemplate.Execute(w, map[string]string{"Request": r.FormValue("request"), "Error": "accounts with two-factor authentication are not supported by this test client"})
+		return
+	}
+
+	c.complete(w, r, r.FormValue("request"), resp.AccessToken, "")
+}
+
+func (c *client) consent(w http.ResponseWriter, r *http.Request) {
+	c.complete(w, r, r.FormValue("request"), r.FormValue("token"), r.FormValue("decision"))
+}
+
+// complete posts the authorization request to /oauth/authorize and either
+// asks for consent or follows the redirect back to the client.
+func (c *client) complete(w http.ResponseWriter, r *http.Request, rawQuery string, token string, decision string) {
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		render(w, "Error", err.Error())
+		return
+	}
+
+	body, _ := json.Marshal(domain.OAuthAuthorizeDTO{
+		ClientID:            q.Get("client_id"),
+		RedirectURI:         q.Get("redirect_uri"),
+		ResponseType:        q.Get("response_type"),
+		Scope:               q.Get("scope"),
+		State:               q.Get("state"),
+		Nonce:               q.Get("nonce"),
+		CodeChallenge:       q.Get("code_challenge"),
+		CodeChallengeMethod: q.Get("code_challenge_method"),
+		Prompt:              q.Get("prompt"),
+		Decision:            decision,
+	})
+	req, _ := http.NewRequest(http.MethodPost, c.issuer+"/oauth/authorize", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var resp domain.OAuthAuthorizeResponse
+	if err := doJSON(req, &resp); err != nil {
+		render(w, "Authorization failed", err.Error())
+		return
+	}
+	if resp.ConsentRequired {
+		consentTemplate.Execute(w, map[string]interface{}{
+			"ClientName": resp.ClientName,
+			"Scopes":     resp.Scopes,
+			"Request":    rawQuery,
+			"Token":      token,
+		})
+		return
+	}
+	http.Redirect(w, r, resp.RedirectTo, http.StatusFound)
+}
+
+func getJSON(u string, token string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return doJSON(req, v)
+}
+
+func doJSON(req *http.Request, v interface{}) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body bytes.Buffer
+		body.ReadFrom(resp.Body)
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(body.String()))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+var resultTemplate = template.Must(template.New("result").Parse(`<!doctype html>
+<title>{{.Title}}</title>
+<h1>{{.Title}}</h1>
+<pre>{{.Body}}</pre>
+<p><a href="/">Sign in again</a></p>`))
+
+func render(w http.ResponseWriter, title string, body string) {
+	resultTemplate.Execute(w, map[string]string{"Title": title, "Body": body})
+}
+
+func pretty(v interface{}) string {
+	b, _ := json.MarshalIndent(v, "", "  ")
+	return string(b)
+}
+
+func random() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// CreateAPIKeyDTO creates an API key. Without expires_at the key does not
+// expire, unless the server limits the lifetime of keys
+type CreateAPIKeyDTO struct {
+	Name      string     `json:"name" example:"GitHub Actions" binding:"required"`
+	Scopes    []string   `json:"scopes,omitempty" example:"orders:read"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-01-01T00:00:00Z"`
+}
+
+// APIKeyResponse represents an API key. Prefix identifies the key; the key
+// itself is only returned once, on creation
+type APIKeyResponse struct {
+	ID         int        `json:"id" example:"1"`
+	Name       string     `json:"name" example:"GitHub Actions"`
+	Prefix     string     `json:"prefix" example:"sk_k2vx7m4q"`
+	Scopes     []string   `json:"scopes" example:"orders:read"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2025-01-01T00:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2024-01-02T08:30:00Z"`
+}
+
+// CreateAPIKeyResponse returns a new API key together with the key to send
+// as Bearer token
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"sk_k2vx7m4q_Yk3nV0cXh8Lq..."`
+}
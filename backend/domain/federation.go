@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// FederationProviderResponse represents an identity provider users can sign
+// in with
+type FederationProviderResponse struct {
+	Name        string `json:"name" example:"google"`
+	DisplayName string `json:"display_name" example:"Google"`
+}
+
+// FederationBeginResponse starts a sign-in at an identity provider. The
+// frontend sends the user to authorization_url and keeps state to check it
+// against the one the provider redirects back with
+type FederationBeginResponse struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://accounts.google.com/o/oauth2/v2/auth?client_id=...&state=Yk3n...Q2c"`
+	State            string `json:"state" example:"Yk3n...Q2c"`
+}
+
+// FederationCallbackDTO finishes a sign-in with the query parameters the
+// identity provider redirected back with
+type FederationCallbackDTO struct {
+	State string `json:"state" example:"Yk3n...Q2c" binding:"required"`
+	Code  string `json:"code" example:"4/0AX4XfWh...Q2c" binding:"required"`
+}
+
+// IdentityResponse represents an external identity linked to the account
+type IdentityResponse struct {
+	ID          int        `json:"id" example:"1"`
+	Provider    string     `json:"provider" example:"google"`
+	Subject     string     `json:"subject" example:"110169484474386276334"`
+	Email       string     `json:"email,omitempty" example:"user@example.com"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" example:"2024-01-02T08:30:00Z"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+// MagicLinkRequestDTO asks for a sign-in link and code by email
+type MagicLinkRequestDTO struct {
+	Email string `json:"email" example:"user@example.com" binding:"required,email"`
+}
+
+// MagicLinkConsumeDTO signs in with either the token from a sign-in link or
+// the email address and the code mailed with it
+type MagicLinkConsumeDTO struct {
+	Token string `json:"token,omitempty" example:"Yk3n...Q2c"`
+	Email string `json:"email,omitempty" example:"user@example.com"`
+	Code  string `json:"code,omitempty" example:"493027"`
+}
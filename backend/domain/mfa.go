@@ -0,0 +1,32 @@
+package domain
+
+// MFAChallengeResponse is returned by login instead of tokens when the user
+// has two-factor authentication enabled
+type MFAChallengeResponse struct {
+	MFARequired    bool   `json:"mfa_required" example:"true"`
+	ChallengeToken string `json:"challenge_token" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiIsInR5cCI6ImFjdGlvbitqd3QifQ..."`
+	ExpiresIn      int    `json:"expires_in" example:"300"`
+}
+
+// MFAVerifyDTO completes a login with a TOTP code or a recovery code
+type MFAVerifyDTO struct {
+	ChallengeToken string `json:"challenge_token" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiIsInR5cCI6ImFjdGlvbitqd3QifQ..." binding:"required"`
+	Code           string `json:"code" example:"123456" binding:"required"`
+}
+
+// MFACodeDTO represents a TOTP code, or a recovery code where allowed
+type MFACodeDTO struct {
+	Code string `json:"code" example:"123456" binding:"required"`
+}
+
+// TOTPEnrollmentResponse represents a new TOTP secret to add to an
+// authenticator app
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"otpauth_uri" example:"otpauth://totp/Go-Shablon:user@example.com?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Go-Shablon"`
+}
+
+// RecoveryCodesResponse lists new recovery codes. They are shown only once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3f9-x2qa,7hbd-p0rt"`
+}
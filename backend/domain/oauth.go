@@ -0,0 +1,104 @@
+package domain
+
+import "time"
+
+// OAuthAuthorizeDTO completes an authorization request forwarded by
+// /authorize. The fields are the query parameters of that request; decision
+// answers the consent prompt
+type OAuthAuthorizeDTO struct {
+	ClientID            string `json:"client_id" example:"q8Jx2mVbT0aLr5sN1cWfYg"`
+	RedirectURI         string `json:"redirect_uri" example:"https://app.example.com/callback"`
+	ResponseType        string `json:"response_type" example:"code"`
+	Scope               string `json:"scope" example:"openid email offline_access"`
+	State               string `json:"state,omitempty" example:"af0ifjsldkj"`
+	Nonce               string `json:"nonce,omitempty" example:"n-0S6_WzA2Mj"`
+	CodeChallenge       string `json:"code_challenge" example:"E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"`
+	CodeChallengeMethod string `json:"code_challenge_method" example:"S256"`
+	Prompt              string `json:"prompt,omitempty" example:"consent"`
+	Decision            string `json:"decision,omitempty" example:"allow" enums:"allow,deny"`
+}
+
+// OAuthAuthorizeResponse either sends the user back to the client or asks
+// for their consent first
+type OAuthAuthorizeResponse struct {
+	RedirectTo      string   `json:"redirect_to,omitempty" example:"https://app.example.com/callback?code=Yk3n...Q2c&state=af0ifjsldkj"`
+	ConsentRequired bool     `json:"consent_required" example:"false"`
+	ClientID        string   `json:"client_id,omitempty" example:"q8Jx2mVbT0aLr5sN1cWfYg"`
+	ClientName      string   `json:"client_name,omitempty" example:"Web App"`
+	Scopes          []string `json:"scopes,omitempty" example:"openid,email"`
+}
+
+// OAuthTokenResponse is returned by the token endpoint
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" example:"3600"`
+	RefreshToken string `json:"refresh_token,omitempty" example:"Yk3n...Q2c"`
+	IDToken      string `json:"id_token,omitempty" example:"eyJhbGciOiJSUzI1NiIsImtpZCI6IjEifQ..."`
+	Scope        string `json:"scope" example:"openid email offline_access"`
+}
+
+// OAuthErrorResponse is an OAuth 2.0 error
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_grant"`
+	ErrorDescription string `json:"error_description,omitempty" example:"the authorization code is invalid, expired or already used"`
+}
+
+// OAuthIntrospectionResponse describes a token (RFC 7662). Only active is
+// set for a token that is not active
+type OAuthIntrospectionResponse struct {
+	Active    bool     `json:"active" example:"true"`
+	TokenType string   `json:"token_type,omitempty" example:"Bearer"`
+	Scope     string   `json:"scope,omitempty" example:"openid email offline_access"`
+	ClientID  string   `json:"client_id,omitempty" example:"q8Jx2mVbT0aLr5sN1cWfYg"`
+	Subject   string   `json:"sub,omitempty" example:"1"`
+	Issuer    string   `json:"iss,omitempty" example:"http://localhost:9000"`
+	Audience  []string `json:"aud,omitempty" example:"api"`
+	SessionID string   `json:"sid,omitempty" example:"2f1c4d9e8b7a6f5e"`
+	TokenID   string   `json:"jti,omitempty" example:"9b2f6c1e-3d4a-4f8b-a1c2-7e5d9f0b3a6c"`
+	ExpiresAt int64    `json:"exp,omitempty" example:"1704110400"`
+	IssuedAt  int64    `json:"iat,omitempty" example:"1704106800"`
+	NotBefore int64    `json:"nbf,omitempty" example:"1704106800"`
+}
+
+// UserInfoResponse holds the claims about the user released for the scopes
+// of the access token
+type UserInfoResponse struct {
+	Subject           string `json:"sub" example:"1"`
+	Email             string `json:"email,omitempty" example:"user@example.com"`
+	EmailVerified     *bool  `json:"email_verified,omitempty" example:"true"`
+	PreferredUsername string `json:"preferred_username,omitempty" example:"johndoe"`
+}
+
+// OpenIDConfiguration is the OpenID Connect discovery document
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer" example:"http://localhost:9000"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint" example:"http://localhost:9000/authorize"`
+	TokenEndpoint                     string   `json:"token_endpoint" example:"http://localhost:9000/token"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint" example:"http://localhost:9000/userinfo"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint" example:"http://localhost:9000/oauth/introspect"`
+	RevocationEndpoint                string   `json:"revocation_endpoint" example:"http://localhost:9000/oauth/revoke"`
+	JWKSURI                           string   `json:"jwks_uri" example:"http://localhost:9000/.well-known/jwks.json"`
+	ScopesSupported                   []string `json:"scopes_supported" example:"openid,profile,email,offline_access"`
+	ResponseTypesSupported            []string `json:"response_types_supported" example:"code"`
+	ResponseModesSupported            []string `json:"response_modes_supported" example:"query"`
+	GrantTypesSupported               []string `json:"grant_types_supported" example:"authorization_code,refresh_token"`
+	SubjectTypesSupported             []string `json:"subject_types_supported" example:"public"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported" example:"RS256"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported" example:"client_secret_basic,client_secret_post,none"`
+	IntrospectionEndpointAuthMethods  []string `json:"introspection_endpoint_auth_methods_supported" example:"client_secret_basic,client_secret_post"`
+	RevocationEndpointAuthMethods     []string `json:"revocation_endpoint_auth_methods_supported" example:"client_secret_basic,client_secret_post,none"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported" example:"S256"`
+	ClaimsSupported                   []string `json:"claims_supported" example:"sub,iss,aud,exp,iat,nonce,email,email_verified,preferred_username"`
+	PromptValuesSupported             []string `json:"prompt_values_supported" example:"none,consent"`
+	AuthorizationResponseIssParameter bool     `json:"authorization_response_iss_parameter_supported" example:"true"`
+}
+
+// OAuthConsentResponse represents a client the user has shared scopes with
+type OAuthConsentResponse struct {
+	ClientID   string    `json:"client_id" example:"q8Jx2mVbT0aLr5sN1cWfYg"`
+	ClientName string    `json:"client_name" example:"Web App"`
+	Scopes     []string  `json:"scopes" example:"openid,email"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-01-02T08:30:00Z"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PasskeyRegisterDTO finishes a passkey registration with the credential
+// returned by navigator.credentials.create()
+type PasskeyRegisterDTO struct {
+	Name       string          `json:"name,omitempty" example:"MacBook Touch ID"`
+	Credential json.RawMessage `json:"credential" swaggertype:"object" binding:"required"`
+}
+
+// PasskeyLoginDTO finishes a passkey login with the assertion returned by
+// navigator.credentials.get()
+type PasskeyLoginDTO struct {
+	Credential json.RawMessage `json:"credential" swaggertype:"object" binding:"required"`
+}
+
+// PasskeyResponse represents a registered passkey
+type PasskeyResponse struct {
+	ID         int        `json:"id" example:"1"`
+	Name       string     `json:"name" example:"MacBook Touch ID"`
+	BackedUp   bool       `json:"backed_up" example:"true"`
+	CreatedAt  time.Time  `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2024-01-02T08:30:00Z"`
+}
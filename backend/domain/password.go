@@ -0,0 +1,30 @@
+package domain
+
+// ForgotPasswordDTO represents the password reset request
+type ForgotPasswordDTO struct {
+	Email string `json:"email" example:"user@example.com" binding:"required,email"`
+}
+
+// ResetPasswordDTO represents a new password set with a reset token
+type ResetPasswordDTO struct {
+	Token    string `json:"token" example:"oTQmq2Y2sbSQjhQF7HMbULXPOvHj7KjCY9an410" binding:"required"`
+	Password string `json:"password" example:"newSecurePassword456" binding:"required,min=8"`
+}
+
+// ChangePasswordDTO represents a password change by the signed in user
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"current_password" example:"securePassword123" binding:"required"`
+	NewPassword     string `json:"new_password" example:"newSecurePassword456" binding:"required,min=8"`
+}
+
+// PasswordPolicyErrorResponse lists every password rule a request violated
+type PasswordPolicyErrorResponse struct {
+	Error      string                    `json:"error" example:"password does not meet the policy"`
+	Violations []PasswordPolicyViolation `json:"violations"`
+}
+
+// PasswordPolicyViolation describes one violated password rule
+type PasswordPolicyViolation struct {
+	Rule    string `json:"rule" example:"min_length"`
+	Message string `json:"message" example:"must be at least 8 characters"`
+}
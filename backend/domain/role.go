@@ -0,0 +1,15 @@
+package domain
+
+// RoleResponse represents a role and the permissions it grants
+type RoleResponse struct {
+	Name        string   `json:"name" example:"admin"`
+	Description string   `json:"description,omitempty" example:"Full access to every endpoint"`
+	Permissions []string `json:"permissions" example:"users:read,users:write"`
+}
+
+// CreateRoleDTO represents the role creation request payload
+type CreateRoleDTO struct {
+	Name        string   `json:"name" example:"support" binding:"required"`
+	Description string   `json:"description" example:"Customer support staff"`
+	Permissions []string `json:"permissions" example:"users:read"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// SessionResponse represents an active login on one device
+type SessionResponse struct {
+	ID         string    `json:"id" example:"Xk3v9Qm2TgKcW1pRz8yLdA"`
+	Label      string    `json:"label" example:"Firefox on Linux"`
+	UserAgent  string    `json:"user_agent,omitempty" example:"Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"`
+	IP         string    `json:"ip,omitempty" example:"203.0.113.7"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2024-01-02T08:30:00Z"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2024-01-09T08:30:00Z"`
+	Current    bool      `json:"current" example:"true"`
+}
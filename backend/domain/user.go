@@ -7,24 +7,39 @@ type RegisterDTO struct {
 	Username string `json:"username" example:"johndoe" binding:"omitempty,min=3"`
 }
 
+// RegisterResponse represents the registration response. It is the same
+// whether or not the email was already registered.
+type RegisterResponse struct {
+	Message string `json:"message" example:"check your email to continue"`
+}
+
 // LoginDTO represents the login request payload
 type LoginDTO struct {
 	Email    string `json:"email" example:"user@example.com" binding:"required,email"`
 	Password string `json:"password" example:"securePassword123" binding:"required"`
 }
 
+// UpdateUserDTO represents a partial update of the current user.
+// Omitted fields are left unchanged.
+type UpdateUserDTO struct {
+	Email    *string `json:"email,omitempty" example:"new@example.com" binding:"omitempty,email"`
+	Username *string `json:"username,omitempty" example:"johnny" binding:"omitempty,min=3"`
+}
+
 // UserResponse represents the user data in responses
 type UserResponse struct {
-	ID       int    `json:"id" example:"1"`
-	Email    string `json:"email" example:"user@example.com"`
-	Username string `json:"username,omitempty" example:"johndoe"`
+	ID            int    `json:"id" example:"1"`
+	Email         string `json:"email" example:"user@example.com"`
+	EmailVerified bool   `json:"email_verified" example:"true"`
+	Username      string `json:"username,omitempty" example:"johndoe"`
+	MFAEnabled    bool   `json:"mfa_enabled" example:"false"`
 }
 
 // AuthResponse represents the authentication response
 type AuthResponse struct {
 	User         UserResponse `json:"user"`
-	AccessToken  string       `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	RefreshToken string       `json:"refresh_token" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AccessToken  string       `json:"access_token,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string       `json:"refresh_token,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
 }
 
 // RefreshTokenDTO represents the token refresh request
@@ -37,6 +52,21 @@ type LogoutDTO struct {
 	RefreshToken string `json:"refresh_token" example:"550e8400-e29b-41d4-a716-446655440000" binding:"required"`
 }
 
+// VerifyEmailDTO represents the email verification request
+type VerifyEmailDTO struct {
+	Token string `json:"token" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiIsInR5cCI6ImFjdGlvbitqd3QifQ..." binding:"required"`
+}
+
+// UnlockAccountDTO represents the request to lift a login lockout
+type UnlockAccountDTO struct {
+	Token string `json:"token" example:"eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiIsInR5cCI6ImFjdGlvbitqd3QifQ..." binding:"required"`
+}
+
+// ResendVerificationDTO represents the request for a new verification email
+type ResendVerificationDTO struct {
+	Email string `json:"email" example:"user@example.com" binding:"required,email"`
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error string `json:"error" example:"error message"`
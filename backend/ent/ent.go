@@ -3,8 +3,22 @@
 package ent
 
 import (
+	"app/ent/apikey"
+	"app/ent/authorizationcode"
+	"app/ent/federationstate"
+	"app/ent/loginthrottle"
+	"app/ent/magiclinktoken"
+	"app/ent/oauthclient"
+	"app/ent/oauthconsent"
+	"app/ent/passwordresettoken"
+	"app/ent/recoverycode"
 	"app/ent/refreshtoken"
+	"app/ent/role"
+	"app/ent/serviceclient"
 	"app/ent/user"
+	"app/ent/useridentity"
+	"app/ent/webauthncredential"
+	"app/ent/webauthnsession"
 	"context"
 	"errors"
 	"fmt"
@@ -74,8 +88,22 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			refreshtoken.Table: refreshtoken.ValidColumn,
-			user.Table:         user.ValidColumn,
+			apikey.Table:             apikey.ValidColumn,
+			authorizationcode.Table:  authorizationcode.ValidColumn,
+			federationstate.Table:    federationstate.ValidColumn,
+			loginthrottle.Table:      loginthrottle.ValidColumn,
+			magiclinktoken.Table:     magiclinktoken.ValidColumn,
+			oauthclient.Table:        oauthclient.ValidColumn,
+			oauthconsent.Table:       oauthconsent.ValidColumn,
+			passwordresettoken.Table: passwordresettoken.ValidColumn,
+			recoverycode.Table:       recoverycode.ValidColumn,
+			refreshtoken.Table:       refreshtoken.ValidColumn,
+			role.Table:               role.ValidColumn,
+			serviceclient.Table:      serviceclient.ValidColumn,
+			user.Table:               user.ValidColumn,
+			useridentity.Table:       useridentity.ValidColumn,
+			webauthncredential.Table: webauthncredential.ValidColumn,
+			webauthnsession.Table:    webauthnsession.ValidColumn,
 		})
 	})
 	return columnCheck(t, c)
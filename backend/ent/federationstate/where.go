@@ -0,0 +1,499 @@
+// Code generated by ent, DO NOT EDIT.
+
+package federationstate
+
+import (
+	"app/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldID, id))
+}
+
+// StateHash applies equality check predicate on the "state_hash" field. It's identical to StateHashEQ.
+func StateHash(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldStateHash, v))
+}
+
+// Provider applies equality check predicate on the "provider" field. It's identical to ProviderEQ.
+func Provider(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldProvider, v))
+}
+
+// Nonce applies equality check predicate on the "nonce" field. It's identical to NonceEQ.
+func Nonce(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldNonce, v))
+}
+
+// CodeVerifier applies equality check predicate on the "code_verifier" field. It's identical to CodeVerifierEQ.
+func CodeVerifier(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldCodeVerifier, v))
+}
+
+// UserID applies equality check predicate on the "user_id" field. It's identical to UserIDEQ.
+func UserID(v int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldUserID, v))
+}
+
+// ExpiresAt applies equality check predicate on the "expires_at" field. It's identical to ExpiresAtEQ.
+func ExpiresAt(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// StateHashEQ applies the EQ predicate on the "state_hash" field.
+func StateHashEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldStateHash, v))
+}
+
+// StateHashNEQ applies the NEQ predicate on the "state_hash" field.
+func StateHashNEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldStateHash, v))
+}
+
+// StateHashIn applies the In predicate on the "state_hash" field.
+func StateHashIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldStateHash, vs...))
+}
+
+// StateHashNotIn applies the NotIn predicate on the "state_hash" field.
+func StateHashNotIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldStateHash, vs...))
+}
+
+// StateHashGT applies the GT predicate on the "state_hash" field.
+func StateHashGT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldStateHash, v))
+}
+
+// StateHashGTE applies the GTE predicate on the "state_hash" field.
+func StateHashGTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldStateHash, v))
+}
+
+// StateHashLT applies the LT predicate on the "state_hash" field.
+func StateHashLT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldStateHash, v))
+}
+
+// StateHashLTE applies the LTE predicate on the "state_hash" field.
+func StateHashLTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldStateHash, v))
+}
+
+// StateHashContains applies the Contains predicate on the "state_hash" field.
+func StateHashContains(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContains(FieldStateHash, v))
+}
+
+// StateHashHasPrefix applies the HasPrefix predicate on the "state_hash" field.
+func StateHashHasPrefix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasPrefix(FieldStateHash, v))
+}
+
+// StateHashHasSuffix applies the HasSuffix predicate on the "state_hash" field.
+func StateHashHasSuffix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasSuffix(FieldStateHash, v))
+}
+
+// StateHashEqualFold applies the EqualFold predicate on the "state_hash" field.
+func StateHashEqualFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEqualFold(FieldStateHash, v))
+}
+
+// StateHashContainsFold applies the ContainsFold predicate on the "state_hash" field.
+func StateHashContainsFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContainsFold(FieldStateHash, v))
+}
+
+// ProviderEQ applies the EQ predicate on the "provider" field.
+func ProviderEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldProvider, v))
+}
+
+// ProviderNEQ applies the NEQ predicate on the "provider" field.
+func ProviderNEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldProvider, v))
+}
+
+// ProviderIn applies the In predicate on the "provider" field.
+func ProviderIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldProvider, vs...))
+}
+
+// ProviderNotIn applies the NotIn predicate on the "provider" field.
+func ProviderNotIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldProvider, vs...))
+}
+
+// ProviderGT applies the GT predicate on the "provider" field.
+func ProviderGT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldProvider, v))
+}
+
+// ProviderGTE applies the GTE predicate on the "provider" field.
+func ProviderGTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldProvider, v))
+}
+
+// ProviderLT applies the LT predicate on the "provider" field.
+func ProviderLT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldProvider, v))
+}
+
+// ProviderLTE applies the LTE predicate on the "provider" field.
+func ProviderLTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldProvider, v))
+}
+
+// ProviderContains applies the Contains predicate on the "provider" field.
+func ProviderContains(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContains(FieldProvider, v))
+}
+
+// ProviderHasPrefix applies the HasPrefix predicate on the "provider" field.
+func ProviderHasPrefix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasPrefix(FieldProvider, v))
+}
+
+// ProviderHasSuffix applies the HasSuffix predicate on the "provider" field.
+func ProviderHasSuffix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasSuffix(FieldProvider, v))
+}
+
+// ProviderEqualFold applies the EqualFold predicate on the "provider" field.
+func ProviderEqualFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEqualFold(FieldProvider, v))
+}
+
+// ProviderContainsFold applies the ContainsFold predicate on the "provider" field.
+func ProviderContainsFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContainsFold(FieldProvider, v))
+}
+
+// NonceEQ applies the EQ predicate on the "nonce" field.
+func NonceEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldNonce, v))
+}
+
+// NonceNEQ applies the NEQ predicate on the "nonce" field.
+func NonceNEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldNonce, v))
+}
+
+// NonceIn applies the In predicate on the "nonce" field.
+func NonceIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldNonce, vs...))
+}
+
+// NonceNotIn applies the NotIn predicate on the "nonce" field.
+func NonceNotIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldNonce, vs...))
+}
+
+// NonceGT applies the GT predicate on the "nonce" field.
+func NonceGT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldNonce, v))
+}
+
+// NonceGTE applies the GTE predicate on the "nonce" field.
+func NonceGTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldNonce, v))
+}
+
+// NonceLT applies the LT predicate on the "nonce" field.
+func NonceLT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldNonce, v))
+}
+
+// NonceLTE applies the LTE predicate on the "nonce" field.
+func NonceLTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldNonce, v))
+}
+
+// NonceContains applies the Contains predicate on the "nonce" field.
+func NonceContains(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContains(FieldNonce, v))
+}
+
+// NonceHasPrefix applies the HasPrefix predicate on the "nonce" field.
+func NonceHasPrefix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasPrefix(FieldNonce, v))
+}
+
+// NonceHasSuffix applies the HasSuffix predicate on the "nonce" field.
+func NonceHasSuffix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasSuffix(FieldNonce, v))
+}
+
+// NonceEqualFold applies the EqualFold predicate on the "nonce" field.
+func NonceEqualFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEqualFold(FieldNonce, v))
+}
+
+// NonceContainsFold applies the ContainsFold predicate on the "nonce" field.
+func NonceContainsFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContainsFold(FieldNonce, v))
+}
+
+// CodeVerifierEQ applies the EQ predicate on the "code_verifier" field.
+func CodeVerifierEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldCodeVerifier, v))
+}
+
+// CodeVerifierNEQ applies the NEQ predicate on the "code_verifier" field.
+func CodeVerifierNEQ(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldCodeVerifier, v))
+}
+
+// CodeVerifierIn applies the In predicate on the "code_verifier" field.
+func CodeVerifierIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldCodeVerifier, vs...))
+}
+
+// CodeVerifierNotIn applies the NotIn predicate on the "code_verifier" field.
+func CodeVerifierNotIn(vs ...string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldCodeVerifier, vs...))
+}
+
+// CodeVerifierGT applies the GT predicate on the "code_verifier" field.
+func CodeVerifierGT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldCodeVerifier, v))
+}
+
+// CodeVerifierGTE applies the GTE predicate on the "code_verifier" field.
+func CodeVerifierGTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldCodeVerifier, v))
+}
+
+// CodeVerifierLT applies the LT predicate on the "code_verifier" field.
+func CodeVerifierLT(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldCodeVerifier, v))
+}
+
+// CodeVerifierLTE applies the LTE predicate on the "code_verifier" field.
+func CodeVerifierLTE(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldCodeVerifier, v))
+}
+
+// CodeVerifierContains applies the Contains predicate on the "code_verifier" field.
+func CodeVerifierContains(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContains(FieldCodeVerifier, v))
+}
+
+// CodeVerifierHasPrefix applies the HasPrefix predicate on the "code_verifier" field.
+func CodeVerifierHasPrefix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasPrefix(FieldCodeVerifier, v))
+}
+
+// CodeVerifierHasSuffix applies the HasSuffix predicate on the "code_verifier" field.
+func CodeVerifierHasSuffix(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldHasSuffix(FieldCodeVerifier, v))
+}
+
+// CodeVerifierEqualFold applies the EqualFold predicate on the "code_verifier" field.
+func CodeVerifierEqualFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEqualFold(FieldCodeVerifier, v))
+}
+
+// CodeVerifierContainsFold applies the ContainsFold predicate on the "code_verifier" field.
+func CodeVerifierContainsFold(v string) predicate.FederationState {
+	return predicate.FederationState(sql.FieldContainsFold(FieldCodeVerifier, v))
+}
+
+// UserIDEQ applies the EQ predicate on the "user_id" field.
+func UserIDEQ(v int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldUserID, v))
+}
+
+// UserIDNEQ applies the NEQ predicate on the "user_id" field.
+func UserIDNEQ(v int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldUserID, v))
+}
+
+// UserIDIn applies the In predicate on the "user_id" field.
+func UserIDIn(vs ...int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldUserID, vs...))
+}
+
+// UserIDNotIn applies the NotIn predicate on the "user_id" field.
+func UserIDNotIn(vs ...int) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldUserID, vs...))
+}
+
+// UserIDIsNil applies the IsNil predicate on the "user_id" field.
+func UserIDIsNil() predicate.FederationState {
+	return predicate.FederationState(sql.FieldIsNull(FieldUserID))
+}
+
+// UserIDNotNil applies the NotNil predicate on the "user_id" field.
+func UserIDNotNil() predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotNull(FieldUserID))
+}
+
+// ExpiresAtEQ applies the EQ predicate on the "expires_at" field.
+func ExpiresAtEQ(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtNEQ applies the NEQ predicate on the "expires_at" field.
+func ExpiresAtNEQ(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtIn applies the In predicate on the "expires_at" field.
+func ExpiresAtIn(vs ...time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtNotIn applies the NotIn predicate on the "expires_at" field.
+func ExpiresAtNotIn(vs ...time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtGT applies the GT predicate on the "expires_at" field.
+func ExpiresAtGT(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldExpiresAt, v))
+}
+
+// ExpiresAtGTE applies the GTE predicate on the "expires_at" field.
+func ExpiresAtGTE(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtLT applies the LT predicate on the "expires_at" field.
+func ExpiresAtLT(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldExpiresAt, v))
+}
+
+// ExpiresAtLTE applies the LTE predicate on the "expires_at" field.
+func ExpiresAtLTE(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldExpiresAt, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.FederationState {
+	return predicate.FederationState(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.FederationState {
+	return predicate.FederationState(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.FederationState {
+	return predicate.FederationState(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.FederationState) predicate.FederationState {
+	return predicate.FederationState(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.FederationState) predicate.FederationState {
+	return predicate.FederationState(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.FederationState) predicate.FederationState {
+	return predicate.FederationState(sql.NotPredicates(p))
+}
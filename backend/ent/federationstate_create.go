@@ -0,0 +1,324 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/federationstate"
+	"app/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FederationStateCreate is the builder for creating a FederationState entity.
+type FederationStateCreate struct {
+	config
+	mutation *FederationStateMutation
+	hooks    []Hook
+}
+
+// SetStateHash sets the "state_hash" field.
+func (_c *FederationStateCreate) SetStateHash(v string) *FederationStateCreate {
+	_c.mutation.SetStateHash(v)
+	return _c
+}
+
+// SetProvider sets the "provider" field.
+func (_c *FederationStateCreate) SetProvider(v string) *FederationStateCreate {
+	_c.mutation.SetProvider(v)
+	return _c
+}
+
+// SetNonce sets the "nonce" field.
+func (_c *FederationStateCreate) SetNonce(v string) *FederationStateCreate {
+	_c.mutation.SetNonce(v)
+	return _c
+}
+
+// SetCodeVerifier sets the "code_verifier" field.
+func (_c *FederationStateCreate) SetCodeVerifier(v string) *FederationStateCreate {
+	_c.mutation.SetCodeVerifier(v)
+	return _c
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *FederationStateCreate) SetUserID(v int) *FederationStateCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_c *FederationStateCreate) SetNillableUserID(v *int) *FederationStateCreate {
+	if v != nil {
+		_c.SetUserID(*v)
+	}
+	return _c
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_c *FederationStateCreate) SetExpiresAt(v time.Time) *FederationStateCreate {
+	_c.mutation.SetExpiresAt(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *FederationStateCreate) SetCreatedAt(v time.Time) *FederationStateCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *FederationStateCreate) SetNillableCreatedAt(v *time.Time) *FederationStateCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *FederationStateCreate) SetUser(v *User) *FederationStateCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// Mutation returns the FederationStateMutation object of the builder.
+func (_c *FederationStateCreate) Mutation() *FederationStateMutation {
+	return _c.mutation
+}
+
+// Save creates the FederationState in the database.
+func (_c *FederationStateCreate) Save(ctx context.Context) (*FederationState, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *FederationStateCreate) SaveX(ctx context.Context) *FederationState {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FederationStateCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FederationStateCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *FederationStateCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := federationstate.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *FederationStateCreate) check() error {
+	if _, ok := _c.mutation.StateHash(); !ok {
+		return &ValidationError{Name: "state_hash", err: errors.New(`ent: missing required field "FederationState.state_hash"`)}
+	}
+	if v, ok := _c.mutation.StateHash(); ok {
+		if err := federationstate.StateHashValidator(v); err != nil {
+			return &ValidationError{Name: "state_hash", err: fmt.Errorf(`ent: validator failed for field "FederationState.state_hash": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Provider(); !ok {
+		return &ValidationError{Name: "provider", err: errors.New(`ent: missing required field "FederationState.provider"`)}
+	}
+	if v, ok := _c.mutation.Provider(); ok {
+		if err := federationstate.ProviderValidator(v); err != nil {
+			return &ValidationError{Name: "provider", err: fmt.Errorf(`ent: validator failed for field "FederationState.provider": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Nonce(); !ok {
+		return &ValidationError{Name: "nonce", err: errors.New(`ent: missing required field "FederationState.nonce"`)}
+	}
+	if v, ok := _c.mutation.Nonce(); ok {
+		if err := federationstate.NonceValidator(v); err != nil {
+			return &ValidationError{Name: "nonce", err: fmt.Errorf(`ent: validator failed for field "FederationState.nonce": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.CodeVerifier(); !ok {
+		return &ValidationError{Name: "code_verifier", err: errors.New(`ent: missing required field "FederationState.code_verifier"`)}
+	}
+	if v, ok := _c.mutation.CodeVerifier(); ok {
+		if err := federationstate.CodeVerifierValidator(v); err != nil {
+			return &ValidationError{Name: "code_verifier", err: fmt.Errorf(`ent: validator failed for field "FederationState.code_verifier": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "FederationState.expires_at"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "FederationState.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *FederationStateCreate) sqlSave(ctx context.Context) (*FederationState, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *FederationStateCreate) createSpec() (*FederationState, *sqlgraph.CreateSpec) {
+	var (
+		_node = &FederationState{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(federationstate.Table, sqlgraph.NewFieldSpec(federationstate.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.StateHash(); ok {
+		_spec.SetField(federationstate.FieldStateHash, field.TypeString, value)
+		_node.StateHash = value
+	}
+	if value, ok := _c.mutation.Provider(); ok {
+		_spec.SetField(federationstate.FieldProvider, field.TypeString, value)
+		_node.Provider = value
+	}
+	if value, ok := _c.mutation.Nonce(); ok {
+		_spec.SetField(federationstate.FieldNonce, field.TypeString, value)
+		_node.Nonce = value
+	}
+	if value, ok := _c.mutation.CodeVerifier(); ok {
+		_spec.SetField(federationstate.FieldCodeVerifier, field.TypeString, value)
+		_node.CodeVerifier = value
+	}
+	if value, ok := _c.mutation.ExpiresAt(); ok {
+		_spec.SetField(federationstate.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(federationstate.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   federationstate.UserTable,
+			Columns: []string{federationstate.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// FederationStateCreateBulk is the builder for creating many FederationState entities in bulk.
+type FederationStateCreateBulk struct {
+	config
+	err      error
+	builders []*FederationStateCreate
+}
+
+// Save creates the FederationState entities in the database.
+func (_c *FederationStateCreateBulk) Save(ctx context.Context) ([]*FederationState, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*FederationState, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*FederationStateMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *FederationStateCreateBulk) SaveX(ctx context.Context) []*FederationState {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *FederationStateCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *FederationStateCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
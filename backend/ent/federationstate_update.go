@@ -0,0 +1,523 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/federationstate"
+	"app/ent/predicate"
+	"app/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// FederationStateUpdate is the builder for updating FederationState entities.
+type FederationStateUpdate struct {
+	config
+	hooks    []Hook
+	mutation *FederationStateMutation
+}
+
+// Where appends a list predicates to the FederationStateUpdate builder.
+func (_u *FederationStateUpdate) Where(ps ...predicate.FederationState) *FederationStateUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetStateHash sets the "state_hash" field.
+func (_u *FederationStateUpdate) SetStateHash(v string) *FederationStateUpdate {
+	_u.mutation.SetStateHash(v)
+	return _u
+}
+
+// SetNillableStateHash sets the "state_hash" field if the given value is not nil.
+func (_u *FederationStateUpdate) SetNillableStateHash(v *string) *FederationStateUpdate {
+	if v != nil {
+		_u.SetStateHash(*v)
+	}
+	return _u
+}
+
+// SetProvider sets the "provider" field.
+func (_u *FederationStateUpdate) SetProvider(v string) *FederationStateUpdate {
+	_u.mutation.SetProvider(v)
+	return _u
+}
+
+// SetNillableProvider sets the "provider" field if the given value is not nil.
+func (_u *FederationStateUpdate) SetNillableProvider(v *string) *FederationStateUpdate {
+	if v != nil {
+		_u.SetProvider(*v)
+	}
+	return _u
+}
+
+// SetNonce sets the "nonce" field.
+func (_u *FederationStateUpdate) SetNonce(v string) *FederationStateUpdate {
+	_u.mutation.SetNonce(v)
+	return _u
+}
+
+// SetNillableNonce sets the "nonce" field if the given value is not nil.
+func (_u *FederationStateUpdate) SetNillableNonce(v *string) *FederationStateUpdate {
+	if v != nil {
+		_u.SetNonce(*v)
+	}
+	return _u
+}
+
+// SetCodeVerifier sets the "code_verifier" field.
+func (_u *FederationStateUpdate) SetCodeVerifier(v string) *FederationStateUpdate {
+	_u.mutation.SetCodeVerifier(v)
+	return _u
+}
+
+// SetNillableCodeVerifier sets the "code_verifier" field if the given value is not nil.
+func (_u *FederationStateUpdate) SetNillableCodeVerifier(v *string) *FederationStateUpdate {
+	if v != nil {
+		_u.SetCodeVerifier(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *FederationStateUpdate) SetUserID(v int) *FederationStateUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *FederationStateUpdate) SetNillableUserID(v *int) *FederationStateUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *FederationStateUpdate) ClearUserID() *FederationStateUpdate {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *FederationStateUpdate) SetExpiresAt(v time.Time) *FederationStateUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *FederationStateUpdate) SetNillableExpiresAt(v *time.Time) *FederationStateUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *FederationStateUpdate) SetUser(v *User) *FederationStateUpdate {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the FederationStateMutation object of the builder.
+func (_u *FederationStateUpdate) Mutation() *FederationStateMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *FederationStateUpdate) ClearUser() *FederationStateUpdate {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *FederationStateUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FederationStateUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *FederationStateUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FederationStateUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FederationStateUpdate) check() error {
+	if v, ok := _u.mutation.StateHash(); ok {
+		if err := federationstate.StateHashValidator(v); err != nil {
+			return &ValidationError{Name: "state_hash", err: fmt.Errorf(`ent: validator failed for field "FederationState.state_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Provider(); ok {
+		if err := federationstate.ProviderValidator(v); err != nil {
+			return &ValidationError{Name: "provider", err: fmt.Errorf(`ent: validator failed for field "FederationState.provider": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Nonce(); ok {
+		if err := federationstate.NonceValidator(v); err != nil {
+			return &ValidationError{Name: "nonce", err: fmt.Errorf(`ent: validator failed for field "FederationState.nonce": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.CodeVerifier(); ok {
+		if err := federationstate.CodeVerifierValidator(v); err != nil {
+			return &ValidationError{Name: "code_verifier", err: fmt.Errorf(`ent: validator failed for field "FederationState.code_verifier": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FederationStateUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(federationstate.Table, federationstate.Columns, sqlgraph.NewFieldSpec(federationstate.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.StateHash(); ok {
+		_spec.SetField(federationstate.FieldStateHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Provider(); ok {
+		_spec.SetField(federationstate.FieldProvider, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Nonce(); ok {
+		_spec.SetField(federationstate.FieldNonce, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.CodeVerifier(); ok {
+		_spec.SetField(federationstate.FieldCodeVerifier, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(federationstate.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   federationstate.UserTable,
+			Columns: []string{federationstate.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   federationstate.UserTable,
+			Columns: []string{federationstate.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{federationstate.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// FederationStateUpdateOne is the builder for updating a single FederationState entity.
+type FederationStateUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *FederationStateMutation
+}
+
+// SetStateHash sets the "state_hash" field.
+func (_u *FederationStateUpdateOne) SetStateHash(v string) *FederationStateUpdateOne {
+	_u.mutation.SetStateHash(v)
+	return _u
+}
+
+// SetNillableStateHash sets the "state_hash" field if the given value is not nil.
+func (_u *FederationStateUpdateOne) SetNillableStateHash(v *string) *FederationStateUpdateOne {
+	if v != nil {
+		_u.SetStateHash(*v)
+	}
+	return _u
+}
+
+// SetProvider sets the "provider" field.
+func (_u *FederationStateUpdateOne) SetProvider(v string) *FederationStateUpdateOne {
+	_u.mutation.SetProvider(v)
+	return _u
+}
+
+// SetNillableProvider sets the "provider" field if the given value is not nil.
+func (_u *FederationStateUpdateOne) SetNillableProvider(v *string) *FederationStateUpdateOne {
+	if v != nil {
+		_u.SetProvider(*v)
+	}
+	return _u
+}
+
+// SetNonce sets the "nonce" field.
+func (_u *FederationStateUpdateOne) SetNonce(v string) *FederationStateUpdateOne {
+	_u.mutation.SetNonce(v)
+	return _u
+}
+
+// SetNillableNonce sets the "nonce" field if the given value is not nil.
+func (_u *FederationStateUpdateOne) SetNillableNonce(v *string) *FederationStateUpdateOne {
+	if v != nil {
+		_u.SetNonce(*v)
+	}
+	return _u
+}
+
+// SetCodeVerifier sets the "code_verifier" field.
+func (_u *FederationStateUpdateOne) SetCodeVerifier(v string) *FederationStateUpdateOne {
+	_u.mutation.SetCodeVerifier(v)
+	return _u
+}
+
+// SetNillableCodeVerifier sets the "code_verifier" field if the given value is not nil.
+func (_u *FederationStateUpdateOne) SetNillableCodeVerifier(v *string) *FederationStateUpdateOne {
+	if v != nil {
+		_u.SetCodeVerifier(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *FederationStateUpdateOne) SetUserID(v int) *FederationStateUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *FederationStateUpdateOne) SetNillableUserID(v *int) *FederationStateUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *FederationStateUpdateOne) ClearUserID() *FederationStateUpdateOne {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *FederationStateUpdateOne) SetExpiresAt(v time.Time) *FederationStateUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *FederationStateUpdateOne) SetNillableExpiresAt(v *time.Time) *FederationStateUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *FederationStateUpdateOne) SetUser(v *User) *FederationStateUpdateOne {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the FederationStateMutation object of the builder.
+func (_u *FederationStateUpdateOne) Mutation() *FederationStateMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *FederationStateUpdateOne) ClearUser() *FederationStateUpdateOne {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Where appends a list predicates to the FederationStateUpdate builder.
+func (_u *FederationStateUpdateOne) Where(ps ...predicate.FederationState) *FederationStateUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *FederationStateUpdateOne) Select(field string, fields ...string) *FederationStateUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated FederationState entity.
+func (_u *FederationStateUpdateOne) Save(ctx context.Context) (*FederationState, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *FederationStateUpdateOne) SaveX(ctx context.Context) *FederationState {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *FederationStateUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *FederationStateUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *FederationStateUpdateOne) check() error {
+	if v, ok := _u.mutation.StateHash(); ok {
+		if err := federationstate.StateHashValidator(v); err != nil {
+			return &ValidationError{Name: "state_hash", err: fmt.Errorf(`ent: validator failed for field "FederationState.state_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Provider(); ok {
+		if err := federationstate.ProviderValidator(v); err != nil {
+			return &ValidationError{Name: "provider", err: fmt.Errorf(`ent: validator failed for field "FederationState.provider": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Nonce(); ok {
+		if err := federationstate.NonceValidator(v); err != nil {
+			return &ValidationError{Name: "nonce", err: fmt.Errorf(`ent: validator failed for field "FederationState.nonce": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.CodeVerifier(); ok {
+		if err := federationstate.CodeVerifierValidator(v); err != nil {
+			return &ValidationError{Name: "code_verifier", err: fmt.Errorf(`ent: validator failed for field "FederationState.code_verifier": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *FederationStateUpdateOne) sqlSave(ctx context.Context) (_node *FederationState, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(federationstate.Table, federationstate.Columns, sqlgraph.NewFieldSpec(federationstate.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "FederationState.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, federationstate.FieldID)
+		for _, f := range fields {
+			if !federationstate.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != federationstate.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.StateHash(); ok {
+		_spec.SetField(federationstate.FieldStateHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Provider(); ok {
+		_spec.SetField(federationstate.FieldProvider, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Nonce(); ok {
+		_spec.SetField(federationstate.FieldNonce, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.CodeVerifier(); ok {
+		_spec.SetField(federationstate.FieldCodeVerifier, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(federationstate.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   federationstate.UserTable,
+			Columns: []string{federationstate.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   federationstate.UserTable,
+			Columns: []string{federationstate.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &FederationState{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{federationstate.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,585 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/magiclinktoken"
+	"app/ent/predicate"
+	"app/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// MagicLinkTokenUpdate is the builder for updating MagicLinkToken entities.
+type MagicLinkTokenUpdate struct {
+	config
+	hooks    []Hook
+	mutation *MagicLinkTokenMutation
+}
+
+// Where appends a list predicates to the MagicLinkTokenUpdate builder.
+func (_u *MagicLinkTokenUpdate) Where(ps ...predicate.MagicLinkToken) *MagicLinkTokenUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (_u *MagicLinkTokenUpdate) SetTokenHash(v string) *MagicLinkTokenUpdate {
+	_u.mutation.SetTokenHash(v)
+	return _u
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableTokenHash(v *string) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetTokenHash(*v)
+	}
+	return _u
+}
+
+// SetCodeHash sets the "code_hash" field.
+func (_u *MagicLinkTokenUpdate) SetCodeHash(v string) *MagicLinkTokenUpdate {
+	_u.mutation.SetCodeHash(v)
+	return _u
+}
+
+// SetNillableCodeHash sets the "code_hash" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableCodeHash(v *string) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetCodeHash(*v)
+	}
+	return _u
+}
+
+// SetEmail sets the "email" field.
+func (_u *MagicLinkTokenUpdate) SetEmail(v string) *MagicLinkTokenUpdate {
+	_u.mutation.SetEmail(v)
+	return _u
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableEmail(v *string) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetEmail(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *MagicLinkTokenUpdate) SetUserID(v int) *MagicLinkTokenUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableUserID(v *int) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *MagicLinkTokenUpdate) ClearUserID() *MagicLinkTokenUpdate {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetAttempts sets the "attempts" field.
+func (_u *MagicLinkTokenUpdate) SetAttempts(v int) *MagicLinkTokenUpdate {
+	_u.mutation.ResetAttempts()
+	_u.mutation.SetAttempts(v)
+	return _u
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableAttempts(v *int) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetAttempts(*v)
+	}
+	return _u
+}
+
+// AddAttempts adds value to the "attempts" field.
+func (_u *MagicLinkTokenUpdate) AddAttempts(v int) *MagicLinkTokenUpdate {
+	_u.mutation.AddAttempts(v)
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *MagicLinkTokenUpdate) SetExpiresAt(v time.Time) *MagicLinkTokenUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableExpiresAt(v *time.Time) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// SetUsedAt sets the "used_at" field.
+func (_u *MagicLinkTokenUpdate) SetUsedAt(v time.Time) *MagicLinkTokenUpdate {
+	_u.mutation.SetUsedAt(v)
+	return _u
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdate) SetNillableUsedAt(v *time.Time) *MagicLinkTokenUpdate {
+	if v != nil {
+		_u.SetUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (_u *MagicLinkTokenUpdate) ClearUsedAt() *MagicLinkTokenUpdate {
+	_u.mutation.ClearUsedAt()
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *MagicLinkTokenUpdate) SetUser(v *User) *MagicLinkTokenUpdate {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the MagicLinkTokenMutation object of the builder.
+func (_u *MagicLinkTokenUpdate) Mutation() *MagicLinkTokenMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *MagicLinkTokenUpdate) ClearUser() *MagicLinkTokenUpdate {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *MagicLinkTokenUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *MagicLinkTokenUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *MagicLinkTokenUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *MagicLinkTokenUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *MagicLinkTokenUpdate) check() error {
+	if v, ok := _u.mutation.TokenHash(); ok {
+		if err := magiclinktoken.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "MagicLinkToken.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.CodeHash(); ok {
+		if err := magiclinktoken.CodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "code_hash", err: fmt.Errorf(`ent: validator failed for field "MagicLinkToken.code_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Email(); ok {
+		if err := magiclinktoken.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "MagicLinkToken.email": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *MagicLinkTokenUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(magiclinktoken.Table, magiclinktoken.Columns, sqlgraph.NewFieldSpec(magiclinktoken.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.TokenHash(); ok {
+		_spec.SetField(magiclinktoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.CodeHash(); ok {
+		_spec.SetField(magiclinktoken.FieldCodeHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Email(); ok {
+		_spec.SetField(magiclinktoken.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Attempts(); ok {
+		_spec.SetField(magiclinktoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAttempts(); ok {
+		_spec.AddField(magiclinktoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(magiclinktoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.UsedAt(); ok {
+		_spec.SetField(magiclinktoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.UsedAtCleared() {
+		_spec.ClearField(magiclinktoken.FieldUsedAt, field.TypeTime)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   magiclinktoken.UserTable,
+			Columns: []string{magiclinktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   magiclinktoken.UserTable,
+			Columns: []string{magiclinktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{magiclinktoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// MagicLinkTokenUpdateOne is the builder for updating a single MagicLinkToken entity.
+type MagicLinkTokenUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *MagicLinkTokenMutation
+}
+
+// SetTokenHash sets the "token_hash" field.
+func (_u *MagicLinkTokenUpdateOne) SetTokenHash(v string) *MagicLinkTokenUpdateOne {
+	_u.mutation.SetTokenHash(v)
+	return _u
+}
+
+// SetNillableTokenHash sets the "token_hash" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableTokenHash(v *string) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetTokenHash(*v)
+	}
+	return _u
+}
+
+// SetCodeHash sets the "code_hash" field.
+func (_u *MagicLinkTokenUpdateOne) SetCodeHash(v string) *MagicLinkTokenUpdateOne {
+	_u.mutation.SetCodeHash(v)
+	return _u
+}
+
+// SetNillableCodeHash sets the "code_hash" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableCodeHash(v *string) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetCodeHash(*v)
+	}
+	return _u
+}
+
+// SetEmail sets the "email" field.
+func (_u *MagicLinkTokenUpdateOne) SetEmail(v string) *MagicLinkTokenUpdateOne {
+	_u.mutation.SetEmail(v)
+	return _u
+}
+
+// SetNillableEmail sets the "email" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableEmail(v *string) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetEmail(*v)
+	}
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *MagicLinkTokenUpdateOne) SetUserID(v int) *MagicLinkTokenUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableUserID(v *int) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *MagicLinkTokenUpdateOne) ClearUserID() *MagicLinkTokenUpdateOne {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetAttempts sets the "attempts" field.
+func (_u *MagicLinkTokenUpdateOne) SetAttempts(v int) *MagicLinkTokenUpdateOne {
+	_u.mutation.ResetAttempts()
+	_u.mutation.SetAttempts(v)
+	return _u
+}
+
+// SetNillableAttempts sets the "attempts" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableAttempts(v *int) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetAttempts(*v)
+	}
+	return _u
+}
+
+// AddAttempts adds value to the "attempts" field.
+func (_u *MagicLinkTokenUpdateOne) AddAttempts(v int) *MagicLinkTokenUpdateOne {
+	_u.mutation.AddAttempts(v)
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *MagicLinkTokenUpdateOne) SetExpiresAt(v time.Time) *MagicLinkTokenUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableExpiresAt(v *time.Time) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// SetUsedAt sets the "used_at" field.
+func (_u *MagicLinkTokenUpdateOne) SetUsedAt(v time.Time) *MagicLinkTokenUpdateOne {
+	_u.mutation.SetUsedAt(v)
+	return _u
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (_u *MagicLinkTokenUpdateOne) SetNillableUsedAt(v *time.Time) *MagicLinkTokenUpdateOne {
+	if v != nil {
+		_u.SetUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (_u *MagicLinkTokenUpdateOne) ClearUsedAt() *MagicLinkTokenUpdateOne {
+	_u.mutation.ClearUsedAt()
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *MagicLinkTokenUpdateOne) SetUser(v *User) *MagicLinkTokenUpdateOne {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the MagicLinkTokenMutation object of the builder.
+func (_u *MagicLinkTokenUpdateOne) Mutation() *MagicLinkTokenMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *MagicLinkTokenUpdateOne) ClearUser() *MagicLinkTokenUpdateOne {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Where appends a list predicates to the MagicLinkTokenUpdate builder.
+func (_u *MagicLinkTokenUpdateOne) Where(ps ...predicate.MagicLinkToken) *MagicLinkTokenUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *MagicLinkTokenUpdateOne) Select(field string, fields ...string) *MagicLinkTokenUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated MagicLinkToken entity.
+func (_u *MagicLinkTokenUpdateOne) Save(ctx context.Context) (*MagicLinkToken, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *MagicLinkTokenUpdateOne) SaveX(ctx context.Context) *MagicLinkToken {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *MagicLinkTokenUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *MagicLinkTokenUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *MagicLinkTokenUpdateOne) check() error {
+	if v, ok := _u.mutation.TokenHash(); ok {
+		if err := magiclinktoken.TokenHashValidator(v); err != nil {
+			return &ValidationError{Name: "token_hash", err: fmt.Errorf(`ent: validator failed for field "MagicLinkToken.token_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.CodeHash(); ok {
+		if err := magiclinktoken.CodeHashValidator(v); err != nil {
+			return &ValidationError{Name: "code_hash", err: fmt.Errorf(`ent: validator failed for field "MagicLinkToken.code_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Email(); ok {
+		if err := magiclinktoken.EmailValidator(v); err != nil {
+			return &ValidationError{Name: "email", err: fmt.Errorf(`ent: validator failed for field "MagicLinkToken.email": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *MagicLinkTokenUpdateOne) sqlSave(ctx context.Context) (_node *MagicLinkToken, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(magiclinktoken.Table, magiclinktoken.Columns, sqlgraph.NewFieldSpec(magiclinktoken.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "MagicLinkToken.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, magiclinktoken.FieldID)
+		for _, f := range fields {
+			if !magiclinktoken.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != magiclinktoken.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.TokenHash(); ok {
+		_spec.SetField(magiclinktoken.FieldTokenHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.CodeHash(); ok {
+		_spec.SetField(magiclinktoken.FieldCodeHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Email(); ok {
+		_spec.SetField(magiclinktoken.FieldEmail, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Attempts(); ok {
+		_spec.SetField(magiclinktoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedAttempts(); ok {
+		_spec.AddField(magiclinktoken.FieldAttempts, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(magiclinktoken.FieldExpiresAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.UsedAt(); ok {
+		_spec.SetField(magiclinktoken.FieldUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.UsedAtCleared() {
+		_spec.ClearField(magiclinktoken.FieldUsedAt, field.TypeTime)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   magiclinktoken.UserTable,
+			Columns: []string{magiclinktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   magiclinktoken.UserTable,
+			Columns: []string{magiclinktoken.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &MagicLinkToken{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{magiclinktoken.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
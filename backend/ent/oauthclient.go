@@ -0,0 +1,222 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/oauthclient"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// OAuthClient is the model entity for the OAuthClient schema.
+type OAuthClient struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// ClientID holds the value of the "client_id" field.
+	ClientID string `json:"client_id,omitempty"`
+	// SecretHash holds the value of the "secret_hash" field.
+	SecretHash string `json:"-"`
+	// Name holds the value of the "name" field.
+	Name string `json:"name,omitempty"`
+	// RedirectUris holds the value of the "redirect_uris" field.
+	RedirectUris []string `json:"redirect_uris,omitempty"`
+	// Scopes holds the value of the "scopes" field.
+	Scopes []string `json:"scopes,omitempty"`
+	// SkipConsent holds the value of the "skip_consent" field.
+	SkipConsent bool `json:"skip_consent,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the OAuthClientQuery when eager-loading is set.
+	Edges        OAuthClientEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// OAuthClientEdges holds the relations/edges for other nodes in the graph.
+type OAuthClientEdges struct {
+	// AuthorizationCodes holds the value of the authorization_codes edge.
+	AuthorizationCodes []*AuthorizationCode `json:"authorization_codes,omitempty"`
+	// Consents holds the value of the consents edge.
+	Consents []*OAuthConsent `json:"consents,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [2]bool
+}
+
+// AuthorizationCodesOrErr returns the AuthorizationCodes value or an error if the edge
+// was not loaded in eager-loading.
+func (e OAuthClientEdges) AuthorizationCodesOrErr() ([]*AuthorizationCode, error) {
+	if e.loadedTypes[0] {
+		return e.AuthorizationCodes, nil
+	}
+	return nil, &NotLoadedError{edge: "authorization_codes"}
+}
+
+// ConsentsOrErr returns the Consents value or an error if the edge
+// was not loaded in eager-loading.
+func (e OAuthClientEdges) ConsentsOrErr() ([]*OAuthConsent, error) {
+	if e.loadedTypes[1] {
+		return e.Consents, nil
+	}
+	return nil, &NotLoadedError{edge: "consents"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*OAuthClient) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case oauthclient.FieldRedirectUris, oauthclient.FieldScopes:
+			values[i] = new([]byte)
+		case oauthclient.FieldSkipConsent:
+			values[i] = new(sql.NullBool)
+		case oauthclient.FieldID:
+			values[i] = new(sql.NullInt64)
+		case oauthclient.FieldClientID, oauthclient.FieldSecretHash, oauthclient.FieldName:
+			values[i] = new(sql.NullString)
+		case oauthclient.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the OAuthClient fields.
+func (_m *OAuthClient) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case oauthclient.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int(value.Int64)
+		case oauthclient.FieldClientID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field client_id", values[i])
+			} else if value.Valid {
+				_m.ClientID = value.String
+			}
+		case oauthclient.FieldSecretHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field secret_hash", values[i])
+			} else if value.Valid {
+				_m.SecretHash = value.String
+			}
+		case oauthclient.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				_m.Name = value.String
+			}
+		case oauthclient.FieldRedirectUris:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field redirect_uris", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.RedirectUris); err != nil {
+					return fmt.Errorf("unmarshal field redirect_uris: %w", err)
+				}
+			}
+		case oauthclient.FieldScopes:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field scopes", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Scopes); err != nil {
+					return fmt.Errorf("unmarshal field scopes: %w", err)
+				}
+			}
+		case oauthclient.FieldSkipConsent:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field skip_consent", values[i])
+			} else if value.Valid {
+				_m.SkipConsent = value.Bool
+			}
+		case oauthclient.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the OAuthClient.
+// This includes values selected through modifiers, order, etc.
+func (_m *OAuthClient) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// QueryAuthorizationCodes queries the "authorization_codes" edge of the OAuthClient entity.
+func (_m *OAuthClient) QueryAuthorizationCodes() *AuthorizationCodeQuery {
+	return NewOAuthClientClient(_m.config).QueryAuthorizationCodes(_m)
+}
+
+// QueryConsents queries the "consents" edge of the OAuthClient entity.
+func (_m *OAuthClient) QueryConsents() *OAuthConsentQuery {
+	return NewOAuthClientClient(_m.config).QueryConsents(_m)
+}
+
+// Update returns a builder for updating this OAuthClient.
+// Note that you need to call OAuthClient.Unwrap() before calling this method if this OAuthClient
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *OAuthClient) Update() *OAuthClientUpdateOne {
+	return NewOAuthClientClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the OAuthClient entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *OAuthClient) Unwrap() *OAuthClient {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: OAuthClient is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *OAuthClient) String() string {
+	var builder strings.Builder
+	builder.WriteString("OAuthClient(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("client_id=")
+	builder.WriteString(_m.ClientID)
+	builder.WriteString(", ")
+	builder.WriteString("secret_hash=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("name=")
+	builder.WriteString(_m.Name)
+	builder.WriteString(", ")
+	builder.WriteString("redirect_uris=")
+	builder.WriteString(fmt.Sprintf("%v", _m.RedirectUris))
+	builder.WriteString(", ")
+	builder.WriteString("scopes=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Scopes))
+	builder.WriteString(", ")
+	builder.WriteString("skip_consent=")
+	builder.WriteString(fmt.Sprintf("%v", _m.SkipConsent))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// OAuthClients is a parsable slice of OAuthClient.
+type OAuthClients []*OAuthClient
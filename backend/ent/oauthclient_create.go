@@ -0,0 +1,371 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/authorizationcode"
+	"app/ent/oauthclient"
+	"app/ent/oauthconsent"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// OAuthClientCreate is the builder for creating a OAuthClient entity.
+type OAuthClientCreate struct {
+	config
+	mutation *OAuthClientMutation
+	hooks    []Hook
+}
+
+// SetClientID sets the "client_id" field.
+func (_c *OAuthClientCreate) SetClientID(v string) *OAuthClientCreate {
+	_c.mutation.SetClientID(v)
+	return _c
+}
+
+// SetSecretHash sets the "secret_hash" field.
+func (_c *OAuthClientCreate) SetSecretHash(v string) *OAuthClientCreate {
+	_c.mutation.SetSecretHash(v)
+	return _c
+}
+
+// SetNillableSecretHash sets the "secret_hash" field if the given value is not nil.
+func (_c *OAuthClientCreate) SetNillableSecretHash(v *string) *OAuthClientCreate {
+	if v != nil {
+		_c.SetSecretHash(*v)
+	}
+	return _c
+}
+
+// SetName sets the "name" field.
+func (_c *OAuthClientCreate) SetName(v string) *OAuthClientCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetRedirectUris sets the "redirect_uris" field.
+func (_c *OAuthClientCreate) SetRedirectUris(v []string) *OAuthClientCreate {
+	_c.mutation.SetRedirectUris(v)
+	return _c
+}
+
+// SetScopes sets the "scopes" field.
+func (_c *OAuthClientCreate) SetScopes(v []string) *OAuthClientCreate {
+	_c.mutation.SetScopes(v)
+	return _c
+}
+
+// SetSkipConsent sets the "skip_consent" field.
+func (_c *OAuthClientCreate) SetSkipConsent(v bool) *OAuthClientCreate {
+	_c.mutation.SetSkipConsent(v)
+	return _c
+}
+
+// SetNillableSkipConsent sets the "skip_consent" field if the given value is not nil.
+func (_c *OAuthClientCreate) SetNillableSkipConsent(v *bool) *OAuthClientCreate {
+	if v != nil {
+		_c.SetSkipConsent(*v)
+	}
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *OAuthClientCreate) SetCreatedAt(v time.Time) *OAuthClientCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *OAuthClientCreate) SetNillableCreatedAt(v *time.Time) *OAuthClientCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// AddAuthorizationCodeIDs adds the "authorization_codes" edge to the AuthorizationCode entity by IDs.
+func (_c *OAuthClientCreate) AddAuthorizationCodeIDs(ids ...int) *OAuthClientCreate {
+	_c.mutation.AddAuthorizationCodeIDs(ids...)
+	return _c
+}
+
+// AddAuthorizationCodes adds the "authorization_codes" edges to the AuthorizationCode entity.
+func (_c *OAuthClientCreate) AddAuthorizationCodes(v ...*AuthorizationCode) *OAuthClientCreate {
+	ids := make([]int, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddAuthorizationCodeIDs(ids...)
+}
+
+// AddConsentIDs adds the "consents" edge to the OAuthConsent entity by IDs.
+func (_c *OAuthClientCreate) AddConsentIDs(ids ...int) *OAuthClientCreate {
+	_c.mutation.AddConsentIDs(ids...)
+	return _c
+}
+
+// AddConsents adds the "consents" edges to the OAuthConsent entity.
+func (_c *OAuthClientCreate) AddConsents(v ...*OAuthConsent) *OAuthClientCreate {
+	ids := make([]int, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddConsentIDs(ids...)
+}
+
+// Mutation returns the OAuthClientMutation object of the builder.
+func (_c *OAuthClientCreate) Mutation() *OAuthClientMutation {
+	return _c.mutation
+}
+
+// Save creates the OAuthClient in the database.
+func (_c *OAuthClientCreate) Save(ctx context.Context) (*OAuthClient, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *OAuthClientCreate) SaveX(ctx context.Context) *OAuthClient {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *OAuthClientCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *OAuthClientCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *OAuthClientCreate) defaults() {
+	if _, ok := _c.mutation.SkipConsent(); !ok {
+		v := oauthclient.DefaultSkipConsent
+		_c.mutation.SetSkipConsent(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := oauthclient.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *OAuthClientCreate) check() error {
+	if _, ok := _c.mutation.ClientID(); !ok {
+		return &ValidationError{Name: "client_id", err: errors.New(`ent: missing required field "OAuthClient.client_id"`)}
+	}
+	if v, ok := _c.mutation.ClientID(); ok {
+		if err := oauthclient.ClientIDValidator(v); err != nil {
+			return &ValidationError{Name: "client_id", err: fmt.Errorf(`ent: validator failed for field "OAuthClient.client_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "OAuthClient.name"`)}
+	}
+	if v, ok := _c.mutation.Name(); ok {
+		if err := oauthclient.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "OAuthClient.name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.RedirectUris(); !ok {
+		return &ValidationError{Name: "redirect_uris", err: errors.New(`ent: missing required field "OAuthClient.redirect_uris"`)}
+	}
+	if _, ok := _c.mutation.Scopes(); !ok {
+		return &ValidationError{Name: "scopes", err: errors.New(`ent: missing required field "OAuthClient.scopes"`)}
+	}
+	if _, ok := _c.mutation.SkipConsent(); !ok {
+		return &ValidationError{Name: "skip_consent", err: errors.New(`ent: missing required field "OAuthClient.skip_consent"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "OAuthClient.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *OAuthClientCreate) sqlSave(ctx context.Context) (*OAuthClient, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *OAuthClientCreate) createSpec() (*OAuthClient, *sqlgraph.CreateSpec) {
+	var (
+		_node = &OAuthClient{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(oauthclient.Table, sqlgraph.NewFieldSpec(oauthclient.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.ClientID(); ok {
+		_spec.SetField(oauthclient.FieldClientID, field.TypeString, value)
+		_node.ClientID = value
+	}
+	if value, ok := _c.mutation.SecretHash(); ok {
+		_spec.SetField(oauthclient.FieldSecretHash, field.TypeString, value)
+		_node.SecretHash = value
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(oauthclient.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.RedirectUris(); ok {
+		_spec.SetField(oauthclient.FieldRedirectUris, field.TypeJSON, value)
+		_node.RedirectUris = value
+	}
+	if value, ok := _c.mutation.Scopes(); ok {
+		_spec.SetField(oauthclient.FieldScopes, field.TypeJSON, value)
+		_node.Scopes = value
+	}
+	if value, ok := _c.mutation.SkipConsent(); ok {
+		_spec.SetField(oauthclient.FieldSkipConsent, field.TypeBool, value)
+		_node.SkipConsent = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(oauthclient.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := _c.mutation.AuthorizationCodesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   oauthclient.AuthorizationCodesTable,
+			Columns: []string{oauthclient.AuthorizationCodesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(authorizationcode.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.ConsentsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   oauthclient.ConsentsTable,
+			Columns: []string{oauthclient.ConsentsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(oauthconsent.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// OAuthClientCreateBulk is the builder for creating many OAuthClient entities in bulk.
+type OAuthClientCreateBulk struct {
+	config
+	err      error
+	builders []*OAuthClientCreate
+}
+
+// Save creates the OAuthClient entities in the database.
+func (_c *OAuthClientCreateBulk) Save(ctx context.Context) ([]*OAuthClient, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*OAuthClient, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*OAuthClientMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *OAuthClientCreateBulk) SaveX(ctx context.Context) []*OAuthClient {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *OAuthClientCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *OAuthClientCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
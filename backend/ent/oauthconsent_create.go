@@ -0,0 +1,316 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/oauthclient"
+	"app/ent/oauthconsent"
+	"app/ent/user"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// OAuthConsentCreate is the builder for creating a OAuthConsent entity.
+type OAuthConsentCreate struct {
+	config
+	mutation *OAuthConsentMutation
+	hooks    []Hook
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *OAuthConsentCreate) SetUserID(v int) *OAuthConsentCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetOauthClientID sets the "oauth_client_id" field.
+func (_c *OAuthConsentCreate) SetOauthClientID(v int) *OAuthConsentCreate {
+	_c.mutation.SetOauthClientID(v)
+	return _c
+}
+
+// SetScopes sets the "scopes" field.
+func (_c *OAuthConsentCreate) SetScopes(v []string) *OAuthConsentCreate {
+	_c.mutation.SetScopes(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *OAuthConsentCreate) SetCreatedAt(v time.Time) *OAuthConsentCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *OAuthConsentCreate) SetNillableCreatedAt(v *time.Time) *OAuthConsentCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *OAuthConsentCreate) SetUpdatedAt(v time.Time) *OAuthConsentCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *OAuthConsentCreate) SetNillableUpdatedAt(v *time.Time) *OAuthConsentCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *OAuthConsentCreate) SetUser(v *User) *OAuthConsentCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// SetClientID sets the "client" edge to the OAuthClient entity by ID.
+func (_c *OAuthConsentCreate) SetClientID(id int) *OAuthConsentCreate {
+	_c.mutation.SetClientID(id)
+	return _c
+}
+
+// SetClient sets the "client" edge to the OAuthClient entity.
+func (_c *OAuthConsentCreate) SetClient(v *OAuthClient) *OAuthConsentCreate {
+	return _c.SetClientID(v.ID)
+}
+
+// Mutation returns the OAuthConsentMutation object of the builder.
+func (_c *OAuthConsentCreate) Mutation() *OAuthConsentMutation {
+	return _c.mutation
+}
+
+// Save creates the OAuthConsent in the database.
+func (_c *OAuthConsentCreate) Save(ctx context.Context) (*OAuthConsent, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *OAuthConsentCreate) SaveX(ctx context.Context) *OAuthConsent {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *OAuthConsentCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *OAuthConsentCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *OAuthConsentCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := oauthconsent.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := oauthconsent.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *OAuthConsentCreate) check() error {
+	if _, ok := _c.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "OAuthConsent.user_id"`)}
+	}
+	if _, ok := _c.mutation.OauthClientID(); !ok {
+		return &ValidationError{Name: "oauth_client_id", err: errors.New(`ent: missing required field "OAuthConsent.oauth_client_id"`)}
+	}
+	if _, ok := _c.mutation.Scopes(); !ok {
+		return &ValidationError{Name: "scopes", err: errors.New(`ent: missing required field "OAuthConsent.scopes"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "OAuthConsent.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "OAuthConsent.updated_at"`)}
+	}
+	if len(_c.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "OAuthConsent.user"`)}
+	}
+	if len(_c.mutation.ClientIDs()) == 0 {
+		return &ValidationError{Name: "client", err: errors.New(`ent: missing required edge "OAuthConsent.client"`)}
+	}
+	return nil
+}
+
+func (_c *OAuthConsentCreate) sqlSave(ctx context.Context) (*OAuthConsent, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *OAuthConsentCreate) createSpec() (*OAuthConsent, *sqlgraph.CreateSpec) {
+	var (
+		_node = &OAuthConsent{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(oauthconsent.Table, sqlgraph.NewFieldSpec(oauthconsent.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.Scopes(); ok {
+		_spec.SetField(oauthconsent.FieldScopes, field.TypeJSON, value)
+		_node.Scopes = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(oauthconsent.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(oauthconsent.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   oauthconsent.UserTable,
+			Columns: []string{oauthconsent.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.ClientIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   oauthconsent.ClientTable,
+			Columns: []string{oauthconsent.ClientColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(oauthclient.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.OauthClientID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// OAuthConsentCreateBulk is the builder for creating many OAuthConsent entities in bulk.
+type OAuthConsentCreateBulk struct {
+	config
+	err      error
+	builders []*OAuthConsentCreate
+}
+
+// Save creates the OAuthConsent entities in the database.
+func (_c *OAuthConsentCreateBulk) Save(ctx context.Context) ([]*OAuthConsent, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*OAuthConsent, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*OAuthConsentMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *OAuthConsentCreateBulk) SaveX(ctx context.Context) []*OAuthConsent {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *OAuthConsentCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *OAuthConsentCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -33,6 +33,20 @@ func (_c *RefreshTokenCreate) SetUserID(v int) *RefreshTokenCreate {
 	return _c
 }
 
+// SetFamilyID sets the "family_id" field.
+func (_c *RefreshTokenCreate) SetFamilyID(v string) *RefreshTokenCreate {
+	_c.mutation.SetFamilyID(v)
+	return _c
+}
+
+// SetNillableFamilyID sets the "family_id" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableFamilyID(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetFamilyID(*v)
+	}
+	return _c
+}
+
 // SetExpiresAt sets the "expires_at" field.
 func (_c *RefreshTokenCreate) SetExpiresAt(v time.Time) *RefreshTokenCreate {
 	_c.mutation.SetExpiresAt(v)
@@ -67,6 +81,146 @@ func (_c *RefreshTokenCreate) SetNillableRevoked(v *bool) *RefreshTokenCreate {
 	return _c
 }
 
+// SetRevokedAt sets the "revoked_at" field.
+func (_c *RefreshTokenCreate) SetRevokedAt(v time.Time) *RefreshTokenCreate {
+	_c.mutation.SetRevokedAt(v)
+	return _c
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableRevokedAt(v *time.Time) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetRevokedAt(*v)
+	}
+	return _c
+}
+
+// SetRotationNonce sets the "rotation_nonce" field.
+func (_c *RefreshTokenCreate) SetRotationNonce(v string) *RefreshTokenCreate {
+	_c.mutation.SetRotationNonce(v)
+	return _c
+}
+
+// SetNillableRotationNonce sets the "rotation_nonce" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableRotationNonce(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetRotationNonce(*v)
+	}
+	return _c
+}
+
+// SetRotationClient sets the "rotation_client" field.
+func (_c *RefreshTokenCreate) SetRotationClient(v string) *RefreshTokenCreate {
+	_c.mutation.SetRotationClient(v)
+	return _c
+}
+
+// SetNillableRotationClient sets the "rotation_client" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableRotationClient(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetRotationClient(*v)
+	}
+	return _c
+}
+
+// SetSessionStartedAt sets the "session_started_at" field.
+func (_c *RefreshTokenCreate) SetSessionStartedAt(v time.Time) *RefreshTokenCreate {
+	_c.mutation.SetSessionStartedAt(v)
+	return _c
+}
+
+// SetNillableSessionStartedAt sets the "session_started_at" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableSessionStartedAt(v *time.Time) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetSessionStartedAt(*v)
+	}
+	return _c
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_c *RefreshTokenCreate) SetLastUsedAt(v time.Time) *RefreshTokenCreate {
+	_c.mutation.SetLastUsedAt(v)
+	return _c
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableLastUsedAt(v *time.Time) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetLastUsedAt(*v)
+	}
+	return _c
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (_c *RefreshTokenCreate) SetUserAgent(v string) *RefreshTokenCreate {
+	_c.mutation.SetUserAgent(v)
+	return _c
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableUserAgent(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetUserAgent(*v)
+	}
+	return _c
+}
+
+// SetIP sets the "ip" field.
+func (_c *RefreshTokenCreate) SetIP(v string) *RefreshTokenCreate {
+	_c.mutation.SetIP(v)
+	return _c
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableIP(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetIP(*v)
+	}
+	return _c
+}
+
+// SetLabel sets the "label" field.
+func (_c *RefreshTokenCreate) SetLabel(v string) *RefreshTokenCreate {
+	_c.mutation.SetLabel(v)
+	return _c
+}
+
+// SetNillableLabel sets the "label" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableLabel(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetLabel(*v)
+	}
+	return _c
+}
+
+// SetOauthClientID sets the "oauth_client_id" field.
+func (_c *RefreshTokenCreate) SetOauthClientID(v string) *RefreshTokenCreate {
+	_c.mutation.SetOauthClientID(v)
+	return _c
+}
+
+// SetNillableOauthClientID sets the "oauth_client_id" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableOauthClientID(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetOauthClientID(*v)
+	}
+	return _c
+}
+
+// SetScope sets the "scope" field.
+func (_c *RefreshTokenCreate) SetScope(v string) *RefreshTokenCreate {
+	_c.mutation.SetScope(v)
+	return _c
+}
+
+// SetNillableScope sets the "scope" field if the given value is not nil.
+func (_c *RefreshTokenCreate) SetNillableScope(v *string) *RefreshTokenCreate {
+	if v != nil {
+		_c.SetScope(*v)
+	}
+	return _c
+}
+
 // SetUser sets the "user" edge to the User entity.
 func (_c *RefreshTokenCreate) SetUser(v *User) *RefreshTokenCreate {
 	return _c.SetUserID(v.ID)
@@ -172,6 +326,10 @@ func (_c *RefreshTokenCreate) createSpec() (*RefreshToken, *sqlgraph.CreateSpec)
 		_spec.SetField(refreshtoken.FieldTokenHash, field.TypeString, value)
 		_node.TokenHash = value
 	}
+	if value, ok := _c.mutation.FamilyID(); ok {
+		_spec.SetField(refreshtoken.FieldFamilyID, field.TypeString, value)
+		_node.FamilyID = value
+	}
 	if value, ok := _c.mutation.ExpiresAt(); ok {
 		_spec.SetField(refreshtoken.FieldExpiresAt, field.TypeTime, value)
 		_node.ExpiresAt = value
@@ -184,6 +342,46 @@ func (_c *RefreshTokenCreate) createSpec() (*RefreshToken, *sqlgraph.CreateSpec)
 		_spec.SetField(refreshtoken.FieldRevoked, field.TypeBool, value)
 		_node.Revoked = value
 	}
+	if value, ok := _c.mutation.RevokedAt(); ok {
+		_spec.SetField(refreshtoken.FieldRevokedAt, field.TypeTime, value)
+		_node.RevokedAt = &value
+	}
+	if value, ok := _c.mutation.RotationNonce(); ok {
+		_spec.SetField(refreshtoken.FieldRotationNonce, field.TypeString, value)
+		_node.RotationNonce = value
+	}
+	if value, ok := _c.mutation.RotationClient(); ok {
+		_spec.SetField(refreshtoken.FieldRotationClient, field.TypeString, value)
+		_node.RotationClient = value
+	}
+	if value, ok := _c.mutation.SessionStartedAt(); ok {
+		_spec.SetField(refreshtoken.FieldSessionStartedAt, field.TypeTime, value)
+		_node.SessionStartedAt = &value
+	}
+	if value, ok := _c.mutation.LastUsedAt(); ok {
+		_spec.SetField(refreshtoken.FieldLastUsedAt, field.TypeTime, value)
+		_node.LastUsedAt = &value
+	}
+	if value, ok := _c.mutation.UserAgent(); ok {
+		_spec.SetField(refreshtoken.FieldUserAgent, field.TypeString, value)
+		_node.UserAgent = value
+	}
+	if value, ok := _c.mutation.IP(); ok {
+		_spec.SetField(refreshtoken.FieldIP, field.TypeString, value)
+		_node.IP = value
+	}
+	if value, ok := _c.mutation.Label(); ok {
+		_spec.SetField(refreshtoken.FieldLabel, field.TypeString, value)
+		_node.Label = value
+	}
+	if value, ok := _c.mutation.OauthClientID(); ok {
+		_spec.SetField(refreshtoken.FieldOauthClientID, field.TypeString, value)
+		_node.OauthClientID = value
+	}
+	if value, ok := _c.mutation.Scope(); ok {
+		_spec.SetField(refreshtoken.FieldScope, field.TypeString, value)
+		_node.Scope = value
+	}
 	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -85,6 +85,146 @@ func (_u *RefreshTokenUpdate) SetNillableRevoked(v *bool) *RefreshTokenUpdate {
 	return _u
 }
 
+// SetRevokedAt sets the "revoked_at" field.
+func (_u *RefreshTokenUpdate) SetRevokedAt(v time.Time) *RefreshTokenUpdate {
+	_u.mutation.SetRevokedAt(v)
+	return _u
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableRevokedAt(v *time.Time) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetRevokedAt(*v)
+	}
+	return _u
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (_u *RefreshTokenUpdate) ClearRevokedAt() *RefreshTokenUpdate {
+	_u.mutation.ClearRevokedAt()
+	return _u
+}
+
+// SetRotationNonce sets the "rotation_nonce" field.
+func (_u *RefreshTokenUpdate) SetRotationNonce(v string) *RefreshTokenUpdate {
+	_u.mutation.SetRotationNonce(v)
+	return _u
+}
+
+// SetNillableRotationNonce sets the "rotation_nonce" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableRotationNonce(v *string) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetRotationNonce(*v)
+	}
+	return _u
+}
+
+// ClearRotationNonce clears the value of the "rotation_nonce" field.
+func (_u *RefreshTokenUpdate) ClearRotationNonce() *RefreshTokenUpdate {
+	_u.mutation.ClearRotationNonce()
+	return _u
+}
+
+// SetRotationClient sets the "rotation_client" field.
+func (_u *RefreshTokenUpdate) SetRotationClient(v string) *RefreshTokenUpdate {
+	_u.mutation.SetRotationClient(v)
+	return _u
+}
+
+// SetNillableRotationClient sets the "rotation_client" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableRotationClient(v *string) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetRotationClient(*v)
+	}
+	return _u
+}
+
+// ClearRotationClient clears the value of the "rotation_client" field.
+func (_u *RefreshTokenUpdate) ClearRotationClient() *RefreshTokenUpdate {
+	_u.mutation.ClearRotationClient()
+	return _u
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_u *RefreshTokenUpdate) SetLastUsedAt(v time.Time) *RefreshTokenUpdate {
+	_u.mutation.SetLastUsedAt(v)
+	return _u
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableLastUsedAt(v *time.Time) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetLastUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (_u *RefreshTokenUpdate) ClearLastUsedAt() *RefreshTokenUpdate {
+	_u.mutation.ClearLastUsedAt()
+	return _u
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (_u *RefreshTokenUpdate) SetUserAgent(v string) *RefreshTokenUpdate {
+	_u.mutation.SetUserAgent(v)
+	return _u
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableUserAgent(v *string) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetUserAgent(*v)
+	}
+	return _u
+}
+
+// ClearUserAgent clears the value of the "user_agent" field.
+func (_u *RefreshTokenUpdate) ClearUserAgent() *RefreshTokenUpdate {
+	_u.mutation.ClearUserAgent()
+	return _u
+}
+
+// SetIP sets the "ip" field.
+func (_u *RefreshTokenUpdate) SetIP(v string) *RefreshTokenUpdate {
+	_u.mutation.SetIP(v)
+	return _u
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableIP(v *string) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetIP(*v)
+	}
+	return _u
+}
+
+// ClearIP clears the value of the "ip" field.
+func (_u *RefreshTokenUpdate) ClearIP() *RefreshTokenUpdate {
+	_u.mutation.ClearIP()
+	return _u
+}
+
+// SetLabel sets the "label" field.
+func (_u *RefreshTokenUpdate) SetLabel(v string) *RefreshTokenUpdate {
+	_u.mutation.SetLabel(v)
+	return _u
+}
+
+// SetNillableLabel sets the "label" field if the given value is not nil.
+func (_u *RefreshTokenUpdate) SetNillableLabel(v *string) *RefreshTokenUpdate {
+	if v != nil {
+		_u.SetLabel(*v)
+	}
+	return _u
+}
+
+// ClearLabel clears the value of the "label" field.
+func (_u *RefreshTokenUpdate) ClearLabel() *RefreshTokenUpdate {
+	_u.mutation.ClearLabel()
+	return _u
+}
+
 // SetUser sets the "user" edge to the User entity.
 func (_u *RefreshTokenUpdate) SetUser(v *User) *RefreshTokenUpdate {
 	return _u.SetUserID(v.ID)
@@ -156,12 +296,66 @@ func (_u *RefreshTokenUpdate) sqlSave(ctx context.Context) (_node int, err error
 	if value, ok := _u.mutation.TokenHash(); ok {
 		_spec.SetField(refreshtoken.FieldTokenHash, field.TypeString, value)
 	}
+	if _u.mutation.FamilyIDCleared() {
+		_spec.ClearField(refreshtoken.FieldFamilyID, field.TypeString)
+	}
 	if value, ok := _u.mutation.ExpiresAt(); ok {
 		_spec.SetField(refreshtoken.FieldExpiresAt, field.TypeTime, value)
 	}
 	if value, ok := _u.mutation.Revoked(); ok {
 		_spec.SetField(refreshtoken.FieldRevoked, field.TypeBool, value)
 	}
+	if value, ok := _u.mutation.RevokedAt(); ok {
+		_spec.SetField(refreshtoken.FieldRevokedAt, field.TypeTime, value)
+	}
+	if _u.mutation.RevokedAtCleared() {
+		_spec.ClearField(refreshtoken.FieldRevokedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.RotationNonce(); ok {
+		_spec.SetField(refreshtoken.FieldRotationNonce, field.TypeString, value)
+	}
+	if _u.mutation.RotationNonceCleared() {
+		_spec.ClearField(refreshtoken.FieldRotationNonce, field.TypeString)
+	}
+	if value, ok := _u.mutation.RotationClient(); ok {
+		_spec.SetField(refreshtoken.FieldRotationClient, field.TypeString, value)
+	}
+	if _u.mutation.RotationClientCleared() {
+		_spec.ClearField(refreshtoken.FieldRotationClient, field.TypeString)
+	}
+	if _u.mutation.SessionStartedAtCleared() {
+		_spec.ClearField(refreshtoken.FieldSessionStartedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastUsedAt(); ok {
+		_spec.SetField(refreshtoken.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastUsedAtCleared() {
+		_spec.ClearField(refreshtoken.FieldLastUsedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.UserAgent(); ok {
+		_spec.SetField(refreshtoken.FieldUserAgent, field.TypeString, value)
+	}
+	if _u.mutation.UserAgentCleared() {
+		_spec.ClearField(refreshtoken.FieldUserAgent, field.TypeString)
+	}
+	if value, ok := _u.mutation.IP(); ok {
+		_spec.SetField(refreshtoken.FieldIP, field.TypeString, value)
+	}
+	if _u.mutation.IPCleared() {
+		_spec.ClearField(refreshtoken.FieldIP, field.TypeString)
+	}
+	if value, ok := _u.mutation.Label(); ok {
+		_spec.SetField(refreshtoken.FieldLabel, field.TypeString, value)
+	}
+	if _u.mutation.LabelCleared() {
+		_spec.ClearField(refreshtoken.FieldLabel, field.TypeString)
+	}
+	if _u.mutation.OauthClientIDCleared() {
+		_spec.ClearField(refreshtoken.FieldOauthClientID, field.TypeString)
+	}
+	if _u.mutation.ScopeCleared() {
+		_spec.ClearField(refreshtoken.FieldScope, field.TypeString)
+	}
 	if _u.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -267,6 +461,146 @@ func (_u *RefreshTokenUpdateOne) SetNillableRevoked(v *bool) *RefreshTokenUpdate
 	return _u
 }
 
+// SetRevokedAt sets the "revoked_at" field.
+func (_u *RefreshTokenUpdateOne) SetRevokedAt(v time.Time) *RefreshTokenUpdateOne {
+	_u.mutation.SetRevokedAt(v)
+	return _u
+}
+
+// SetNillableRevokedAt sets the "revoked_at" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableRevokedAt(v *time.Time) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetRevokedAt(*v)
+	}
+	return _u
+}
+
+// ClearRevokedAt clears the value of the "revoked_at" field.
+func (_u *RefreshTokenUpdateOne) ClearRevokedAt() *RefreshTokenUpdateOne {
+	_u.mutation.ClearRevokedAt()
+	return _u
+}
+
+// SetRotationNonce sets the "rotation_nonce" field.
+func (_u *RefreshTokenUpdateOne) SetRotationNonce(v string) *RefreshTokenUpdateOne {
+	_u.mutation.SetRotationNonce(v)
+	return _u
+}
+
+// SetNillableRotationNonce sets the "rotation_nonce" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableRotationNonce(v *string) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetRotationNonce(*v)
+	}
+	return _u
+}
+
+// ClearRotationNonce clears the value of the "rotation_nonce" field.
+func (_u *RefreshTokenUpdateOne) ClearRotationNonce() *RefreshTokenUpdateOne {
+	_u.mutation.ClearRotationNonce()
+	return _u
+}
+
+// SetRotationClient sets the "rotation_client" field.
+func (_u *RefreshTokenUpdateOne) SetRotationClient(v string) *RefreshTokenUpdateOne {
+	_u.mutation.SetRotationClient(v)
+	return _u
+}
+
+// SetNillableRotationClient sets the "rotation_client" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableRotationClient(v *string) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetRotationClient(*v)
+	}
+	return _u
+}
+
+// ClearRotationClient clears the value of the "rotation_client" field.
+func (_u *RefreshTokenUpdateOne) ClearRotationClient() *RefreshTokenUpdateOne {
+	_u.mutation.ClearRotationClient()
+	return _u
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_u *RefreshTokenUpdateOne) SetLastUsedAt(v time.Time) *RefreshTokenUpdateOne {
+	_u.mutation.SetLastUsedAt(v)
+	return _u
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableLastUsedAt(v *time.Time) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetLastUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (_u *RefreshTokenUpdateOne) ClearLastUsedAt() *RefreshTokenUpdateOne {
+	_u.mutation.ClearLastUsedAt()
+	return _u
+}
+
+// SetUserAgent sets the "user_agent" field.
+func (_u *RefreshTokenUpdateOne) SetUserAgent(v string) *RefreshTokenUpdateOne {
+	_u.mutation.SetUserAgent(v)
+	return _u
+}
+
+// SetNillableUserAgent sets the "user_agent" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableUserAgent(v *string) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetUserAgent(*v)
+	}
+	return _u
+}
+
+// ClearUserAgent clears the value of the "user_agent" field.
+func (_u *RefreshTokenUpdateOne) ClearUserAgent() *RefreshTokenUpdateOne {
+	_u.mutation.ClearUserAgent()
+	return _u
+}
+
+// SetIP sets the "ip" field.
+func (_u *RefreshTokenUpdateOne) SetIP(v string) *RefreshTokenUpdateOne {
+	_u.mutation.SetIP(v)
+	return _u
+}
+
+// SetNillableIP sets the "ip" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableIP(v *string) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetIP(*v)
+	}
+	return _u
+}
+
+// ClearIP clears the value of the "ip" field.
+func (_u *RefreshTokenUpdateOne) ClearIP() *RefreshTokenUpdateOne {
+	_u.mutation.ClearIP()
+	return _u
+}
+
+// SetLabel sets the "label" field.
+func (_u *RefreshTokenUpdateOne) SetLabel(v string) *RefreshTokenUpdateOne {
+	_u.mutation.SetLabel(v)
+	return _u
+}
+
+// SetNillableLabel sets the "label" field if the given value is not nil.
+func (_u *RefreshTokenUpdateOne) SetNillableLabel(v *string) *RefreshTokenUpdateOne {
+	if v != nil {
+		_u.SetLabel(*v)
+	}
+	return _u
+}
+
+// ClearLabel clears the value of the "label" field.
+func (_u *RefreshTokenUpdateOne) ClearLabel() *RefreshTokenUpdateOne {
+	_u.mutation.ClearLabel()
+	return _u
+}
+
 // SetUser sets the "user" edge to the User entity.
 func (_u *RefreshTokenUpdateOne) SetUser(v *User) *RefreshTokenUpdateOne {
 	return _u.SetUserID(v.ID)
@@ -368,12 +702,66 @@ func (_u *RefreshTokenUpdateOne) sqlSave(ctx context.Context) (_node *RefreshTok
 	if value, ok := _u.mutation.TokenHash(); ok {
 		_spec.SetField(refreshtoken.FieldTokenHash, field.TypeString, value)
 	}
+	if _u.mutation.FamilyIDCleared() {
+		_spec.ClearField(refreshtoken.FieldFamilyID, field.TypeString)
+	}
 	if value, ok := _u.mutation.ExpiresAt(); ok {
 		_spec.SetField(refreshtoken.FieldExpiresAt, field.TypeTime, value)
 	}
 	if value, ok := _u.mutation.Revoked(); ok {
 		_spec.SetField(refreshtoken.FieldRevoked, field.TypeBool, value)
 	}
+	if value, ok := _u.mutation.RevokedAt(); ok {
+		_spec.SetField(refreshtoken.FieldRevokedAt, field.TypeTime, value)
+	}
+	if _u.mutation.RevokedAtCleared() {
+		_spec.ClearField(refreshtoken.FieldRevokedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.RotationNonce(); ok {
+		_spec.SetField(refreshtoken.FieldRotationNonce, field.TypeString, value)
+	}
+	if _u.mutation.RotationNonceCleared() {
+		_spec.ClearField(refreshtoken.FieldRotationNonce, field.TypeString)
+	}
+	if value, ok := _u.mutation.RotationClient(); ok {
+		_spec.SetField(refreshtoken.FieldRotationClient, field.TypeString, value)
+	}
+	if _u.mutation.RotationClientCleared() {
+		_spec.ClearField(refreshtoken.FieldRotationClient, field.TypeString)
+	}
+	if _u.mutation.SessionStartedAtCleared() {
+		_spec.ClearField(refreshtoken.FieldSessionStartedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastUsedAt(); ok {
+		_spec.SetField(refreshtoken.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastUsedAtCleared() {
+		_spec.ClearField(refreshtoken.FieldLastUsedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.UserAgent(); ok {
+		_spec.SetField(refreshtoken.FieldUserAgent, field.TypeString, value)
+	}
+	if _u.mutation.UserAgentCleared() {
+		_spec.ClearField(refreshtoken.FieldUserAgent, field.TypeString)
+	}
+	if value, ok := _u.mutation.IP(); ok {
+		_spec.SetField(refreshtoken.FieldIP, field.TypeString, value)
+	}
+	if _u.mutation.IPCleared() {
+		_spec.ClearField(refreshtoken.FieldIP, field.TypeString)
+	}
+	if value, ok := _u.mutation.Label(); ok {
+		_spec.SetField(refreshtoken.FieldLabel, field.TypeString, value)
+	}
+	if _u.mutation.LabelCleared() {
+		_spec.ClearField(refreshtoken.FieldLabel, field.TypeString)
+	}
+	if _u.mutation.OauthClientIDCleared() {
+		_spec.ClearField(refreshtoken.FieldOauthClientID, field.TypeString)
+	}
+	if _u.mutation.ScopeCleared() {
+		_spec.ClearField(refreshtoken.FieldScope, field.TypeString)
+	}
 	if _u.mutation.UserCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -3,9 +3,23 @@
 package ent
 
 import (
+	"app/ent/apikey"
+	"app/ent/authorizationcode"
+	"app/ent/federationstate"
+	"app/ent/loginthrottle"
+	"app/ent/magiclinktoken"
+	"app/ent/oauthclient"
+	"app/ent/oauthconsent"
+	"app/ent/passwordresettoken"
+	"app/ent/recoverycode"
 	"app/ent/refreshtoken"
+	"app/ent/role"
 	"app/ent/schema"
+	"app/ent/serviceclient"
 	"app/ent/user"
+	"app/ent/useridentity"
+	"app/ent/webauthncredential"
+	"app/ent/webauthnsession"
 	"time"
 )
 
@@ -13,6 +27,138 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	apikeyFields := schema.APIKey{}.Fields()
+	_ = apikeyFields
+	// apikeyDescName is the schema descriptor for name field.
+	apikeyDescName := apikeyFields[1].Descriptor()
+	// apikey.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	apikey.NameValidator = apikeyDescName.Validators[0].(func(string) error)
+	// apikeyDescPrefix is the schema descriptor for prefix field.
+	apikeyDescPrefix := apikeyFields[2].Descriptor()
+	// apikey.PrefixValidator is a validator for the "prefix" field. It is called by the builders before save.
+	apikey.PrefixValidator = apikeyDescPrefix.Validators[0].(func(string) error)
+	// apikeyDescSecretHash is the schema descriptor for secret_hash field.
+	apikeyDescSecretHash := apikeyFields[3].Descriptor()
+	// apikey.SecretHashValidator is a validator for the "secret_hash" field. It is called by the builders before save.
+	apikey.SecretHashValidator = apikeyDescSecretHash.Validators[0].(func(string) error)
+	// apikeyDescCreatedAt is the schema descriptor for created_at field.
+	apikeyDescCreatedAt := apikeyFields[6].Descriptor()
+	// apikey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	apikey.DefaultCreatedAt = apikeyDescCreatedAt.Default.(func() time.Time)
+	authorizationcodeFields := schema.AuthorizationCode{}.Fields()
+	_ = authorizationcodeFields
+	// authorizationcodeDescCodeHash is the schema descriptor for code_hash field.
+	authorizationcodeDescCodeHash := authorizationcodeFields[0].Descriptor()
+	// authorizationcode.CodeHashValidator is a validator for the "code_hash" field. It is called by the builders before save.
+	authorizationcode.CodeHashValidator = authorizationcodeDescCodeHash.Validators[0].(func(string) error)
+	// authorizationcodeDescCreatedAt is the schema descriptor for created_at field.
+	authorizationcodeDescCreatedAt := authorizationcodeFields[10].Descriptor()
+	// authorizationcode.DefaultCreatedAt holds the default value on creation for the created_at field.
+	authorizationcode.DefaultCreatedAt = authorizationcodeDescCreatedAt.Default.(func() time.Time)
+	federationstateFields := schema.FederationState{}.Fields()
+	_ = federationstateFields
+	// federationstateDescStateHash is the schema descriptor for state_hash field.
+	federationstateDescStateHash := federationstateFields[0].Descriptor()
+	// federationstate.StateHashValidator is a validator for the "state_hash" field. It is called by the builders before save.
+	federationstate.StateHashValidator = federationstateDescStateHash.Validators[0].(func(string) error)
+	// federationstateDescProvider is the schema descriptor for provider field.
+	federationstateDescProvider := federationstateFields[1].Descriptor()
+	// federationstate.ProviderValidator is a validator for the "provider" field. It is called by the builders before save.
+	federationstate.ProviderValidator = federationstateDescProvider.Validators[0].(func(string) error)
+	// federationstateDescNonce is the schema descriptor for nonce field.
+	federationstateDescNonce := federationstateFields[2].Descriptor()
+	// federationstate.NonceValidator is a validator for the "nonce" field. It is called by the builders before save.
+	federationstate.NonceValidator = federationstateDescNonce.Validators[0].(func(string) error)
+	// federationstateDescCodeVerifier is the schema descriptor for code_verifier field.
+	federationstateDescCodeVerifier := federationstateFields[3].Descriptor()
+	// federationstate.CodeVerifierValidator is a validator for the "code_verifier" field. It is called by the builders before save.
+	federationstate.CodeVerifierValidator = federationstateDescCodeVerifier.Validators[0].(func(string) error)
+	// federationstateDescCreatedAt is the schema descriptor for created_at field.
+	federationstateDescCreatedAt := federationstateFields[6].Descriptor()
+	// federationstate.DefaultCreatedAt holds the default value on creation for the created_at field.
+	federationstate.DefaultCreatedAt = federationstateDescCreatedAt.Default.(func() time.Time)
+	loginthrottleFields := schema.LoginThrottle{}.Fields()
+	_ = loginthrottleFields
+	// loginthrottleDescKey is the schema descriptor for key field.
+	loginthrottleDescKey := loginthrottleFields[0].Descriptor()
+	// loginthrottle.KeyValidator is a validator for the "key" field. It is called by the builders before save.
+	loginthrottle.KeyValidator = loginthrottleDescKey.Validators[0].(func(string) error)
+	// loginthrottleDescFailures is the schema descriptor for failures field.
+	loginthrottleDescFailures := loginthrottleFields[1].Descriptor()
+	// loginthrottle.DefaultFailures holds the default value on creation for the failures field.
+	loginthrottle.DefaultFailures = loginthrottleDescFailures.Default.(int)
+	magiclinktokenFields := schema.MagicLinkToken{}.Fields()
+	_ = magiclinktokenFields
+	// magiclinktokenDescTokenHash is the schema descriptor for token_hash field.
+	magiclinktokenDescTokenHash := magiclinktokenFields[0].Descriptor()
+	// magiclinktoken.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	magiclinktoken.TokenHashValidator = magiclinktokenDescTokenHash.Validators[0].(func(string) error)
+	// magiclinktokenDescCodeHash is the schema descriptor for code_hash field.
+	magiclinktokenDescCodeHash := magiclinktokenFields[1].Descriptor()
+	// magiclinktoken.CodeHashValidator is a validator for the "code_hash" field. It is called by the builders before save.
+	magiclinktoken.CodeHashValidator = magiclinktokenDescCodeHash.Validators[0].(func(string) error)
+	// magiclinktokenDescEmail is the schema descriptor for email field.
+	magiclinktokenDescEmail := magiclinktokenFields[2].Descriptor()
+	// magiclinktoken.EmailValidator is a validator for the "email" field. It is called by the builders before save.
+	magiclinktoken.EmailValidator = magiclinktokenDescEmail.Validators[0].(func(string) error)
+	// magiclinktokenDescAttempts is the schema descriptor for attempts field.
+	magiclinktokenDescAttempts := magiclinktokenFields[4].Descriptor()
+	// magiclinktoken.DefaultAttempts holds the default value on creation for the attempts field.
+	magiclinktoken.DefaultAttempts = magiclinktokenDescAttempts.Default.(int)
+	// magiclinktokenDescCreatedAt is the schema descriptor for created_at field.
+	magiclinktokenDescCreatedAt := magiclinktokenFields[6].Descriptor()
+	// magiclinktoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	magiclinktoken.DefaultCreatedAt = magiclinktokenDescCreatedAt.Default.(func() time.Time)
+	oauthclientFields := schema.OAuthClient{}.Fields()
+	_ = oauthclientFields
+	// oauthclientDescClientID is the schema descriptor for client_id field.
+	oauthclientDescClientID := oauthclientFields[0].Descriptor()
+	// oauthclient.ClientIDValidator is a validator for the "client_id" field. It is called by the builders before save.
+	oauthclient.ClientIDValidator = oauthclientDescClientID.Validators[0].(func(string) error)
+	// oauthclientDescName is the schema descriptor for name field.
+	oauthclientDescName := oauthclientFields[2].Descriptor()
+	// oauthclient.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	oauthclient.NameValidator = oauthclientDescName.Validators[0].(func(string) error)
+	// oauthclientDescSkipConsent is the schema descriptor for skip_consent field.
+	oauthclientDescSkipConsent := oauthclientFields[5].Descriptor()
+	// oauthclient.DefaultSkipConsent holds the default value on creation for the skip_consent field.
+	oauthclient.DefaultSkipConsent = oauthclientDescSkipConsent.Default.(bool)
+	// oauthclientDescCreatedAt is the schema descriptor for created_at field.
+	oauthclientDescCreatedAt := oauthclientFields[6].Descriptor()
+	// oauthclient.DefaultCreatedAt holds the default value on creation for the created_at field.
+	oauthclient.DefaultCreatedAt = oauthclientDescCreatedAt.Default.(func() time.Time)
+	oauthconsentFields := schema.OAuthConsent{}.Fields()
+	_ = oauthconsentFields
+	// oauthconsentDescCreatedAt is the schema descriptor for created_at field.
+	oauthconsentDescCreatedAt := oauthconsentFields[3].Descriptor()
+	// oauthconsent.DefaultCreatedAt holds the default value on creation for the created_at field.
+	oauthconsent.DefaultCreatedAt = oauthconsentDescCreatedAt.Default.(func() time.Time)
+	// oauthconsentDescUpdatedAt is the schema descriptor for updated_at field.
+	oauthconsentDescUpdatedAt := oauthconsentFields[4].Descriptor()
+	// oauthconsent.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	oauthconsent.DefaultUpdatedAt = oauthconsentDescUpdatedAt.Default.(func() time.Time)
+	// oauthconsent.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	oauthconsent.UpdateDefaultUpdatedAt = oauthconsentDescUpdatedAt.UpdateDefault.(func() time.Time)
+	passwordresettokenFields := schema.PasswordResetToken{}.Fields()
+	_ = passwordresettokenFields
+	// passwordresettokenDescTokenHash is the schema descriptor for token_hash field.
+	passwordresettokenDescTokenHash := passwordresettokenFields[0].Descriptor()
+	// passwordresettoken.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
+	passwordresettoken.TokenHashValidator = passwordresettokenDescTokenHash.Validators[0].(func(string) error)
+	// passwordresettokenDescCreatedAt is the schema descriptor for created_at field.
+	passwordresettokenDescCreatedAt := passwordresettokenFields[3].Descriptor()
+	// passwordresettoken.DefaultCreatedAt holds the default value on creation for the created_at field.
+	passwordresettoken.DefaultCreatedAt = passwordresettokenDescCreatedAt.Default.(func() time.Time)
+	recoverycodeFields := schema.RecoveryCode{}.Fields()
+	_ = recoverycodeFields
+	// recoverycodeDescCodeHash is the schema descriptor for code_hash field.
+	recoverycodeDescCodeHash := recoverycodeFields[0].Descriptor()
+	// recoverycode.CodeHashValidator is a validator for the "code_hash" field. It is called by the builders before save.
+	recoverycode.CodeHashValidator = recoverycodeDescCodeHash.Validators[0].(func(string) error)
+	// recoverycodeDescCreatedAt is the schema descriptor for created_at field.
+	recoverycodeDescCreatedAt := recoverycodeFields[2].Descriptor()
+	// recoverycode.DefaultCreatedAt holds the default value on creation for the created_at field.
+	recoverycode.DefaultCreatedAt = recoverycodeDescCreatedAt.Default.(func() time.Time)
 	refreshtokenFields := schema.RefreshToken{}.Fields()
 	_ = refreshtokenFields
 	// refreshtokenDescTokenHash is the schema descriptor for token_hash field.
@@ -20,13 +166,41 @@ func init() {
 	// refreshtoken.TokenHashValidator is a validator for the "token_hash" field. It is called by the builders before save.
 	refreshtoken.TokenHashValidator = refreshtokenDescTokenHash.Validators[0].(func(string) error)
 	// refreshtokenDescCreatedAt is the schema descriptor for created_at field.
-	refreshtokenDescCreatedAt := refreshtokenFields[3].Descriptor()
+	refreshtokenDescCreatedAt := refreshtokenFields[4].Descriptor()
 	// refreshtoken.DefaultCreatedAt holds the default value on creation for the created_at field.
 	refreshtoken.DefaultCreatedAt = refreshtokenDescCreatedAt.Default.(func() time.Time)
 	// refreshtokenDescRevoked is the schema descriptor for revoked field.
-	refreshtokenDescRevoked := refreshtokenFields[4].Descriptor()
+	refreshtokenDescRevoked := refreshtokenFields[5].Descriptor()
 	// refreshtoken.DefaultRevoked holds the default value on creation for the revoked field.
 	refreshtoken.DefaultRevoked = refreshtokenDescRevoked.Default.(bool)
+	roleFields := schema.Role{}.Fields()
+	_ = roleFields
+	// roleDescName is the schema descriptor for name field.
+	roleDescName := roleFields[0].Descriptor()
+	// role.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	role.NameValidator = roleDescName.Validators[0].(func(string) error)
+	// roleDescPermissions is the schema descriptor for permissions field.
+	roleDescPermissions := roleFields[2].Descriptor()
+	// role.DefaultPermissions holds the default value on creation for the permissions field.
+	role.DefaultPermissions = roleDescPermissions.Default.([]string)
+	serviceclientFields := schema.ServiceClient{}.Fields()
+	_ = serviceclientFields
+	// serviceclientDescClientID is the schema descriptor for client_id field.
+	serviceclientDescClientID := serviceclientFields[0].Descriptor()
+	// serviceclient.ClientIDValidator is a validator for the "client_id" field. It is called by the builders before save.
+	serviceclient.ClientIDValidator = serviceclientDescClientID.Validators[0].(func(string) error)
+	// serviceclientDescSecretHash is the schema descriptor for secret_hash field.
+	serviceclientDescSecretHash := serviceclientFields[1].Descriptor()
+	// serviceclient.SecretHashValidator is a validator for the "secret_hash" field. It is called by the builders before save.
+	serviceclient.SecretHashValidator = serviceclientDescSecretHash.Validators[0].(func(string) error)
+	// serviceclientDescName is the schema descriptor for name field.
+	serviceclientDescName := serviceclientFields[2].Descriptor()
+	// serviceclient.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	serviceclient.NameValidator = serviceclientDescName.Validators[0].(func(string) error)
+	// serviceclientDescCreatedAt is the schema descriptor for created_at field.
+	serviceclientDescCreatedAt := serviceclientFields[4].Descriptor()
+	// serviceclient.DefaultCreatedAt holds the default value on creation for the created_at field.
+	serviceclient.DefaultCreatedAt = serviceclientDescCreatedAt.Default.(func() time.Time)
 	userFields := schema.User{}.Fields()
 	_ = userFields
 	// userDescEmail is the schema descriptor for email field.
@@ -37,4 +211,78 @@ func init() {
 	userDescPassword := userFields[2].Descriptor()
 	// user.PasswordValidator is a validator for the "password" field. It is called by the builders before save.
 	user.PasswordValidator = userDescPassword.Validators[0].(func(string) error)
+	// userDescEmailVerified is the schema descriptor for email_verified field.
+	userDescEmailVerified := userFields[3].Descriptor()
+	// user.DefaultEmailVerified holds the default value on creation for the email_verified field.
+	user.DefaultEmailVerified = userDescEmailVerified.Default.(bool)
+	// userDescTotpEnabled is the schema descriptor for totp_enabled field.
+	userDescTotpEnabled := userFields[6].Descriptor()
+	// user.DefaultTotpEnabled holds the default value on creation for the totp_enabled field.
+	user.DefaultTotpEnabled = userDescTotpEnabled.Default.(bool)
+	// userDescTotpLastStep is the schema descriptor for totp_last_step field.
+	userDescTotpLastStep := userFields[7].Descriptor()
+	// user.DefaultTotpLastStep holds the default value on creation for the totp_last_step field.
+	user.DefaultTotpLastStep = userDescTotpLastStep.Default.(int64)
+	useridentityFields := schema.UserIdentity{}.Fields()
+	_ = useridentityFields
+	// useridentityDescProvider is the schema descriptor for provider field.
+	useridentityDescProvider := useridentityFields[0].Descriptor()
+	// useridentity.ProviderValidator is a validator for the "provider" field. It is called by the builders before save.
+	useridentity.ProviderValidator = useridentityDescProvider.Validators[0].(func(string) error)
+	// useridentityDescSubject is the schema descriptor for subject field.
+	useridentityDescSubject := useridentityFields[1].Descriptor()
+	// useridentity.SubjectValidator is a validator for the "subject" field. It is called by the builders before save.
+	useridentity.SubjectValidator = useridentityDescSubject.Validators[0].(func(string) error)
+	// useridentityDescCreatedAt is the schema descriptor for created_at field.
+	useridentityDescCreatedAt := useridentityFields[4].Descriptor()
+	// useridentity.DefaultCreatedAt holds the default value on creation for the created_at field.
+	useridentity.DefaultCreatedAt = useridentityDescCreatedAt.Default.(func() time.Time)
+	webauthncredentialFields := schema.WebAuthnCredential{}.Fields()
+	_ = webauthncredentialFields
+	// webauthncredentialDescCredentialID is the schema descriptor for credential_id field.
+	webauthncredentialDescCredentialID := webauthncredentialFields[0].Descriptor()
+	// webauthncredential.CredentialIDValidator is a validator for the "credential_id" field. It is called by the builders before save.
+	webauthncredential.CredentialIDValidator = webauthncredentialDescCredentialID.Validators[0].(func([]byte) error)
+	// webauthncredentialDescPublicKey is the schema descriptor for public_key field.
+	webauthncredentialDescPublicKey := webauthncredentialFields[1].Descriptor()
+	// webauthncredential.PublicKeyValidator is a validator for the "public_key" field. It is called by the builders before save.
+	webauthncredential.PublicKeyValidator = webauthncredentialDescPublicKey.Validators[0].(func([]byte) error)
+	// webauthncredentialDescAttestationType is the schema descriptor for attestation_type field.
+	webauthncredentialDescAttestationType := webauthncredentialFields[2].Descriptor()
+	// webauthncredential.DefaultAttestationType holds the default value on creation for the attestation_type field.
+	webauthncredential.DefaultAttestationType = webauthncredentialDescAttestationType.Default.(string)
+	// webauthncredentialDescAttestationFormat is the schema descriptor for attestation_format field.
+	webauthncredentialDescAttestationFormat := webauthncredentialFields[3].Descriptor()
+	// webauthncredential.DefaultAttestationFormat holds the default value on creation for the attestation_format field.
+	webauthncredential.DefaultAttestationFormat = webauthncredentialDescAttestationFormat.Default.(string)
+	// webauthncredentialDescSignCount is the schema descriptor for sign_count field.
+	webauthncredentialDescSignCount := webauthncredentialFields[5].Descriptor()
+	// webauthncredential.DefaultSignCount holds the default value on creation for the sign_count field.
+	webauthncredential.DefaultSignCount = webauthncredentialDescSignCount.Default.(uint32)
+	// webauthncredentialDescAttachment is the schema descriptor for attachment field.
+	webauthncredentialDescAttachment := webauthncredentialFields[7].Descriptor()
+	// webauthncredential.DefaultAttachment holds the default value on creation for the attachment field.
+	webauthncredential.DefaultAttachment = webauthncredentialDescAttachment.Default.(string)
+	// webauthncredentialDescFlags is the schema descriptor for flags field.
+	webauthncredentialDescFlags := webauthncredentialFields[8].Descriptor()
+	// webauthncredential.DefaultFlags holds the default value on creation for the flags field.
+	webauthncredential.DefaultFlags = webauthncredentialDescFlags.Default.(uint8)
+	// webauthncredentialDescName is the schema descriptor for name field.
+	webauthncredentialDescName := webauthncredentialFields[9].Descriptor()
+	// webauthncredential.DefaultName holds the default value on creation for the name field.
+	webauthncredential.DefaultName = webauthncredentialDescName.Default.(string)
+	// webauthncredentialDescCreatedAt is the schema descriptor for created_at field.
+	webauthncredentialDescCreatedAt := webauthncredentialFields[11].Descriptor()
+	// webauthncredential.DefaultCreatedAt holds the default value on creation for the created_at field.
+	webauthncredential.DefaultCreatedAt = webauthncredentialDescCreatedAt.Default.(func() time.Time)
+	webauthnsessionFields := schema.WebAuthnSession{}.Fields()
+	_ = webauthnsessionFields
+	// webauthnsessionDescChallenge is the schema descriptor for challenge field.
+	webauthnsessionDescChallenge := webauthnsessionFields[0].Descriptor()
+	// webauthnsession.ChallengeValidator is a validator for the "challenge" field. It is called by the builders before save.
+	webauthnsession.ChallengeValidator = webauthnsessionDescChallenge.Validators[0].(func(string) error)
+	// webauthnsessionDescCreatedAt is the schema descriptor for created_at field.
+	webauthnsessionDescCreatedAt := webauthnsessionFields[5].Descriptor()
+	// webauthnsession.DefaultCreatedAt holds the default value on creation for the created_at field.
+	webauthnsession.DefaultCreatedAt = webauthnsessionDescCreatedAt.Default.(func() time.Time)
 }
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// APIKey holds the schema definition for the APIKey entity: a long-lived
+// credential a user creates for scripts and CI pipelines.
+type APIKey struct {
+	ent.Schema
+}
+
+// Fields of the APIKey.
+func (APIKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("user_id"),
+		field.String("name").
+			NotEmpty(),
+		// Prefix is the random part shown in the key, sk_<prefix>_..., so
+		// that users can tell their keys apart.
+		field.String("prefix").
+			NotEmpty().
+			Unique().
+			Immutable(),
+		// SecretHash is the SHA-256 of the whole key.
+		field.String("secret_hash").
+			NotEmpty().
+			Unique().
+			Sensitive().
+			Immutable(),
+		// Scopes limit what the key may do, like those of OAuth tokens.
+		field.Strings("scopes"),
+		field.Time("expires_at").
+			Optional().
+			Nillable(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("last_used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the APIKey.
+func (APIKey) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("api_keys").
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
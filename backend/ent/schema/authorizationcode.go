@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// AuthorizationCode holds the schema definition for the AuthorizationCode entity.
+type AuthorizationCode struct {
+	ent.Schema
+}
+
+// Fields of the AuthorizationCode.
+func (AuthorizationCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("code_hash").
+			NotEmpty().
+			Unique(),
+		field.Int("oauth_client_id"),
+		field.Int("user_id"),
+		field.String("redirect_uri"),
+		field.Strings("scopes"),
+		field.String("nonce").
+			Optional(),
+		field.String("code_challenge"),
+		field.String("code_challenge_method"),
+		// SessionID is the session started when the code is exchanged, so
+		// that it can be ended if the code is presented again.
+		field.String("session_id").
+			Optional(),
+		field.Time("expires_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the AuthorizationCode.
+func (AuthorizationCode) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("client", OAuthClient.Type).
+			Ref("authorization_codes").
+			Field("oauth_client_id").
+			Unique().
+			Required(),
+		edge.From("user", User.Type).
+			Ref("authorization_codes").
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the AuthorizationCode.
+func (AuthorizationCode) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("code_hash").Unique(),
+		index.Fields("expires_at"),
+	}
+}
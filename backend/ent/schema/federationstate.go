@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// FederationState holds the schema definition for the FederationState
+// entity. It keeps a sign-in at an external identity provider between the
+// redirect to the provider and the callback.
+type FederationState struct {
+	ent.Schema
+}
+
+// Fields of the FederationState.
+func (FederationState) Fields() []ent.Field {
+	return []ent.Field{
+		// StateHash is the SHA-256 of the state parameter sent to the
+		// provider.
+		field.String("state_hash").
+			NotEmpty().
+			Unique(),
+		field.String("provider").
+			NotEmpty(),
+		field.String("nonce").
+			NotEmpty(),
+		// CodeVerifier is the PKCE verifier; it never leaves the server.
+		field.String("code_verifier").
+			NotEmpty().
+			Sensitive(),
+		// UserID is set when a logged in user links another identity.
+		field.Int("user_id").
+			Optional().
+			Nillable(),
+		field.Time("expires_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the FederationState.
+func (FederationState) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("federation_states").
+			Field("user_id").
+			Unique(),
+	}
+}
+
+// Indexes of the FederationState.
+func (FederationState) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("expires_at"),
+	}
+}
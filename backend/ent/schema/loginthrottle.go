@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// LoginThrottle holds the schema definition for the LoginThrottle entity.
+// It counts failed logins per key, either an account email or a client IP.
+type LoginThrottle struct {
+	ent.Schema
+}
+
+// Fields of the LoginThrottle.
+func (LoginThrottle) Fields() []ent.Field {
+	return []ent.Field{
+		// Key is "account:<email>" or "ip:<address>".
+		field.String("key").
+			NotEmpty().
+			Unique(),
+		field.Int("failures").
+			Default(0),
+		field.Time("last_failure_at"),
+		field.Time("locked_until").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Indexes of the LoginThrottle.
+func (LoginThrottle) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("key").Unique(),
+	}
+}
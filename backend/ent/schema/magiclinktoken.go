@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// MagicLinkToken holds the schema definition for the MagicLinkToken entity.
+type MagicLinkToken struct {
+	ent.Schema
+}
+
+// Fields of the MagicLinkToken.
+func (MagicLinkToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("token_hash").
+			NotEmpty().
+			Unique(),
+		// CodeHash is the hash of the short code mailed with the link.
+		field.String("code_hash").
+			NotEmpty(),
+		field.String("email").
+			NotEmpty(),
+		// UserID is unset when the email had no account at the time of the
+		// request; the account is then created when the token is used.
+		field.Int("user_id").
+			Optional().
+			Nillable(),
+		// Attempts counts wrong codes entered for the token.
+		field.Int("attempts").
+			Default(0),
+		field.Time("expires_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		// UsedAt is set when the token is redeemed, superseded by a newer one
+		// or burnt by too many wrong codes.
+		field.Time("used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the MagicLinkToken.
+func (MagicLinkToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("magic_link_tokens").
+			Field("user_id").
+			Unique(),
+	}
+}
+
+// Indexes of the MagicLinkToken.
+func (MagicLinkToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("token_hash").Unique(),
+		index.Fields("email", "created_at"),
+	}
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// OAuthClient holds the schema definition for the OAuthClient entity.
+type OAuthClient struct {
+	ent.Schema
+}
+
+// Fields of the OAuthClient.
+func (OAuthClient) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("client_id").
+			NotEmpty().
+			Unique().
+			Immutable(),
+		// SecretHash is empty for public clients such as SPAs and mobile
+		// apps, which cannot keep a secret and rely on PKCE alone.
+		field.String("secret_hash").
+			Optional().
+			Sensitive(),
+		field.String("name").
+			NotEmpty(),
+		// RedirectURIs are compared exactly with the redirect_uri of a
+		// request.
+		field.Strings("redirect_uris"),
+		// Scopes the client may request.
+		field.Strings("scopes"),
+		// SkipConsent marks first-party clients whose users are not asked
+		// for consent.
+		field.Bool("skip_consent").
+			Default(false),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the OAuthClient.
+func (OAuthClient) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("authorization_codes", AuthorizationCode.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("consents", OAuthConsent.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+	}
+}
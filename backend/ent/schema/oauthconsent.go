@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OAuthConsent holds the schema definition for the OAuthConsent entity.
+type OAuthConsent struct {
+	ent.Schema
+}
+
+// Fields of the OAuthConsent.
+func (OAuthConsent) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("user_id"),
+		field.Int("oauth_client_id"),
+		// Scopes the user has agreed to share with the client.
+		field.Strings("scopes"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now),
+	}
+}
+
+// Edges of the OAuthConsent.
+func (OAuthConsent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("oauth_consents").
+			Field("user_id").
+			Unique().
+			Required(),
+		edge.From("client", OAuthClient.Type).
+			Ref("consents").
+			Field("oauth_client_id").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the OAuthConsent.
+func (OAuthConsent) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id", "oauth_client_id").Unique(),
+	}
+}
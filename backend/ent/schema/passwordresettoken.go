@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// PasswordResetToken holds the schema definition for the PasswordResetToken entity.
+type PasswordResetToken struct {
+	ent.Schema
+}
+
+// Fields of the PasswordResetToken.
+func (PasswordResetToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("token_hash").
+			NotEmpty().
+			Unique(),
+		field.Int("user_id"),
+		field.Time("expires_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		// UsedAt is set when the token is redeemed or superseded by a reset.
+		field.Time("used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the PasswordResetToken.
+func (PasswordResetToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("password_reset_tokens").
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the PasswordResetToken.
+func (PasswordResetToken) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("token_hash").Unique(),
+		index.Fields("user_id"),
+	}
+}
@@ -21,12 +21,51 @@ func (RefreshToken) Fields() []ent.Field {
 			NotEmpty().
 			Unique(),
 		field.Int("user_id"),
+		// FamilyID groups every token descended from one login through
+		// rotation. Tokens issued before families existed have none.
+		field.String("family_id").
+			Optional().
+			Immutable(),
 		field.Time("expires_at"),
 		field.Time("created_at").
 			Default(time.Now).
 			Immutable(),
 		field.Bool("revoked").
 			Default(false),
+		field.Time("revoked_at").
+			Optional().
+			Nillable(),
+		// RotationNonce and RotationClient are set when the token is rotated.
+		// Together with the plain token they let a retried request from the
+		// same client re-derive its successor during the grace period.
+		field.String("rotation_nonce").
+			Optional().
+			Sensitive(),
+		field.String("rotation_client").
+			Optional(),
+		// Session metadata, refreshed from the presenting client on rotation.
+		// SessionStartedAt is copied from the first token of the family.
+		field.Time("session_started_at").
+			Optional().
+			Nillable().
+			Immutable(),
+		field.Time("last_used_at").
+			Optional().
+			Nillable(),
+		field.String("user_agent").
+			Optional(),
+		field.String("ip").
+			Optional(),
+		field.String("label").
+			Optional(),
+		// OAuthClientID and Scope are set for sessions authorized through
+		// the OpenID Connect provider and carried over on rotation.
+		field.String("oauth_client_id").
+			Optional().
+			Immutable(),
+		field.String("scope").
+			Optional().
+			Immutable(),
 	}
 }
 
@@ -46,5 +85,7 @@ func (RefreshToken) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("token_hash").Unique(),
 		index.Fields("user_id"),
+		index.Fields("family_id"),
+		index.Fields("oauth_client_id"),
 	}
 }
@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// ServiceClient holds the schema definition for the ServiceClient entity: a
+// machine identity of another backend service, authenticating with the
+// client_credentials grant.
+type ServiceClient struct {
+	ent.Schema
+}
+
+// Fields of the ServiceClient.
+func (ServiceClient) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("client_id").
+			NotEmpty().
+			Unique().
+			Immutable(),
+		field.String("secret_hash").
+			NotEmpty().
+			Sensitive(),
+		field.String("name").
+			NotEmpty(),
+		// Scopes the client may request, such as orders:read.
+		field.Strings("scopes"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("secret_rotated_at").
+			Optional().
+			Nillable(),
+		field.Time("last_used_at").
+			Optional().
+			Nillable(),
+	}
+}
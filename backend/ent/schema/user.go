@@ -2,6 +2,7 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 )
@@ -17,12 +18,42 @@ func (User) Fields() []ent.Field {
 		field.String("email").NotEmpty().Unique(),
 		field.String("username").Optional(),
 		field.String("password").NotEmpty(),
+		field.Bool("email_verified").Default(false),
+		field.Time("email_verified_at").Optional().Nillable(),
+		// TOTPSecret is set on enrollment; TOTPEnabled once a code confirmed it.
+		field.String("totp_secret").Optional().Sensitive(),
+		field.Bool("totp_enabled").Default(false),
+		// TOTPLastStep is the time step of the last accepted code, so that a
+		// code cannot be used twice.
+		field.Int64("totp_last_step").Default(0),
 	}
 }
 
 // Edges of the User.
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
-		edge.To("refresh_tokens", RefreshToken.Type),
+		edge.To("refresh_tokens", RefreshToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("roles", Role.Type),
+		edge.To("password_reset_tokens", PasswordResetToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("recovery_codes", RecoveryCode.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("webauthn_credentials", WebAuthnCredential.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("webauthn_sessions", WebAuthnSession.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("magic_link_tokens", MagicLinkToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("authorization_codes", AuthorizationCode.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("oauth_consents", OAuthConsent.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("identities", UserIdentity.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("federation_states", FederationState.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("api_keys", APIKey.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
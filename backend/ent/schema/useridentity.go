@@ -0,0 +1,60 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// UserIdentity holds the schema definition for the UserIdentity entity: an
+// account at an external OpenID Connect provider linked to a user. A user
+// can have several.
+type UserIdentity struct {
+	ent.Schema
+}
+
+// Fields of the UserIdentity.
+func (UserIdentity) Fields() []ent.Field {
+	return []ent.Field{
+		// Provider is the configured name of the identity provider.
+		field.String("provider").
+			NotEmpty().
+			Immutable(),
+		// Subject is the sub claim, stable for the account at the provider.
+		field.String("subject").
+			NotEmpty().
+			Immutable(),
+		field.Int("user_id"),
+		// Email is the address the provider reported at the last login.
+		field.String("email").
+			Optional(),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("last_login_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the UserIdentity.
+func (UserIdentity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("identities").
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the UserIdentity.
+func (UserIdentity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("provider", "subject").
+			Unique(),
+	}
+}
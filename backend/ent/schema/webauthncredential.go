@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// WebAuthnCredential holds the schema definition for the WebAuthnCredential
+// entity. Each row is a passkey a user registered with an authenticator.
+type WebAuthnCredential struct {
+	ent.Schema
+}
+
+// Fields of the WebAuthnCredential.
+func (WebAuthnCredential) Fields() []ent.Field {
+	return []ent.Field{
+		// CredentialID is chosen by the authenticator and sent with every
+		// assertion to look the credential up.
+		field.Bytes("credential_id").
+			NotEmpty().
+			Unique(),
+		field.Bytes("public_key").
+			NotEmpty(),
+		field.String("attestation_type").
+			Default(""),
+		field.String("attestation_format").
+			Default(""),
+		field.Bytes("aaguid").
+			Optional(),
+		// SignCount is the last signature counter reported by the
+		// authenticator; a counter that does not grow hints at a clone.
+		field.Uint32("sign_count").
+			Default(0),
+		field.Strings("transports").
+			Optional(),
+		field.String("attachment").
+			Default(""),
+		// Flags are the authenticator data flags (user present, user
+		// verified, backup eligible, backup state) as last reported.
+		field.Uint8("flags").
+			Default(0),
+		field.String("name").
+			Default(""),
+		field.Int("user_id"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+		field.Time("last_used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the WebAuthnCredential.
+func (WebAuthnCredential) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("webauthn_credentials").
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+// Indexes of the WebAuthnCredential.
+func (WebAuthnCredential) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id"),
+	}
+}
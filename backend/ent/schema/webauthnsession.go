@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// WebAuthnSession holds the schema definition for the WebAuthnSession entity.
+// It keeps the challenge of a registration or login ceremony between its
+// begin and finish requests.
+type WebAuthnSession struct {
+	ent.Schema
+}
+
+// Fields of the WebAuthnSession.
+func (WebAuthnSession) Fields() []ent.Field {
+	return []ent.Field{
+		// Challenge is the base64url challenge the authenticator signs, and
+		// identifies the session when the client finishes the ceremony.
+		field.String("challenge").
+			NotEmpty().
+			Unique(),
+		field.Enum("ceremony").
+			Values("registration", "login"),
+		// Data is the session data of the WebAuthn library, JSON encoded.
+		field.Bytes("data"),
+		// UserID is set for registrations; passkey logins start without
+		// knowing the user.
+		field.Int("user_id").
+			Optional().
+			Nillable(),
+		field.Time("expires_at"),
+		field.Time("created_at").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+// Edges of the WebAuthnSession.
+func (WebAuthnSession) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("webauthn_sessions").
+			Field("user_id").
+			Unique(),
+	}
+}
+
+// Indexes of the WebAuthnSession.
+func (WebAuthnSession) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("expires_at"),
+	}
+}
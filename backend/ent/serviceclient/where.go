@@ -0,0 +1,435 @@
+// Code generated by ent, DO NOT EDIT.
+
+package serviceclient
+
+import (
+	"app/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldID, id))
+}
+
+// ClientID applies equality check predicate on the "client_id" field. It's identical to ClientIDEQ.
+func ClientID(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldClientID, v))
+}
+
+// SecretHash applies equality check predicate on the "secret_hash" field. It's identical to SecretHashEQ.
+func SecretHash(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldSecretHash, v))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldName, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// SecretRotatedAt applies equality check predicate on the "secret_rotated_at" field. It's identical to SecretRotatedAtEQ.
+func SecretRotatedAt(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldSecretRotatedAt, v))
+}
+
+// LastUsedAt applies equality check predicate on the "last_used_at" field. It's identical to LastUsedAtEQ.
+func LastUsedAt(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldLastUsedAt, v))
+}
+
+// ClientIDEQ applies the EQ predicate on the "client_id" field.
+func ClientIDEQ(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldClientID, v))
+}
+
+// ClientIDNEQ applies the NEQ predicate on the "client_id" field.
+func ClientIDNEQ(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldClientID, v))
+}
+
+// ClientIDIn applies the In predicate on the "client_id" field.
+func ClientIDIn(vs ...string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldClientID, vs...))
+}
+
+// ClientIDNotIn applies the NotIn predicate on the "client_id" field.
+func ClientIDNotIn(vs ...string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldClientID, vs...))
+}
+
+// ClientIDGT applies the GT predicate on the "client_id" field.
+func ClientIDGT(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldClientID, v))
+}
+
+// ClientIDGTE applies the GTE predicate on the "client_id" field.
+func ClientIDGTE(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldClientID, v))
+}
+
+// ClientIDLT applies the LT predicate on the "client_id" field.
+func ClientIDLT(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldClientID, v))
+}
+
+// ClientIDLTE applies the LTE predicate on the "client_id" field.
+func ClientIDLTE(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldClientID, v))
+}
+
+// ClientIDContains applies the Contains predicate on the "client_id" field.
+func ClientIDContains(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldContains(FieldClientID, v))
+}
+
+// ClientIDHasPrefix applies the HasPrefix predicate on the "client_id" field.
+func ClientIDHasPrefix(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldHasPrefix(FieldClientID, v))
+}
+
+// ClientIDHasSuffix applies the HasSuffix predicate on the "client_id" field.
+func ClientIDHasSuffix(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldHasSuffix(FieldClientID, v))
+}
+
+// ClientIDEqualFold applies the EqualFold predicate on the "client_id" field.
+func ClientIDEqualFold(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEqualFold(FieldClientID, v))
+}
+
+// ClientIDContainsFold applies the ContainsFold predicate on the "client_id" field.
+func ClientIDContainsFold(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldContainsFold(FieldClientID, v))
+}
+
+// SecretHashEQ applies the EQ predicate on the "secret_hash" field.
+func SecretHashEQ(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldSecretHash, v))
+}
+
+// SecretHashNEQ applies the NEQ predicate on the "secret_hash" field.
+func SecretHashNEQ(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldSecretHash, v))
+}
+
+// SecretHashIn applies the In predicate on the "secret_hash" field.
+func SecretHashIn(vs ...string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldSecretHash, vs...))
+}
+
+// SecretHashNotIn applies the NotIn predicate on the "secret_hash" field.
+func SecretHashNotIn(vs ...string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldSecretHash, vs...))
+}
+
+// SecretHashGT applies the GT predicate on the "secret_hash" field.
+func SecretHashGT(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldSecretHash, v))
+}
+
+// SecretHashGTE applies the GTE predicate on the "secret_hash" field.
+func SecretHashGTE(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldSecretHash, v))
+}
+
+// SecretHashLT applies the LT predicate on the "secret_hash" field.
+func SecretHashLT(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldSecretHash, v))
+}
+
+// SecretHashLTE applies the LTE predicate on the "secret_hash" field.
+func SecretHashLTE(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldSecretHash, v))
+}
+
+// SecretHashContains applies the Contains predicate on the "secret_hash" field.
+func SecretHashContains(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldContains(FieldSecretHash, v))
+}
+
+// SecretHashHasPrefix applies the HasPrefix predicate on the "secret_hash" field.
+func SecretHashHasPrefix(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldHasPrefix(FieldSecretHash, v))
+}
+
+// SecretHashHasSuffix applies the HasSuffix predicate on the "secret_hash" field.
+func SecretHashHasSuffix(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldHasSuffix(FieldSecretHash, v))
+}
+
+// SecretHashEqualFold applies the EqualFold predicate on the "secret_hash" field.
+func SecretHashEqualFold(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEqualFold(FieldSecretHash, v))
+}
+
+// SecretHashContainsFold applies the ContainsFold predicate on the "secret_hash" field.
+func SecretHashContainsFold(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldContainsFold(FieldSecretHash, v))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldContainsFold(FieldName, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// SecretRotatedAtEQ applies the EQ predicate on the "secret_rotated_at" field.
+func SecretRotatedAtEQ(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldSecretRotatedAt, v))
+}
+
+// SecretRotatedAtNEQ applies the NEQ predicate on the "secret_rotated_at" field.
+func SecretRotatedAtNEQ(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldSecretRotatedAt, v))
+}
+
+// SecretRotatedAtIn applies the In predicate on the "secret_rotated_at" field.
+func SecretRotatedAtIn(vs ...time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldSecretRotatedAt, vs...))
+}
+
+// SecretRotatedAtNotIn applies the NotIn predicate on the "secret_rotated_at" field.
+func SecretRotatedAtNotIn(vs ...time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldSecretRotatedAt, vs...))
+}
+
+// SecretRotatedAtGT applies the GT predicate on the "secret_rotated_at" field.
+func SecretRotatedAtGT(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldSecretRotatedAt, v))
+}
+
+// SecretRotatedAtGTE applies the GTE predicate on the "secret_rotated_at" field.
+func SecretRotatedAtGTE(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldSecretRotatedAt, v))
+}
+
+// SecretRotatedAtLT applies the LT predicate on the "secret_rotated_at" field.
+func SecretRotatedAtLT(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldSecretRotatedAt, v))
+}
+
+// SecretRotatedAtLTE applies the LTE predicate on the "secret_rotated_at" field.
+func SecretRotatedAtLTE(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldSecretRotatedAt, v))
+}
+
+// SecretRotatedAtIsNil applies the IsNil predicate on the "secret_rotated_at" field.
+func SecretRotatedAtIsNil() predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIsNull(FieldSecretRotatedAt))
+}
+
+// SecretRotatedAtNotNil applies the NotNil predicate on the "secret_rotated_at" field.
+func SecretRotatedAtNotNil() predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotNull(FieldSecretRotatedAt))
+}
+
+// LastUsedAtEQ applies the EQ predicate on the "last_used_at" field.
+func LastUsedAtEQ(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldEQ(FieldLastUsedAt, v))
+}
+
+// LastUsedAtNEQ applies the NEQ predicate on the "last_used_at" field.
+func LastUsedAtNEQ(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNEQ(FieldLastUsedAt, v))
+}
+
+// LastUsedAtIn applies the In predicate on the "last_used_at" field.
+func LastUsedAtIn(vs ...time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIn(FieldLastUsedAt, vs...))
+}
+
+// LastUsedAtNotIn applies the NotIn predicate on the "last_used_at" field.
+func LastUsedAtNotIn(vs ...time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotIn(FieldLastUsedAt, vs...))
+}
+
+// LastUsedAtGT applies the GT predicate on the "last_used_at" field.
+func LastUsedAtGT(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGT(FieldLastUsedAt, v))
+}
+
+// LastUsedAtGTE applies the GTE predicate on the "last_used_at" field.
+func LastUsedAtGTE(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldGTE(FieldLastUsedAt, v))
+}
+
+// LastUsedAtLT applies the LT predicate on the "last_used_at" field.
+func LastUsedAtLT(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLT(FieldLastUsedAt, v))
+}
+
+// LastUsedAtLTE applies the LTE predicate on the "last_used_at" field.
+func LastUsedAtLTE(v time.Time) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldLTE(FieldLastUsedAt, v))
+}
+
+// LastUsedAtIsNil applies the IsNil predicate on the "last_used_at" field.
+func LastUsedAtIsNil() predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldIsNull(FieldLastUsedAt))
+}
+
+// LastUsedAtNotNil applies the NotNil predicate on the "last_used_at" field.
+func LastUsedAtNotNil() predicate.ServiceClient {
+	return predicate.ServiceClient(sql.FieldNotNull(FieldLastUsedAt))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.ServiceClient) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.ServiceClient) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.ServiceClient) predicate.ServiceClient {
+	return predicate.ServiceClient(sql.NotPredicates(p))
+}
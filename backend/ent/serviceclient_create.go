@@ -0,0 +1,305 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/serviceclient"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// ServiceClientCreate is the builder for creating a ServiceClient entity.
+type ServiceClientCreate struct {
+	config
+	mutation *ServiceClientMutation
+	hooks    []Hook
+}
+
+// SetClientID sets the "client_id" field.
+func (_c *ServiceClientCreate) SetClientID(v string) *ServiceClientCreate {
+	_c.mutation.SetClientID(v)
+	return _c
+}
+
+// SetSecretHash sets the "secret_hash" field.
+func (_c *ServiceClientCreate) SetSecretHash(v string) *ServiceClientCreate {
+	_c.mutation.SetSecretHash(v)
+	return _c
+}
+
+// SetName sets the "name" field.
+func (_c *ServiceClientCreate) SetName(v string) *ServiceClientCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetScopes sets the "scopes" field.
+func (_c *ServiceClientCreate) SetScopes(v []string) *ServiceClientCreate {
+	_c.mutation.SetScopes(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *ServiceClientCreate) SetCreatedAt(v time.Time) *ServiceClientCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *ServiceClientCreate) SetNillableCreatedAt(v *time.Time) *ServiceClientCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetSecretRotatedAt sets the "secret_rotated_at" field.
+func (_c *ServiceClientCreate) SetSecretRotatedAt(v time.Time) *ServiceClientCreate {
+	_c.mutation.SetSecretRotatedAt(v)
+	return _c
+}
+
+// SetNillableSecretRotatedAt sets the "secret_rotated_at" field if the given value is not nil.
+func (_c *ServiceClientCreate) SetNillableSecretRotatedAt(v *time.Time) *ServiceClientCreate {
+	if v != nil {
+		_c.SetSecretRotatedAt(*v)
+	}
+	return _c
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_c *ServiceClientCreate) SetLastUsedAt(v time.Time) *ServiceClientCreate {
+	_c.mutation.SetLastUsedAt(v)
+	return _c
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_c *ServiceClientCreate) SetNillableLastUsedAt(v *time.Time) *ServiceClientCreate {
+	if v != nil {
+		_c.SetLastUsedAt(*v)
+	}
+	return _c
+}
+
+// Mutation returns the ServiceClientMutation object of the builder.
+func (_c *ServiceClientCreate) Mutation() *ServiceClientMutation {
+	return _c.mutation
+}
+
+// Save creates the ServiceClient in the database.
+func (_c *ServiceClientCreate) Save(ctx context.Context) (*ServiceClient, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *ServiceClientCreate) SaveX(ctx context.Context) *ServiceClient {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ServiceClientCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ServiceClientCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *ServiceClientCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := serviceclient.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *ServiceClientCreate) check() error {
+	if _, ok := _c.mutation.ClientID(); !ok {
+		return &ValidationError{Name: "client_id", err: errors.New(`ent: missing required field "ServiceClient.client_id"`)}
+	}
+	if v, ok := _c.mutation.ClientID(); ok {
+		if err := serviceclient.ClientIDValidator(v); err != nil {
+			return &ValidationError{Name: "client_id", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.client_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.SecretHash(); !ok {
+		return &ValidationError{Name: "secret_hash", err: errors.New(`ent: missing required field "ServiceClient.secret_hash"`)}
+	}
+	if v, ok := _c.mutation.SecretHash(); ok {
+		if err := serviceclient.SecretHashValidator(v); err != nil {
+			return &ValidationError{Name: "secret_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.secret_hash": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "ServiceClient.name"`)}
+	}
+	if v, ok := _c.mutation.Name(); ok {
+		if err := serviceclient.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Scopes(); !ok {
+		return &ValidationError{Name: "scopes", err: errors.New(`ent: missing required field "ServiceClient.scopes"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "ServiceClient.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *ServiceClientCreate) sqlSave(ctx context.Context) (*ServiceClient, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *ServiceClientCreate) createSpec() (*ServiceClient, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ServiceClient{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(serviceclient.Table, sqlgraph.NewFieldSpec(serviceclient.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.ClientID(); ok {
+		_spec.SetField(serviceclient.FieldClientID, field.TypeString, value)
+		_node.ClientID = value
+	}
+	if value, ok := _c.mutation.SecretHash(); ok {
+		_spec.SetField(serviceclient.FieldSecretHash, field.TypeString, value)
+		_node.SecretHash = value
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(serviceclient.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.Scopes(); ok {
+		_spec.SetField(serviceclient.FieldScopes, field.TypeJSON, value)
+		_node.Scopes = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(serviceclient.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.SecretRotatedAt(); ok {
+		_spec.SetField(serviceclient.FieldSecretRotatedAt, field.TypeTime, value)
+		_node.SecretRotatedAt = &value
+	}
+	if value, ok := _c.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceclient.FieldLastUsedAt, field.TypeTime, value)
+		_node.LastUsedAt = &value
+	}
+	return _node, _spec
+}
+
+// ServiceClientCreateBulk is the builder for creating many ServiceClient entities in bulk.
+type ServiceClientCreateBulk struct {
+	config
+	err      error
+	builders []*ServiceClientCreate
+}
+
+// Save creates the ServiceClient entities in the database.
+func (_c *ServiceClientCreateBulk) Save(ctx context.Context) ([]*ServiceClient, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*ServiceClient, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ServiceClientMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *ServiceClientCreateBulk) SaveX(ctx context.Context) []*ServiceClient {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *ServiceClientCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *ServiceClientCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,425 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/predicate"
+	"app/ent/serviceclient"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+)
+
+// ServiceClientUpdate is the builder for updating ServiceClient entities.
+type ServiceClientUpdate struct {
+	config
+	hooks    []Hook
+	mutation *ServiceClientMutation
+}
+
+// Where appends a list predicates to the ServiceClientUpdate builder.
+func (_u *ServiceClientUpdate) Where(ps ...predicate.ServiceClient) *ServiceClientUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetSecretHash sets the "secret_hash" field.
+func (_u *ServiceClientUpdate) SetSecretHash(v string) *ServiceClientUpdate {
+	_u.mutation.SetSecretHash(v)
+	return _u
+}
+
+// SetNillableSecretHash sets the "secret_hash" field if the given value is not nil.
+func (_u *ServiceClientUpdate) SetNillableSecretHash(v *string) *ServiceClientUpdate {
+	if v != nil {
+		_u.SetSecretHash(*v)
+	}
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *ServiceClientUpdate) SetName(v string) *ServiceClientUpdate {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *ServiceClientUpdate) SetNillableName(v *string) *ServiceClientUpdate {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetScopes sets the "scopes" field.
+func (_u *ServiceClientUpdate) SetScopes(v []string) *ServiceClientUpdate {
+	_u.mutation.SetScopes(v)
+	return _u
+}
+
+// AppendScopes appends value to the "scopes" field.
+func (_u *ServiceClientUpdate) AppendScopes(v []string) *ServiceClientUpdate {
+	_u.mutation.AppendScopes(v)
+	return _u
+}
+
+// SetSecretRotatedAt sets the "secret_rotated_at" field.
+func (_u *ServiceClientUpdate) SetSecretRotatedAt(v time.Time) *ServiceClientUpdate {
+	_u.mutation.SetSecretRotatedAt(v)
+	return _u
+}
+
+// SetNillableSecretRotatedAt sets the "secret_rotated_at" field if the given value is not nil.
+func (_u *ServiceClientUpdate) SetNillableSecretRotatedAt(v *time.Time) *ServiceClientUpdate {
+	if v != nil {
+		_u.SetSecretRotatedAt(*v)
+	}
+	return _u
+}
+
+// ClearSecretRotatedAt clears the value of the "secret_rotated_at" field.
+func (_u *ServiceClientUpdate) ClearSecretRotatedAt() *ServiceClientUpdate {
+	_u.mutation.ClearSecretRotatedAt()
+	return _u
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_u *ServiceClientUpdate) SetLastUsedAt(v time.Time) *ServiceClientUpdate {
+	_u.mutation.SetLastUsedAt(v)
+	return _u
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_u *ServiceClientUpdate) SetNillableLastUsedAt(v *time.Time) *ServiceClientUpdate {
+	if v != nil {
+		_u.SetLastUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (_u *ServiceClientUpdate) ClearLastUsedAt() *ServiceClientUpdate {
+	_u.mutation.ClearLastUsedAt()
+	return _u
+}
+
+// Mutation returns the ServiceClientMutation object of the builder.
+func (_u *ServiceClientUpdate) Mutation() *ServiceClientMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *ServiceClientUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ServiceClientUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *ServiceClientUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ServiceClientUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ServiceClientUpdate) check() error {
+	if v, ok := _u.mutation.SecretHash(); ok {
+		if err := serviceclient.SecretHashValidator(v); err != nil {
+			return &ValidationError{Name: "secret_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.secret_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Name(); ok {
+		if err := serviceclient.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.name": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ServiceClientUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(serviceclient.Table, serviceclient.Columns, sqlgraph.NewFieldSpec(serviceclient.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.SecretHash(); ok {
+		_spec.SetField(serviceclient.FieldSecretHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(serviceclient.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Scopes(); ok {
+		_spec.SetField(serviceclient.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, serviceclient.FieldScopes, value)
+		})
+	}
+	if value, ok := _u.mutation.SecretRotatedAt(); ok {
+		_spec.SetField(serviceclient.FieldSecretRotatedAt, field.TypeTime, value)
+	}
+	if _u.mutation.SecretRotatedAtCleared() {
+		_spec.ClearField(serviceclient.FieldSecretRotatedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceclient.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastUsedAtCleared() {
+		_spec.ClearField(serviceclient.FieldLastUsedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{serviceclient.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// ServiceClientUpdateOne is the builder for updating a single ServiceClient entity.
+type ServiceClientUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *ServiceClientMutation
+}
+
+// SetSecretHash sets the "secret_hash" field.
+func (_u *ServiceClientUpdateOne) SetSecretHash(v string) *ServiceClientUpdateOne {
+	_u.mutation.SetSecretHash(v)
+	return _u
+}
+
+// SetNillableSecretHash sets the "secret_hash" field if the given value is not nil.
+func (_u *ServiceClientUpdateOne) SetNillableSecretHash(v *string) *ServiceClientUpdateOne {
+	if v != nil {
+		_u.SetSecretHash(*v)
+	}
+	return _u
+}
+
+// SetName sets the "name" field.
+func (_u *ServiceClientUpdateOne) SetName(v string) *ServiceClientUpdateOne {
+	_u.mutation.SetName(v)
+	return _u
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_u *ServiceClientUpdateOne) SetNillableName(v *string) *ServiceClientUpdateOne {
+	if v != nil {
+		_u.SetName(*v)
+	}
+	return _u
+}
+
+// SetScopes sets the "scopes" field.
+func (_u *ServiceClientUpdateOne) SetScopes(v []string) *ServiceClientUpdateOne {
+	_u.mutation.SetScopes(v)
+	return _u
+}
+
+// AppendScopes appends value to the "scopes" field.
+func (_u *ServiceClientUpdateOne) AppendScopes(v []string) *ServiceClientUpdateOne {
+	_u.mutation.AppendScopes(v)
+	return _u
+}
+
+// SetSecretRotatedAt sets the "secret_rotated_at" field.
+func (_u *ServiceClientUpdateOne) SetSecretRotatedAt(v time.Time) *ServiceClientUpdateOne {
+	_u.mutation.SetSecretRotatedAt(v)
+	return _u
+}
+
+// SetNillableSecretRotatedAt sets the "secret_rotated_at" field if the given value is not nil.
+func (_u *ServiceClientUpdateOne) SetNillableSecretRotatedAt(v *time.Time) *ServiceClientUpdateOne {
+	if v != nil {
+		_u.SetSecretRotatedAt(*v)
+	}
+	return _u
+}
+
+// ClearSecretRotatedAt clears the value of the "secret_rotated_at" field.
+func (_u *ServiceClientUpdateOne) ClearSecretRotatedAt() *ServiceClientUpdateOne {
+	_u.mutation.ClearSecretRotatedAt()
+	return _u
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_u *ServiceClientUpdateOne) SetLastUsedAt(v time.Time) *ServiceClientUpdateOne {
+	_u.mutation.SetLastUsedAt(v)
+	return _u
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_u *ServiceClientUpdateOne) SetNillableLastUsedAt(v *time.Time) *ServiceClientUpdateOne {
+	if v != nil {
+		_u.SetLastUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastUsedAt clears the value of the "last_used_at" field.
+func (_u *ServiceClientUpdateOne) ClearLastUsedAt() *ServiceClientUpdateOne {
+	_u.mutation.ClearLastUsedAt()
+	return _u
+}
+
+// Mutation returns the ServiceClientMutation object of the builder.
+func (_u *ServiceClientUpdateOne) Mutation() *ServiceClientMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the ServiceClientUpdate builder.
+func (_u *ServiceClientUpdateOne) Where(ps ...predicate.ServiceClient) *ServiceClientUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *ServiceClientUpdateOne) Select(field string, fields ...string) *ServiceClientUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated ServiceClient entity.
+func (_u *ServiceClientUpdateOne) Save(ctx context.Context) (*ServiceClient, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *ServiceClientUpdateOne) SaveX(ctx context.Context) *ServiceClient {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *ServiceClientUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *ServiceClientUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *ServiceClientUpdateOne) check() error {
+	if v, ok := _u.mutation.SecretHash(); ok {
+		if err := serviceclient.SecretHashValidator(v); err != nil {
+			return &ValidationError{Name: "secret_hash", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.secret_hash": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Name(); ok {
+		if err := serviceclient.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "ServiceClient.name": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *ServiceClientUpdateOne) sqlSave(ctx context.Context) (_node *ServiceClient, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(serviceclient.Table, serviceclient.Columns, sqlgraph.NewFieldSpec(serviceclient.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "ServiceClient.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, serviceclient.FieldID)
+		for _, f := range fields {
+			if !serviceclient.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != serviceclient.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.SecretHash(); ok {
+		_spec.SetField(serviceclient.FieldSecretHash, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Name(); ok {
+		_spec.SetField(serviceclient.FieldName, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Scopes(); ok {
+		_spec.SetField(serviceclient.FieldScopes, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedScopes(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, serviceclient.FieldScopes, value)
+		})
+	}
+	if value, ok := _u.mutation.SecretRotatedAt(); ok {
+		_spec.SetField(serviceclient.FieldSecretRotatedAt, field.TypeTime, value)
+	}
+	if _u.mutation.SecretRotatedAtCleared() {
+		_spec.ClearField(serviceclient.FieldSecretRotatedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LastUsedAt(); ok {
+		_spec.SetField(serviceclient.FieldLastUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastUsedAtCleared() {
+		_spec.ClearField(serviceclient.FieldLastUsedAt, field.TypeTime)
+	}
+	_node = &ServiceClient{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{serviceclient.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,461 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/user"
+	"app/ent/webauthncredential"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// WebAuthnCredentialCreate is the builder for creating a WebAuthnCredential entity.
+type WebAuthnCredentialCreate struct {
+	config
+	mutation *WebAuthnCredentialMutation
+	hooks    []Hook
+}
+
+// SetCredentialID sets the "credential_id" field.
+func (_c *WebAuthnCredentialCreate) SetCredentialID(v []byte) *WebAuthnCredentialCreate {
+	_c.mutation.SetCredentialID(v)
+	return _c
+}
+
+// SetPublicKey sets the "public_key" field.
+func (_c *WebAuthnCredentialCreate) SetPublicKey(v []byte) *WebAuthnCredentialCreate {
+	_c.mutation.SetPublicKey(v)
+	return _c
+}
+
+// SetAttestationType sets the "attestation_type" field.
+func (_c *WebAuthnCredentialCreate) SetAttestationType(v string) *WebAuthnCredentialCreate {
+	_c.mutation.SetAttestationType(v)
+	return _c
+}
+
+// SetNillableAttestationType sets the "attestation_type" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableAttestationType(v *string) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetAttestationType(*v)
+	}
+	return _c
+}
+
+// SetAttestationFormat sets the "attestation_format" field.
+func (_c *WebAuthnCredentialCreate) SetAttestationFormat(v string) *WebAuthnCredentialCreate {
+	_c.mutation.SetAttestationFormat(v)
+	return _c
+}
+
+// SetNillableAttestationFormat sets the "attestation_format" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableAttestationFormat(v *string) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetAttestationFormat(*v)
+	}
+	return _c
+}
+
+// SetAaguid sets the "aaguid" field.
+func (_c *WebAuthnCredentialCreate) SetAaguid(v []byte) *WebAuthnCredentialCreate {
+	_c.mutation.SetAaguid(v)
+	return _c
+}
+
+// SetSignCount sets the "sign_count" field.
+func (_c *WebAuthnCredentialCreate) SetSignCount(v uint32) *WebAuthnCredentialCreate {
+	_c.mutation.SetSignCount(v)
+	return _c
+}
+
+// SetNillableSignCount sets the "sign_count" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableSignCount(v *uint32) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetSignCount(*v)
+	}
+	return _c
+}
+
+// SetTransports sets the "transports" field.
+func (_c *WebAuthnCredentialCreate) SetTransports(v []string) *WebAuthnCredentialCreate {
+	_c.mutation.SetTransports(v)
+	return _c
+}
+
+// SetAttachment sets the "attachment" field.
+func (_c *WebAuthnCredentialCreate) SetAttachment(v string) *WebAuthnCredentialCreate {
+	_c.mutation.SetAttachment(v)
+	return _c
+}
+
+// SetNillableAttachment sets the "attachment" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableAttachment(v *string) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetAttachment(*v)
+	}
+	return _c
+}
+
+// SetFlags sets the "flags" field.
+func (_c *WebAuthnCredentialCreate) SetFlags(v uint8) *WebAuthnCredentialCreate {
+	_c.mutation.SetFlags(v)
+	return _c
+}
+
+// SetNillableFlags sets the "flags" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableFlags(v *uint8) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetFlags(*v)
+	}
+	return _c
+}
+
+// SetName sets the "name" field.
+func (_c *WebAuthnCredentialCreate) SetName(v string) *WebAuthnCredentialCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetNillableName sets the "name" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableName(v *string) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetName(*v)
+	}
+	return _c
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *WebAuthnCredentialCreate) SetUserID(v int) *WebAuthnCredentialCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *WebAuthnCredentialCreate) SetCreatedAt(v time.Time) *WebAuthnCredentialCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableCreatedAt(v *time.Time) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetLastUsedAt sets the "last_used_at" field.
+func (_c *WebAuthnCredentialCreate) SetLastUsedAt(v time.Time) *WebAuthnCredentialCreate {
+	_c.mutation.SetLastUsedAt(v)
+	return _c
+}
+
+// SetNillableLastUsedAt sets the "last_used_at" field if the given value is not nil.
+func (_c *WebAuthnCredentialCreate) SetNillableLastUsedAt(v *time.Time) *WebAuthnCredentialCreate {
+	if v != nil {
+		_c.SetLastUsedAt(*v)
+	}
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *WebAuthnCredentialCreate) SetUser(v *User) *WebAuthnCredentialCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// Mutation returns the WebAuthnCredentialMutation object of the builder.
+func (_c *WebAuthnCredentialCreate) Mutation() *WebAuthnCredentialMutation {
+	return _c.mutation
+}
+
+// Save creates the WebAuthnCredential in the database.
+func (_c *WebAuthnCredentialCreate) Save(ctx context.Context) (*WebAuthnCredential, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *WebAuthnCredentialCreate) SaveX(ctx context.Context) *WebAuthnCredential {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *WebAuthnCredentialCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *WebAuthnCredentialCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *WebAuthnCredentialCreate) defaults() {
+	if _, ok := _c.mutation.AttestationType(); !ok {
+		v := webauthncredential.DefaultAttestationType
+		_c.mutation.SetAttestationType(v)
+	}
+	if _, ok := _c.mutation.AttestationFormat(); !ok {
+		v := webauthncredential.DefaultAttestationFormat
+		_c.mutation.SetAttestationFormat(v)
+	}
+	if _, ok := _c.mutation.SignCount(); !ok {
+		v := webauthncredential.DefaultSignCount
+		_c.mutation.SetSignCount(v)
+	}
+	if _, ok := _c.mutation.Attachment(); !ok {
+		v := webauthncredential.DefaultAttachment
+		_c.mutation.SetAttachment(v)
+	}
+	if _, ok := _c.mutation.Flags(); !ok {
+		v := webauthncredential.DefaultFlags
+		_c.mutation.SetFlags(v)
+	}
+	if _, ok := _c.mutation.Name(); !ok {
+		v := webauthncredential.DefaultName
+		_c.mutation.SetName(v)
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := webauthncredential.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *WebAuthnCredentialCreate) check() error {
+	if _, ok := _c.mutation.CredentialID(); !ok {
+		return &ValidationError{Name: "credential_id", err: errors.New(`ent: missing required field "WebAuthnCredential.credential_id"`)}
+	}
+	if v, ok := _c.mutation.CredentialID(); ok {
+		if err := webauthncredential.CredentialIDValidator(v); err != nil {
+			return &ValidationError{Name: "credential_id", err: fmt.Errorf(`ent: validator failed for field "WebAuthnCredential.credential_id": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.PublicKey(); !ok {
+		return &ValidationError{Name: "public_key", err: errors.New(`ent: missing required field "WebAuthnCredential.public_key"`)}
+	}
+	if v, ok := _c.mutation.PublicKey(); ok {
+		if err := webauthncredential.PublicKeyValidator(v); err != nil {
+			return &ValidationError{Name: "public_key", err: fmt.Errorf(`ent: validator failed for field "WebAuthnCredential.public_key": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.AttestationType(); !ok {
+		return &ValidationError{Name: "attestation_type", err: errors.New(`ent: missing required field "WebAuthnCredential.attestation_type"`)}
+	}
+	if _, ok := _c.mutation.AttestationFormat(); !ok {
+		return &ValidationError{Name: "attestation_format", err: errors.New(`ent: missing required field "WebAuthnCredential.attestation_format"`)}
+	}
+	if _, ok := _c.mutation.SignCount(); !ok {
+		return &ValidationError{Name: "sign_count", err: errors.New(`ent: missing required field "WebAuthnCredential.sign_count"`)}
+	}
+	if _, ok := _c.mutation.Attachment(); !ok {
+		return &ValidationError{Name: "attachment", err: errors.New(`ent: missing required field "WebAuthnCredential.attachment"`)}
+	}
+	if _, ok := _c.mutation.Flags(); !ok {
+		return &ValidationError{Name: "flags", err: errors.New(`ent: missing required field "WebAuthnCredential.flags"`)}
+	}
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "WebAuthnCredential.name"`)}
+	}
+	if _, ok := _c.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "WebAuthnCredential.user_id"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "WebAuthnCredential.created_at"`)}
+	}
+	if len(_c.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "WebAuthnCredential.user"`)}
+	}
+	return nil
+}
+
+func (_c *WebAuthnCredentialCreate) sqlSave(ctx context.Context) (*WebAuthnCredential, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *WebAuthnCredentialCreate) createSpec() (*WebAuthnCredential, *sqlgraph.CreateSpec) {
+	var (
+		_node = &WebAuthnCredential{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(webauthncredential.Table, sqlgraph.NewFieldSpec(webauthncredential.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.CredentialID(); ok {
+		_spec.SetField(webauthncredential.FieldCredentialID, field.TypeBytes, value)
+		_node.CredentialID = value
+	}
+	if value, ok := _c.mutation.PublicKey(); ok {
+		_spec.SetField(webauthncredential.FieldPublicKey, field.TypeBytes, value)
+		_node.PublicKey = value
+	}
+	if value, ok := _c.mutation.AttestationType(); ok {
+		_spec.SetField(webauthncredential.FieldAttestationType, field.TypeString, value)
+		_node.AttestationType = value
+	}
+	if value, ok := _c.mutation.AttestationFormat(); ok {
+		_spec.SetField(webauthncredential.FieldAttestationFormat, field.TypeString, value)
+		_node.AttestationFormat = value
+	}
+	if value, ok := _c.mutation.Aaguid(); ok {
+		_spec.SetField(webauthncredential.FieldAaguid, field.TypeBytes, value)
+		_node.Aaguid = value
+	}
+	if value, ok := _c.mutation.SignCount(); ok {
+		_spec.SetField(webauthncredential.FieldSignCount, field.TypeUint32, value)
+		_node.SignCount = value
+	}
+	if value, ok := _c.mutation.Transports(); ok {
+		_spec.SetField(webauthncredential.FieldTransports, field.TypeJSON, value)
+		_node.Transports = value
+	}
+	if value, ok := _c.mutation.Attachment(); ok {
+		_spec.SetField(webauthncredential.FieldAttachment, field.TypeString, value)
+		_node.Attachment = value
+	}
+	if value, ok := _c.mutation.Flags(); ok {
+		_spec.SetField(webauthncredential.FieldFlags, field.TypeUint8, value)
+		_node.Flags = value
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(webauthncredential.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(webauthncredential.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.LastUsedAt(); ok {
+		_spec.SetField(webauthncredential.FieldLastUsedAt, field.TypeTime, value)
+		_node.LastUsedAt = &value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   webauthncredential.UserTable,
+			Columns: []string{webauthncredential.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// WebAuthnCredentialCreateBulk is the builder for creating many WebAuthnCredential entities in bulk.
+type WebAuthnCredentialCreateBulk struct {
+	config
+	err      error
+	builders []*WebAuthnCredentialCreate
+}
+
+// Save creates the WebAuthnCredential entities in the database.
+func (_c *WebAuthnCredentialCreateBulk) Save(ctx context.Context) ([]*WebAuthnCredential, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*WebAuthnCredential, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*WebAuthnCredentialMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *WebAuthnCredentialCreateBulk) SaveX(ctx context.Context) []*WebAuthnCredential {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *WebAuthnCredentialCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *WebAuthnCredentialCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
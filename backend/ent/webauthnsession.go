@@ -0,0 +1,195 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/user"
+	"app/ent/webauthnsession"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// WebAuthnSession is the model entity for the WebAuthnSession schema.
+type WebAuthnSession struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// Challenge holds the value of the "challenge" field.
+	Challenge string `json:"challenge,omitempty"`
+	// Ceremony holds the value of the "ceremony" field.
+	Ceremony webauthnsession.Ceremony `json:"ceremony,omitempty"`
+	// Data holds the value of the "data" field.
+	Data []byte `json:"data,omitempty"`
+	// UserID holds the value of the "user_id" field.
+	UserID *int `json:"user_id,omitempty"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the WebAuthnSessionQuery when eager-loading is set.
+	Edges        WebAuthnSessionEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// WebAuthnSessionEdges holds the relations/edges for other nodes in the graph.
+type WebAuthnSessionEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e WebAuthnSessionEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*WebAuthnSession) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case webauthnsession.FieldData:
+			values[i] = new([]byte)
+		case webauthnsession.FieldID, webauthnsession.FieldUserID:
+			values[i] = new(sql.NullInt64)
+		case webauthnsession.FieldChallenge, webauthnsession.FieldCeremony:
+			values[i] = new(sql.NullString)
+		case webauthnsession.FieldExpiresAt, webauthnsession.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the WebAuthnSession fields.
+func (_m *WebAuthnSession) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case webauthnsession.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			_m.ID = int(value.Int64)
+		case webauthnsession.FieldChallenge:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field challenge", values[i])
+			} else if value.Valid {
+				_m.Challenge = value.String
+			}
+		case webauthnsession.FieldCeremony:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field ceremony", values[i])
+			} else if value.Valid {
+				_m.Ceremony = webauthnsession.Ceremony(value.String)
+			}
+		case webauthnsession.FieldData:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field data", values[i])
+			} else if value != nil {
+				_m.Data = *value
+			}
+		case webauthnsession.FieldUserID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				_m.UserID = new(int)
+				*_m.UserID = int(value.Int64)
+			}
+		case webauthnsession.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				_m.ExpiresAt = value.Time
+			}
+		case webauthnsession.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the WebAuthnSession.
+// This includes values selected through modifiers, order, etc.
+func (_m *WebAuthnSession) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the WebAuthnSession entity.
+func (_m *WebAuthnSession) QueryUser() *UserQuery {
+	return NewWebAuthnSessionClient(_m.config).QueryUser(_m)
+}
+
+// Update returns a builder for updating this WebAuthnSession.
+// Note that you need to call WebAuthnSession.Unwrap() before calling this method if this WebAuthnSession
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *WebAuthnSession) Update() *WebAuthnSessionUpdateOne {
+	return NewWebAuthnSessionClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the WebAuthnSession entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *WebAuthnSession) Unwrap() *WebAuthnSession {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: WebAuthnSession is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *WebAuthnSession) String() string {
+	var builder strings.Builder
+	builder.WriteString("WebAuthnSession(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("challenge=")
+	builder.WriteString(_m.Challenge)
+	builder.WriteString(", ")
+	builder.WriteString("ceremony=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Ceremony))
+	builder.WriteString(", ")
+	builder.WriteString("data=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Data))
+	builder.WriteString(", ")
+	if v := _m.UserID; v != nil {
+		builder.WriteString("user_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("expires_at=")
+	builder.WriteString(_m.ExpiresAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// WebAuthnSessions is a parsable slice of WebAuthnSession.
+type WebAuthnSessions []*WebAuthnSession
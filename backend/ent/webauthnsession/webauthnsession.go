@@ -0,0 +1,139 @@
+// Code generated by ent, DO NOT EDIT.
+
+package webauthnsession
+
+import (
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+const (
+	// Label holds the string label denoting the webauthnsession type in the database.
+	Label = "web_authn_session"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldChallenge holds the string denoting the challenge field in the database.
+	FieldChallenge = "challenge"
+	// FieldCeremony holds the string denoting the ceremony field in the database.
+	FieldCeremony = "ceremony"
+	// FieldData holds the string denoting the data field in the database.
+	FieldData = "data"
+	// FieldUserID holds the string denoting the user_id field in the database.
+	FieldUserID = "user_id"
+	// FieldExpiresAt holds the string denoting the expires_at field in the database.
+	FieldExpiresAt = "expires_at"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// EdgeUser holds the string denoting the user edge name in mutations.
+	EdgeUser = "user"
+	// Table holds the table name of the webauthnsession in the database.
+	Table = "web_authn_sessions"
+	// UserTable is the table that holds the user relation/edge.
+	UserTable = "web_authn_sessions"
+	// UserInverseTable is the table name for the User entity.
+	// It exists in this package in order to avoid circular dependency with the "user" package.
+	UserInverseTable = "users"
+	// UserColumn is the table column denoting the user relation/edge.
+	UserColumn = "user_id"
+)
+
+// Columns holds all SQL columns for webauthnsession fields.
+var Columns = []string{
+	FieldID,
+	FieldChallenge,
+	FieldCeremony,
+	FieldData,
+	FieldUserID,
+	FieldExpiresAt,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ChallengeValidator is a validator for the "challenge" field. It is called by the builders before save.
+	ChallengeValidator func(string) error
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
+
+// Ceremony defines the type for the "ceremony" enum field.
+type Ceremony string
+
+// Ceremony values.
+const (
+	CeremonyRegistration Ceremony = "registration"
+	CeremonyLogin        Ceremony = "login"
+)
+
+func (c Ceremony) String() string {
+	return string(c)
+}
+
+// CeremonyValidator is a validator for the "ceremony" field enum values. It is called by the builders before save.
+func CeremonyValidator(c Ceremony) error {
+	switch c {
+	case CeremonyRegistration, CeremonyLogin:
+		return nil
+	default:
+		return fmt.Errorf("webauthnsession: invalid enum value for ceremony field: %q", c)
+	}
+}
+
+// OrderOption defines the ordering options for the WebAuthnSession queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByChallenge orders the results by the challenge field.
+func ByChallenge(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldChallenge, opts...).ToFunc()
+}
+
+// ByCeremony orders the results by the ceremony field.
+func ByCeremony(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCeremony, opts...).ToFunc()
+}
+
+// ByUserID orders the results by the user_id field.
+func ByUserID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldUserID, opts...).ToFunc()
+}
+
+// ByExpiresAt orders the results by the expires_at field.
+func ByExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldExpiresAt, opts...).ToFunc()
+}
+
+// ByCreatedAt orders the results by the created_at field.
+func ByCreatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCreatedAt, opts...).ToFunc()
+}
+
+// ByUserField orders the results by user field.
+func ByUserField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newUserStep(), sql.OrderByField(field, opts...))
+	}
+}
+func newUserStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(UserInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+	)
+}
@@ -0,0 +1,354 @@
+// Code generated by ent, DO NOT EDIT.
+
+package webauthnsession
+
+import (
+	"app/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLTE(FieldID, id))
+}
+
+// Challenge applies equality check predicate on the "challenge" field. It's identical to ChallengeEQ.
+func Challenge(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldChallenge, v))
+}
+
+// Data applies equality check predicate on the "data" field. It's identical to DataEQ.
+func Data(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldData, v))
+}
+
+// UserID applies equality check predicate on the "user_id" field. It's identical to UserIDEQ.
+func UserID(v int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldUserID, v))
+}
+
+// ExpiresAt applies equality check predicate on the "expires_at" field. It's identical to ExpiresAtEQ.
+func ExpiresAt(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// ChallengeEQ applies the EQ predicate on the "challenge" field.
+func ChallengeEQ(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldChallenge, v))
+}
+
+// ChallengeNEQ applies the NEQ predicate on the "challenge" field.
+func ChallengeNEQ(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldChallenge, v))
+}
+
+// ChallengeIn applies the In predicate on the "challenge" field.
+func ChallengeIn(vs ...string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldChallenge, vs...))
+}
+
+// ChallengeNotIn applies the NotIn predicate on the "challenge" field.
+func ChallengeNotIn(vs ...string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldChallenge, vs...))
+}
+
+// ChallengeGT applies the GT predicate on the "challenge" field.
+func ChallengeGT(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGT(FieldChallenge, v))
+}
+
+// ChallengeGTE applies the GTE predicate on the "challenge" field.
+func ChallengeGTE(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGTE(FieldChallenge, v))
+}
+
+// ChallengeLT applies the LT predicate on the "challenge" field.
+func ChallengeLT(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLT(FieldChallenge, v))
+}
+
+// ChallengeLTE applies the LTE predicate on the "challenge" field.
+func ChallengeLTE(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLTE(FieldChallenge, v))
+}
+
+// ChallengeContains applies the Contains predicate on the "challenge" field.
+func ChallengeContains(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldContains(FieldChallenge, v))
+}
+
+// ChallengeHasPrefix applies the HasPrefix predicate on the "challenge" field.
+func ChallengeHasPrefix(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldHasPrefix(FieldChallenge, v))
+}
+
+// ChallengeHasSuffix applies the HasSuffix predicate on the "challenge" field.
+func ChallengeHasSuffix(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldHasSuffix(FieldChallenge, v))
+}
+
+// ChallengeEqualFold applies the EqualFold predicate on the "challenge" field.
+func ChallengeEqualFold(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEqualFold(FieldChallenge, v))
+}
+
+// ChallengeContainsFold applies the ContainsFold predicate on the "challenge" field.
+func ChallengeContainsFold(v string) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldContainsFold(FieldChallenge, v))
+}
+
+// CeremonyEQ applies the EQ predicate on the "ceremony" field.
+func CeremonyEQ(v Ceremony) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldCeremony, v))
+}
+
+// CeremonyNEQ applies the NEQ predicate on the "ceremony" field.
+func CeremonyNEQ(v Ceremony) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldCeremony, v))
+}
+
+// CeremonyIn applies the In predicate on the "ceremony" field.
+func CeremonyIn(vs ...Ceremony) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldCeremony, vs...))
+}
+
+// CeremonyNotIn applies the NotIn predicate on the "ceremony" field.
+func CeremonyNotIn(vs ...Ceremony) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldCeremony, vs...))
+}
+
+// DataEQ applies the EQ predicate on the "data" field.
+func DataEQ(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldData, v))
+}
+
+// DataNEQ applies the NEQ predicate on the "data" field.
+func DataNEQ(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldData, v))
+}
+
+// DataIn applies the In predicate on the "data" field.
+func DataIn(vs ...[]byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldData, vs...))
+}
+
+// DataNotIn applies the NotIn predicate on the "data" field.
+func DataNotIn(vs ...[]byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldData, vs...))
+}
+
+// DataGT applies the GT predicate on the "data" field.
+func DataGT(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGT(FieldData, v))
+}
+
+// DataGTE applies the GTE predicate on the "data" field.
+func DataGTE(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGTE(FieldData, v))
+}
+
+// DataLT applies the LT predicate on the "data" field.
+func DataLT(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLT(FieldData, v))
+}
+
+// DataLTE applies the LTE predicate on the "data" field.
+func DataLTE(v []byte) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLTE(FieldData, v))
+}
+
+// UserIDEQ applies the EQ predicate on the "user_id" field.
+func UserIDEQ(v int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldUserID, v))
+}
+
+// UserIDNEQ applies the NEQ predicate on the "user_id" field.
+func UserIDNEQ(v int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldUserID, v))
+}
+
+// UserIDIn applies the In predicate on the "user_id" field.
+func UserIDIn(vs ...int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldUserID, vs...))
+}
+
+// UserIDNotIn applies the NotIn predicate on the "user_id" field.
+func UserIDNotIn(vs ...int) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldUserID, vs...))
+}
+
+// UserIDIsNil applies the IsNil predicate on the "user_id" field.
+func UserIDIsNil() predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIsNull(FieldUserID))
+}
+
+// UserIDNotNil applies the NotNil predicate on the "user_id" field.
+func UserIDNotNil() predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotNull(FieldUserID))
+}
+
+// ExpiresAtEQ applies the EQ predicate on the "expires_at" field.
+func ExpiresAtEQ(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtNEQ applies the NEQ predicate on the "expires_at" field.
+func ExpiresAtNEQ(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtIn applies the In predicate on the "expires_at" field.
+func ExpiresAtIn(vs ...time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtNotIn applies the NotIn predicate on the "expires_at" field.
+func ExpiresAtNotIn(vs ...time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtGT applies the GT predicate on the "expires_at" field.
+func ExpiresAtGT(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGT(FieldExpiresAt, v))
+}
+
+// ExpiresAtGTE applies the GTE predicate on the "expires_at" field.
+func ExpiresAtGTE(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtLT applies the LT predicate on the "expires_at" field.
+func ExpiresAtLT(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLT(FieldExpiresAt, v))
+}
+
+// ExpiresAtLTE applies the LTE predicate on the "expires_at" field.
+func ExpiresAtLTE(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLTE(FieldExpiresAt, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.WebAuthnSession) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.WebAuthnSession) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.WebAuthnSession) predicate.WebAuthnSession {
+	return predicate.WebAuthnSession(sql.NotPredicates(p))
+}
@@ -0,0 +1,301 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/user"
+	"app/ent/webauthnsession"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// WebAuthnSessionCreate is the builder for creating a WebAuthnSession entity.
+type WebAuthnSessionCreate struct {
+	config
+	mutation *WebAuthnSessionMutation
+	hooks    []Hook
+}
+
+// SetChallenge sets the "challenge" field.
+func (_c *WebAuthnSessionCreate) SetChallenge(v string) *WebAuthnSessionCreate {
+	_c.mutation.SetChallenge(v)
+	return _c
+}
+
+// SetCeremony sets the "ceremony" field.
+func (_c *WebAuthnSessionCreate) SetCeremony(v webauthnsession.Ceremony) *WebAuthnSessionCreate {
+	_c.mutation.SetCeremony(v)
+	return _c
+}
+
+// SetData sets the "data" field.
+func (_c *WebAuthnSessionCreate) SetData(v []byte) *WebAuthnSessionCreate {
+	_c.mutation.SetData(v)
+	return _c
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *WebAuthnSessionCreate) SetUserID(v int) *WebAuthnSessionCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_c *WebAuthnSessionCreate) SetNillableUserID(v *int) *WebAuthnSessionCreate {
+	if v != nil {
+		_c.SetUserID(*v)
+	}
+	return _c
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_c *WebAuthnSessionCreate) SetExpiresAt(v time.Time) *WebAuthnSessionCreate {
+	_c.mutation.SetExpiresAt(v)
+	return _c
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *WebAuthnSessionCreate) SetCreatedAt(v time.Time) *WebAuthnSessionCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *WebAuthnSessionCreate) SetNillableCreatedAt(v *time.Time) *WebAuthnSessionCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *WebAuthnSessionCreate) SetUser(v *User) *WebAuthnSessionCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// Mutation returns the WebAuthnSessionMutation object of the builder.
+func (_c *WebAuthnSessionCreate) Mutation() *WebAuthnSessionMutation {
+	return _c.mutation
+}
+
+// Save creates the WebAuthnSession in the database.
+func (_c *WebAuthnSessionCreate) Save(ctx context.Context) (*WebAuthnSession, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *WebAuthnSessionCreate) SaveX(ctx context.Context) *WebAuthnSession {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *WebAuthnSessionCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *WebAuthnSessionCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *WebAuthnSessionCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := webauthnsession.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *WebAuthnSessionCreate) check() error {
+	if _, ok := _c.mutation.Challenge(); !ok {
+		return &ValidationError{Name: "challenge", err: errors.New(`ent: missing required field "WebAuthnSession.challenge"`)}
+	}
+	if v, ok := _c.mutation.Challenge(); ok {
+		if err := webauthnsession.ChallengeValidator(v); err != nil {
+			return &ValidationError{Name: "challenge", err: fmt.Errorf(`ent: validator failed for field "WebAuthnSession.challenge": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Ceremony(); !ok {
+		return &ValidationError{Name: "ceremony", err: errors.New(`ent: missing required field "WebAuthnSession.ceremony"`)}
+	}
+	if v, ok := _c.mutation.Ceremony(); ok {
+		if err := webauthnsession.CeremonyValidator(v); err != nil {
+			return &ValidationError{Name: "ceremony", err: fmt.Errorf(`ent: validator failed for field "WebAuthnSession.ceremony": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Data(); !ok {
+		return &ValidationError{Name: "data", err: errors.New(`ent: missing required field "WebAuthnSession.data"`)}
+	}
+	if _, ok := _c.mutation.ExpiresAt(); !ok {
+		return &ValidationError{Name: "expires_at", err: errors.New(`ent: missing required field "WebAuthnSession.expires_at"`)}
+	}
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "WebAuthnSession.created_at"`)}
+	}
+	return nil
+}
+
+func (_c *WebAuthnSessionCreate) sqlSave(ctx context.Context) (*WebAuthnSession, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *WebAuthnSessionCreate) createSpec() (*WebAuthnSession, *sqlgraph.CreateSpec) {
+	var (
+		_node = &WebAuthnSession{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(webauthnsession.Table, sqlgraph.NewFieldSpec(webauthnsession.FieldID, field.TypeInt))
+	)
+	if value, ok := _c.mutation.Challenge(); ok {
+		_spec.SetField(webauthnsession.FieldChallenge, field.TypeString, value)
+		_node.Challenge = value
+	}
+	if value, ok := _c.mutation.Ceremony(); ok {
+		_spec.SetField(webauthnsession.FieldCeremony, field.TypeEnum, value)
+		_node.Ceremony = value
+	}
+	if value, ok := _c.mutation.Data(); ok {
+		_spec.SetField(webauthnsession.FieldData, field.TypeBytes, value)
+		_node.Data = value
+	}
+	if value, ok := _c.mutation.ExpiresAt(); ok {
+		_spec.SetField(webauthnsession.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = value
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(webauthnsession.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   webauthnsession.UserTable,
+			Columns: []string{webauthnsession.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// WebAuthnSessionCreateBulk is the builder for creating many WebAuthnSession entities in bulk.
+type WebAuthnSessionCreateBulk struct {
+	config
+	err      error
+	builders []*WebAuthnSessionCreate
+}
+
+// Save creates the WebAuthnSession entities in the database.
+func (_c *WebAuthnSessionCreateBulk) Save(ctx context.Context) ([]*WebAuthnSession, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*WebAuthnSession, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*WebAuthnSessionMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *WebAuthnSessionCreateBulk) SaveX(ctx context.Context) []*WebAuthnSession {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *WebAuthnSessionCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *WebAuthnSessionCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
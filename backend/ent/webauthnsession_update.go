@@ -0,0 +1,453 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"app/ent/predicate"
+	"app/ent/user"
+	"app/ent/webauthnsession"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// WebAuthnSessionUpdate is the builder for updating WebAuthnSession entities.
+type WebAuthnSessionUpdate struct {
+	config
+	hooks    []Hook
+	mutation *WebAuthnSessionMutation
+}
+
+// Where appends a list predicates to the WebAuthnSessionUpdate builder.
+func (_u *WebAuthnSessionUpdate) Where(ps ...predicate.WebAuthnSession) *WebAuthnSessionUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetChallenge sets the "challenge" field.
+func (_u *WebAuthnSessionUpdate) SetChallenge(v string) *WebAuthnSessionUpdate {
+	_u.mutation.SetChallenge(v)
+	return _u
+}
+
+// SetNillableChallenge sets the "challenge" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdate) SetNillableChallenge(v *string) *WebAuthnSessionUpdate {
+	if v != nil {
+		_u.SetChallenge(*v)
+	}
+	return _u
+}
+
+// SetCeremony sets the "ceremony" field.
+func (_u *WebAuthnSessionUpdate) SetCeremony(v webauthnsession.Ceremony) *WebAuthnSessionUpdate {
+	_u.mutation.SetCeremony(v)
+	return _u
+}
+
+// SetNillableCeremony sets the "ceremony" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdate) SetNillableCeremony(v *webauthnsession.Ceremony) *WebAuthnSessionUpdate {
+	if v != nil {
+		_u.SetCeremony(*v)
+	}
+	return _u
+}
+
+// SetData sets the "data" field.
+func (_u *WebAuthnSessionUpdate) SetData(v []byte) *WebAuthnSessionUpdate {
+	_u.mutation.SetData(v)
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *WebAuthnSessionUpdate) SetUserID(v int) *WebAuthnSessionUpdate {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdate) SetNillableUserID(v *int) *WebAuthnSessionUpdate {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *WebAuthnSessionUpdate) ClearUserID() *WebAuthnSessionUpdate {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *WebAuthnSessionUpdate) SetExpiresAt(v time.Time) *WebAuthnSessionUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdate) SetNillableExpiresAt(v *time.Time) *WebAuthnSessionUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *WebAuthnSessionUpdate) SetUser(v *User) *WebAuthnSessionUpdate {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the WebAuthnSessionMutation object of the builder.
+func (_u *WebAuthnSessionUpdate) Mutation() *WebAuthnSessionMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *WebAuthnSessionUpdate) ClearUser() *WebAuthnSessionUpdate {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *WebAuthnSessionUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *WebAuthnSessionUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *WebAuthnSessionUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *WebAuthnSessionUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *WebAuthnSessionUpdate) check() error {
+	if v, ok := _u.mutation.Challenge(); ok {
+		if err := webauthnsession.ChallengeValidator(v); err != nil {
+			return &ValidationError{Name: "challenge", err: fmt.Errorf(`ent: validator failed for field "WebAuthnSession.challenge": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Ceremony(); ok {
+		if err := webauthnsession.CeremonyValidator(v); err != nil {
+			return &ValidationError{Name: "ceremony", err: fmt.Errorf(`ent: validator failed for field "WebAuthnSession.ceremony": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *WebAuthnSessionUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(webauthnsession.Table, webauthnsession.Columns, sqlgraph.NewFieldSpec(webauthnsession.FieldID, field.TypeInt))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Challenge(); ok {
+		_spec.SetField(webauthnsession.FieldChallenge, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Ceremony(); ok {
+		_spec.SetField(webauthnsession.FieldCeremony, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Data(); ok {
+		_spec.SetField(webauthnsession.FieldData, field.TypeBytes, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(webauthnsession.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   webauthnsession.UserTable,
+			Columns: []string{webauthnsession.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   webauthnsession.UserTable,
+			Columns: []string{webauthnsession.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{webauthnsession.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// WebAuthnSessionUpdateOne is the builder for updating a single WebAuthnSession entity.
+type WebAuthnSessionUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *WebAuthnSessionMutation
+}
+
+// SetChallenge sets the "challenge" field.
+func (_u *WebAuthnSessionUpdateOne) SetChallenge(v string) *WebAuthnSessionUpdateOne {
+	_u.mutation.SetChallenge(v)
+	return _u
+}
+
+// SetNillableChallenge sets the "challenge" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdateOne) SetNillableChallenge(v *string) *WebAuthnSessionUpdateOne {
+	if v != nil {
+		_u.SetChallenge(*v)
+	}
+	return _u
+}
+
+// SetCeremony sets the "ceremony" field.
+func (_u *WebAuthnSessionUpdateOne) SetCeremony(v webauthnsession.Ceremony) *WebAuthnSessionUpdateOne {
+	_u.mutation.SetCeremony(v)
+	return _u
+}
+
+// SetNillableCeremony sets the "ceremony" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdateOne) SetNillableCeremony(v *webauthnsession.Ceremony) *WebAuthnSessionUpdateOne {
+	if v != nil {
+		_u.SetCeremony(*v)
+	}
+	return _u
+}
+
+// SetData sets the "data" field.
+func (_u *WebAuthnSessionUpdateOne) SetData(v []byte) *WebAuthnSessionUpdateOne {
+	_u.mutation.SetData(v)
+	return _u
+}
+
+// SetUserID sets the "user_id" field.
+func (_u *WebAuthnSessionUpdateOne) SetUserID(v int) *WebAuthnSessionUpdateOne {
+	_u.mutation.SetUserID(v)
+	return _u
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdateOne) SetNillableUserID(v *int) *WebAuthnSessionUpdateOne {
+	if v != nil {
+		_u.SetUserID(*v)
+	}
+	return _u
+}
+
+// ClearUserID clears the value of the "user_id" field.
+func (_u *WebAuthnSessionUpdateOne) ClearUserID() *WebAuthnSessionUpdateOne {
+	_u.mutation.ClearUserID()
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *WebAuthnSessionUpdateOne) SetExpiresAt(v time.Time) *WebAuthnSessionUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *WebAuthnSessionUpdateOne) SetNillableExpiresAt(v *time.Time) *WebAuthnSessionUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_u *WebAuthnSessionUpdateOne) SetUser(v *User) *WebAuthnSessionUpdateOne {
+	return _u.SetUserID(v.ID)
+}
+
+// Mutation returns the WebAuthnSessionMutation object of the builder.
+func (_u *WebAuthnSessionUpdateOne) Mutation() *WebAuthnSessionMutation {
+	return _u.mutation
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (_u *WebAuthnSessionUpdateOne) ClearUser() *WebAuthnSessionUpdateOne {
+	_u.mutation.ClearUser()
+	return _u
+}
+
+// Where appends a list predicates to the WebAuthnSessionUpdate builder.
+func (_u *WebAuthnSessionUpdateOne) Where(ps ...predicate.WebAuthnSession) *WebAuthnSessionUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *WebAuthnSessionUpdateOne) Select(field string, fields ...string) *WebAuthnSessionUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated WebAuthnSession entity.
+func (_u *WebAuthnSessionUpdateOne) Save(ctx context.Context) (*WebAuthnSession, error) {
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *WebAuthnSessionUpdateOne) SaveX(ctx context.Context) *WebAuthnSession {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *WebAuthnSessionUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *WebAuthnSessionUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *WebAuthnSessionUpdateOne) check() error {
+	if v, ok := _u.mutation.Challenge(); ok {
+		if err := webauthnsession.ChallengeValidator(v); err != nil {
+			return &ValidationError{Name: "challenge", err: fmt.Errorf(`ent: validator failed for field "WebAuthnSession.challenge": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Ceremony(); ok {
+		if err := webauthnsession.CeremonyValidator(v); err != nil {
+			return &ValidationError{Name: "ceremony", err: fmt.Errorf(`ent: validator failed for field "WebAuthnSession.ceremony": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (_u *WebAuthnSessionUpdateOne) sqlSave(ctx context.Context) (_node *WebAuthnSession, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(webauthnsession.Table, webauthnsession.Columns, sqlgraph.NewFieldSpec(webauthnsession.FieldID, field.TypeInt))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "WebAuthnSession.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, webauthnsession.FieldID)
+		for _, f := range fields {
+			if !webauthnsession.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != webauthnsession.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.Challenge(); ok {
+		_spec.SetField(webauthnsession.FieldChallenge, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.Ceremony(); ok {
+		_spec.SetField(webauthnsession.FieldCeremony, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.Data(); ok {
+		_spec.SetField(webauthnsession.FieldData, field.TypeBytes, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(webauthnsession.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.UserCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   webauthnsession.UserTable,
+			Columns: []string{webauthnsession.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   webauthnsession.UserTable,
+			Columns: []string{webauthnsession.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &WebAuthnSession{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{webauthnsession.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,146 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/domain"
+	"app/internal/auth"
+	"app/internal/middleware"
+	"app/internal/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	Service *Service
+}
+
+func NewHandler(s *Service) *Handler {
+	return &Handler{Service: s}
+}
+
+// Create godoc
+// @Summary      Create API key
+// @Description  Create a named API key for scripts and CI pipelines. Send it as "Authorization: Bearer sk_..." wherever an access token is accepted; requests made with it act for the user on routes that accept API keys, limited by its scopes. The key is only returned in this response. API keys and OAuth client tokens cannot create keys.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateAPIKeyDTO true "Name, scopes and expiry"
+// @Success      201 {object} domain.CreateAPIKeyResponse "API key created"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body, name, scope or expiry"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Request made with an API key or OAuth client token"
+// @Failure      409 {object} domain.ErrorResponse "Too many API keys"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/api-keys [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	// A leaked key or client token must not be able to outlive its
+	// revocation.
+	if claims, _ := middleware.ClaimsFromContext(r.Context()); claims.Caller() != auth.SubjectUser {
+		response.Error(w, http.StatusForbidden, "api keys can only be created by the user")
+		return
+	}
+
+	var dto domain.CreateAPIKeyDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	k, key, err := h.Service.Create(r.Context(), userID, Spec{
+		Name:      dto.Name,
+		Scopes:    dto.Scopes,
+		ExpiresAt: dto.ExpiresAt,
+	})
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, domain.CreateAPIKeyResponse{
+		APIKeyResponse: ToAPIKeyResponse(k),
+		Key:            key,
+	})
+}
+
+// List godoc
+// @Summary      List API keys
+// @Description  List the API keys of the authenticated user, oldest first, including expired ones
+// @Tags         api-keys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.APIKeyResponse "API keys"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/api-keys [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	keys, err := h.Service.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToAPIKeyResponses(keys))
+}
+
+// Revoke godoc
+// @Summary      Revoke API key
+// @Description  Delete an API key. Requests made with it are rejected from now on.
+// @Tags         api-keys
+// @Security     BearerAuth
+// @Param        id path int true "API key ID"
+// @Success      204 "API key revoked"
+// @Failure      400 {object} domain.ErrorResponse "Invalid API key id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "API key not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/api-keys/{id} [delete]
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid api key id")
+		return
+	}
+
+	if err := h.Service.Revoke(r.Context(), userID, id); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondWithError maps service errors to responses.
+func respondWithError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidSpec):
+		response.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, ErrTooManyKeys):
+		response.Error(w, http.StatusConflict, "too many api keys, revoke one first")
+	case errors.Is(err, ErrKeyNotFound):
+		response.Error(w, http.StatusNotFound, "api key not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
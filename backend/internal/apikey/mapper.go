@@ -0,0 +1,26 @@
+package apikey
+
+import (
+	"app/domain"
+	"app/ent"
+)
+
+func ToAPIKeyResponse(k *ent.APIKey) domain.APIKeyResponse {
+	return domain.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     KeyPrefix + k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
+
+func ToAPIKeyResponses(keys []*ent.APIKey) []domain.APIKeyResponse {
+	resp := make([]domain.APIKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = ToAPIKeyResponse(k)
+	}
+	return resp
+}
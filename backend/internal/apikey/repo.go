@@ -0,0 +1,104 @@
+package apikey
+
+import (
+	"app/ent"
+	"app/ent/apikey"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrKeyNotFound = errors.New("api key not found")
+	ErrKeyExists   = errors.New("api key already exists")
+)
+
+// Repository defines the interface for API key data access.
+type Repository interface {
+	Create(ctx context.Context, userID int, name string, prefix string, secretHash string, scopes []string, expiresAt *time.Time) (*ent.APIKey, error)
+	GetByHash(ctx context.Context, secretHash string) (*ent.APIKey, error)
+	List(ctx context.Context, userID int) ([]*ent.APIKey, error)
+	Count(ctx context.Context, userID int) (int, error)
+	Delete(ctx context.Context, userID int, id int) error
+	Touch(ctx context.Context, id int, at time.Time) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// Create stores a new API key of a user.
+func (r *PostgresRepo) Create(ctx context.Context, userID int, name string, prefix string, secretHash string, scopes []string, expiresAt *time.Time) (*ent.APIKey, error) {
+	k, err := r.Db.Client.APIKey.Create().
+		SetUserID(userID).
+		SetName(name).
+		SetPrefix(prefix).
+		SetSecretHash(secretHash).
+		SetScopes(scopes).
+		SetNillableExpiresAt(expiresAt).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrKeyExists
+	}
+	return k, err
+}
+
+// GetByHash returns the API key with the given hash.
+func (r *PostgresRepo) GetByHash(ctx context.Context, secretHash string) (*ent.APIKey, error) {
+	k, err := r.Db.Client.APIKey.Query().
+		Where(apikey.SecretHashEQ(secretHash)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return k, nil
+}
+
+// List returns the API keys of a user, oldest first.
+func (r *PostgresRepo) List(ctx context.Context, userID int) ([]*ent.APIKey, error) {
+	return r.Db.Client.APIKey.Query().
+		Where(apikey.UserIDEQ(userID)).
+		Order(ent.Asc(apikey.FieldCreatedAt), ent.Asc(apikey.FieldID)).
+		All(ctx)
+}
+
+// Count returns the number of API keys of a user, including expired ones.
+func (r *PostgresRepo) Count(ctx context.Context, userID int) (int, error) {
+	return r.Db.Client.APIKey.Query().
+		Where(apikey.UserIDEQ(userID)).
+		Count(ctx)
+}
+
+// Delete revokes an API key of a user.
+func (r *PostgresRepo) Delete(ctx context.Context, userID int, id int) error {
+	n, err := r.Db.Client.APIKey.Delete().
+		Where(
+			apikey.IDEQ(id),
+			apikey.UserIDEQ(userID),
+		).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// Touch records the last use of an API key.
+func (r *PostgresRepo) Touch(ctx context.Context, id int, at time.Time) error {
+	return r.Db.Client.APIKey.UpdateOneID(id).
+		SetLastUsedAt(at).
+		Exec(ctx)
+}
@@ -0,0 +1,232 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/refreshtoken"
+	"app/internal/user"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/gommon/log"
+)
+
+// KeyPrefix starts every API key, so that keys are told from access tokens
+// and found by secret scanners.
+const KeyPrefix = "sk_"
+
+// MaxNameLength limits the label a user gives a key.
+const MaxNameLength = 64
+
+// touchInterval limits how often the last use of a key is written, so that
+// a busy script does not cause a write per request.
+const touchInterval = time.Minute
+
+var (
+	ErrInvalidKey  = errors.New("invalid or expired api key")
+	ErrInvalidSpec = errors.New("invalid api key")
+	ErrTooManyKeys = errors.New("too many api keys")
+)
+
+// scopePattern matches scopes such as orders:read.
+var scopePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]*$`)
+
+// Config controls API keys.
+type Config struct {
+	// MaxPerUser limits the keys a user can have. Zero disables the limit.
+	MaxPerUser int
+	// MaxTTL is the longest lifetime a key may have; keys created without
+	// an expiry get it. Zero allows keys that never expire.
+	MaxTTL time.Duration
+}
+
+// Spec describes an API key to create.
+type Spec struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// Service manages API keys, which let scripts and CI pipelines act as the
+// user who created them without logging in. A key has the form
+// sk_<prefix>_<secret>; only the prefix is stored in clear to identify it,
+// the whole key only as a SHA-256 hash. Requests with a key carry none of
+// the user's roles; like an OAuth token, a key may only call the routes that
+// accept API keys, within its scopes.
+type Service struct {
+	Repo   Repository
+	Users  *user.Service
+	Config Config
+}
+
+func NewService(repo Repository, users *user.Service, cfg Config) *Service {
+	return &Service{
+		Repo:   repo,
+		Users:  users,
+		Config: cfg,
+	}
+}
+
+// Create stores a new API key of a user and returns it together with the
+// key, which is not stored and cannot be shown again.
+func (s *Service) Create(ctx context.Context, userID int, spec Spec) (*ent.APIKey, string, error) {
+	name := strings.TrimSpace(spec.Name)
+	if name == "" || len(name) > MaxNameLength {
+		return nil, "", fmt.Errorf("%w: name is required and at most %d characters", ErrInvalidSpec, MaxNameLength)
+	}
+	for _, scope := range spec.Scopes {
+		if !scopePattern.MatchString(scope) {
+			return nil, "", fmt.Errorf("%w: invalid scope %q", ErrInvalidSpec, scope)
+		}
+	}
+	scopes := slices.Clone(spec.Scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	now := time.Now()
+	expiresAt := spec.ExpiresAt
+	switch {
+	case expiresAt != nil && !expiresAt.After(now):
+		return nil, "", fmt.Errorf("%w: expiry must be in the future", ErrInvalidSpec)
+	case s.Config.MaxTTL > 0 && expiresAt == nil:
+		max := now.Add(s.Config.MaxTTL)
+		expiresAt = &max
+	case s.Config.MaxTTL > 0 && expiresAt.After(now.Add(s.Config.MaxTTL)):
+		return nil, "", fmt.Errorf("%w: expiry must be within %s", ErrInvalidSpec, s.Config.MaxTTL)
+	}
+
+	if s.Config.MaxPerUser > 0 {
+		n, err := s.Repo.Count(ctx, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		if n >= s.Config.MaxPerUser {
+			return nil, "", ErrTooManyKeys
+		}
+	}
+
+	prefix, err := generatePrefix()
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := refreshtoken.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+	key := KeyPrefix + prefix + "_" + secret
+
+	k, err := s.Repo.Create(ctx, userID, name, prefix, refreshtoken.Hash(key), scopes, expiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+	return k, key, nil
+}
+
+// List returns the API keys of a user.
+func (s *Service) List(ctx context.Context, userID int) ([]*ent.APIKey, error) {
+	return s.Repo.List(ctx, userID)
+}
+
+// Revoke deletes an API key of a user. It stops working at once.
+func (s *Service) Revoke(ctx context.Context, userID int, id int) error {
+	return s.Repo.Delete(ctx, userID, id)
+}
+
+// Verify checks an API key and returns the claims of a request made with it:
+// its user and its scopes.
+func (s *Service) Verify(ctx context.Context, key string) (*auth.Claims, error) {
+	if !IsKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	k, err := s.Repo.GetByHash(ctx, refreshtoken.Hash(key))
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(now) {
+		return nil, ErrInvalidKey
+	}
+
+	u, err := s.Users.GetByID(ctx, k.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= touchInterval {
+		if err := s.Repo.Touch(ctx, k.ID, now); err != nil {
+			log.Error("Failed to record api key use ", err.Error())
+		}
+	}
+
+	return &auth.Claims{
+		SubjectType: auth.SubjectUser,
+		UserID:      u.ID,
+		Scope:       strings.Join(k.Scopes, " "),
+		APIKeyID:    k.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(u.ID),
+			ExpiresAt: toNumericDate(k.ExpiresAt),
+		},
+	}, nil
+}
+
+// IsKey reports whether credential has the form of an API key.
+func IsKey(credential string) bool {
+	return strings.HasPrefix(credential, KeyPrefix)
+}
+
+// Authenticator verifies the credential of a request: an API key if it has
+// the key prefix, an access token otherwise. It lets middleware.Auth accept
+// both.
+type Authenticator struct {
+	JWT  *auth.JWT
+	Keys *Service
+}
+
+func NewAuthenticator(jwt *auth.JWT, keys *Service) *Authenticator {
+	return &Authenticator{JWT: jwt, Keys: keys}
+}
+
+func (a *Authenticator) Verify(ctx context.Context, credential string) (*auth.Claims, error) {
+	if IsKey(credential) {
+		return a.Keys.Verify(ctx, credential)
+	}
+	return a.JWT.Parse(credential)
+}
+
+// generatePrefix returns 8 random lowercase characters identifying a key.
+func generatePrefix() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key prefix: %w", err)
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b)), nil
+}
+
+func toNumericDate(t *time.Time) *jwt.NumericDate {
+	if t == nil {
+		return nil
+	}
+	return jwt.NewNumericDate(*t)
+}
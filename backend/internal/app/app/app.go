@@ -1,20 +1,37 @@
 package app
 
 import (
+	"context"
+
+	"app/internal/apikey"
 	"app/internal/auth"
 	"app/internal/config"
 	"app/internal/db"
+	"app/internal/federation"
+	"app/internal/lockout"
+	"app/internal/magiclink"
+	"app/internal/mailer"
+	appmiddleware "app/internal/middleware"
+	"app/internal/oidc"
+	"app/internal/passkey"
+	"app/internal/password"
+	"app/internal/passwordreset"
+	"app/internal/rbac"
 	"app/internal/refreshtoken"
 	"app/internal/router"
+	"app/internal/serviceclient"
 	"app/internal/user"
 
 	"github.com/labstack/gommon/log"
 )
 
 type App struct {
-	Router *router.Router
-	Cfg    *config.Config
-	Db     *db.Db
+	Router   *router.Router
+	Cfg      *config.Config
+	Db       *db.Db
+	JWT      *auth.JWT
+	KeyStore *auth.KeyStore
+	Janitor  *refreshtoken.Janitor
 }
 
 func NewApp() *App {
@@ -27,22 +44,240 @@ func NewApp() *App {
 	log.Info("Open connect to db", db.Client.Schema)
 
 	// TODO: jwt
-	jwtSvc := auth.New(cfg.Jwt.Secret, cfg.Jwt.AccessTtlHours)
+	keyStore, keyRing, err := loadKeyRing(cfg.Jwt)
+	if err != nil {
+		log.Fatal("Failed to load jwt signing keys", err.Error())
+	}
+	jwtSvc := auth.New(keyRing, auth.Config{
+		TTL:      cfg.Jwt.AccessTtlHours,
+		Issuer:   cfg.Jwt.Issuer,
+		Audience: cfg.Jwt.Audience,
+		Leeway:   cfg.Jwt.Leeway,
+	})
+	authHandler := auth.NewHandler(jwtSvc)
 
 	// TODO: refresh token
 	refreshTokenRepo := refreshtoken.NewPostgresRepo(db)
-	refreshTokenService := refreshtoken.NewService(refreshTokenRepo, cfg.Jwt.RefreshTtlHours)
+	refreshTokenService := refreshtoken.NewService(refreshTokenRepo, refreshtoken.Config{
+		TTL:              cfg.Jwt.RefreshTtlHours,
+		RevokeAllOnReuse: cfg.Jwt.RevokeOnReuse,
+		RotationGrace:    cfg.Jwt.RotationGrace,
+		MaxAge:           cfg.Jwt.SessionMaxAge,
+		IdleTimeout:      cfg.Jwt.SessionIdle,
+	})
+	sessionHandler := refreshtoken.NewHandler(refreshTokenService)
+	janitor := refreshtoken.NewJanitor(refreshTokenRepo, cfg.Janitor.Retention, cfg.Janitor.BatchSize)
+
+	// TODO: roles
+	rbacRepo := rbac.NewPostgresRepo(db)
+	rbacService := rbac.NewService(rbacRepo)
+	if err := rbacService.EnsureDefaults(context.Background()); err != nil {
+		log.Fatal("Failed to create default roles", err.Error())
+	}
+	rbacHandler := rbac.NewHandler(rbacService)
 
 	// TODO: init chi
 	userRepo := user.NewPostgresRepo(db)
-	userService := user.NewSercie(userRepo)
-	userHandler := user.NewHandler(userService, jwtSvc, refreshTokenService)
+	hasher, err := password.New(cfg.Password.Algorithm, cfg.Password.BcryptCost, password.Argon2idParams{
+		Memory:      cfg.Password.Argon2Memory,
+		Iterations:  cfg.Password.Argon2Iterations,
+		Parallelism: cfg.Password.Argon2Parallelism,
+	})
+	if err != nil {
+		log.Fatal("Failed to configure password hashing", err.Error())
+	}
+	policy, err := loadPasswordPolicy(cfg.Password)
+	if err != nil {
+		log.Fatal("Failed to load password policy", err.Error())
+	}
+	mail := newMailer(cfg.Mail)
+	lockoutService := lockout.NewService(lockout.NewPostgresRepo(db), userRepo, jwtSvc, mail, lockout.Config{
+		Account:           lockout.Limit{Free: cfg.Lockout.FreeAttempts, Lockout: cfg.Lockout.MaxFailures},
+		IP:                lockout.Limit{Free: cfg.Lockout.IPFreeAttempts, Lockout: cfg.Lockout.IPMaxFailures},
+		BaseDelay:         cfg.Lockout.BaseDelay,
+		MaxDelay:          cfg.Lockout.MaxDelay,
+		Duration:          cfg.Lockout.Duration,
+		Window:            cfg.Lockout.Window,
+		UnlockTTL:         cfg.Lockout.UnlockTtl,
+		UnlockLinkBaseURL: cfg.Lockout.UnlockUrl,
+	})
+	lockoutHandler := lockout.NewHandler(lockoutService)
+	userService := user.NewSercie(userRepo, hasher, user.Config{
+		RequireVerifiedEmail: cfg.Auth.RequireVerifiedEmail,
+		PasswordPolicy:       policy,
+		LoginThrottle:        lockoutService,
+	})
+	verifier := user.NewVerifier(userRepo, jwtSvc, mail, user.VerifierConfig{
+		TTL:         cfg.Auth.VerifyEmailTtl,
+		LinkBaseURL: cfg.Auth.VerifyEmailUrl,
+	})
+	mfa := user.NewMFA(userRepo, jwtSvc, lockoutService, user.MFAConfig{
+		Issuer:       cfg.Auth.MfaIssuer,
+		ChallengeTTL: cfg.Auth.MfaChallengeTtl,
+	})
+	userHandler := user.NewHandler(userService, jwtSvc, refreshTokenService, verifier, mfa)
+
+	// TODO: password reset
+	passwordResetRepo := passwordreset.NewPostgresRepo(db)
+	passwordResetService := passwordreset.NewService(passwordResetRepo, userService, refreshTokenService, mail, passwordreset.Config{
+		TTL:         cfg.Auth.PasswordResetTtl,
+		LinkBaseURL: cfg.Auth.PasswordResetUrl,
+	})
+	passwordResetHandler := passwordreset.NewHandler(passwordResetService)
+
+	// TODO: passkeys
+	passkeyService, err := passkey.NewService(passkey.NewPostgresRepo(db), userService, passkey.Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+		Timeout:       cfg.WebAuthn.Timeout,
+	})
+	if err != nil {
+		log.Fatal("Failed to configure passkeys", err.Error())
+	}
+	passkeyHandler := passkey.NewHandler(passkeyService, userHandler)
+
+	// TODO: magic link
+	magicLinkService := magiclink.NewService(magiclink.NewPostgresRepo(db), userService, mail, magiclink.Config{
+		TTL:               cfg.MagicLink.Ttl,
+		LinkBaseURL:       cfg.MagicLink.Url,
+		MaxAttempts:       cfg.MagicLink.MaxAttempts,
+		MaxPerWindow:      cfg.MagicLink.MaxPerWindow,
+		Window:            cfg.MagicLink.Window,
+		AllowRegistration: cfg.MagicLink.AllowRegistration,
+	})
+	magicLinkHandler := magiclink.NewHandler(magicLinkService, userHandler)
+
+	// TODO: service clients
+	serviceClientService := serviceclient.NewService(serviceclient.NewPostgresRepo(db), jwtSvc)
+
+	// TODO: openid connect
+	if keyRing.Current().IsSymmetric() {
+		log.Warn("JWT is signed with a shared secret, OpenID Connect clients cannot verify ID tokens")
+	}
+	oidcService := oidc.NewService(oidc.NewPostgresRepo(db), userService, jwtSvc, refreshTokenService, oidc.Config{
+		LoginURL:   cfg.OIDC.LoginUrl,
+		CodeTTL:    cfg.OIDC.CodeTtl,
+		IDTokenTTL: cfg.OIDC.IDTokenTtl,
+	})
+	oidcHandler := oidc.NewHandler(oidcService, serviceClientService)
+
+	// TODO: federated login
+	providers := make([]federation.ProviderConfig, len(cfg.Federation.Providers))
+	for i, p := range cfg.Federation.Providers {
+		providers[i] = federation.ProviderConfig{
+			Name:         p.Name,
+			DisplayName:  p.DisplayName,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientId,
+			ClientSecret: p.ClientSecret,
+			Scopes:       p.Scopes,
+		}
+	}
+	federationService, err := federation.NewService(federation.NewPostgresRepo(db), userService, federation.Config{
+		CallbackURL:        cfg.Federation.CallbackUrl,
+		StateTTL:           cfg.Federation.StateTtl,
+		AllowRegistration:  cfg.Federation.AllowRegistration,
+		LinkVerifiedEmails: cfg.Federation.LinkVerifiedEmails,
+		Providers:          providers,
+	})
+	if err != nil {
+		log.Fatal("Failed to configure identity providers", err.Error())
+	}
+	federationHandler := federation.NewHandler(federationService, userHandler)
+
+	// TODO: api keys
+	apiKeyService := apikey.NewService(apikey.NewPostgresRepo(db), userService, apikey.Config{
+		MaxPerUser: cfg.APIKeys.MaxPerUser,
+		MaxTTL:     cfg.APIKeys.MaxTtl,
+	})
+	apiKeyHandler := apikey.NewHandler(apiKeyService)
 
-	r := router.NewRouter(userHandler)
+	trustedProxies, err := appmiddleware.ParseProxies(cfg.Http.TrustedProxies)
+	if err != nil {
+		log.Fatal("Failed to parse trusted proxies", err.Error())
+	}
+	r := router.NewRouter(apikey.NewAuthenticator(jwtSvc, apiKeyService), userHandler, authHandler, rbacHandler, sessionHandler, passwordResetHandler, lockoutHandler, passkeyHandler, magicLinkHandler, oidcHandler, federationHandler, apiKeyHandler, trustedProxies)
 
 	return &App{
-		Router: r,
-		Cfg:    cfg,
-		Db:     db,
+		Router:   r,
+		Cfg:      cfg,
+		Db:       db,
+		JWT:      jwtSvc,
+		KeyStore: keyStore,
+		Janitor:  janitor,
+	}
+}
+
+// loadPasswordPolicy builds the password policy, loading the breached
+// password corpus if one is configured.
+func loadPasswordPolicy(cfg config.Password) (*password.Policy, error) {
+	policy := &password.Policy{
+		MinLength:     cfg.MinLength,
+		MaxLength:     cfg.MaxLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+	}
+	// Reject what bcrypt cannot hash up front, instead of failing to hash a
+	// password the policy accepted.
+	if cfg.Algorithm == "bcrypt" {
+		policy.MaxBytes = password.BcryptMaxBytes
+	}
+
+	if cfg.BreachedFile != "" {
+		list, err := password.LoadBreachedList(cfg.BreachedFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Loaded breached password hashes ", list.Len())
+		policy.Breached = list
+	}
+
+	return policy, nil
+}
+
+// newMailer returns an SMTP mailer, or one writing to the log when no SMTP
+// host is configured.
+func newMailer(cfg config.Mail) mailer.Mailer {
+	if cfg.Host == "" {
+		log.Warn("No mail host configured, emails are written to the log")
+		return mailer.NewLog()
+	}
+	return mailer.NewSMTP(mailer.SMTPConfig{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	})
+}
+
+// loadKeyRing builds the signing key ring. A configured keys directory takes
+// precedence and is returned as a store so the ring can be reloaded; otherwise
+// a single key is loaded from the PEM file or the shared HMAC secret.
+func loadKeyRing(cfg config.Jwt) (*auth.KeyStore, *auth.KeyRing, error) {
+	if cfg.KeysDir != "" {
+		store := auth.NewKeyStore(cfg.KeysDir)
+		ring, err := store.Load()
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, ring, nil
+	}
+
+	var (
+		key *auth.Key
+		err error
+	)
+	if cfg.PrivateKeyPath != "" {
+		key, err = auth.LoadPrivateKey(cfg.KeyID, cfg.PrivateKeyPath)
+	} else {
+		key, err = auth.NewHMACKey(cfg.KeyID, cfg.Secret)
+	}
+	if err != nil {
+		return nil, nil, err
 	}
+	return nil, auth.NewKeyRing(key), nil
 }
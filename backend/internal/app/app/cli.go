@@ -0,0 +1,54 @@
+package app
+
+import (
+	"fmt"
+	"os"
+)
+
+// Command runs a maintenance subcommand and returns the process exit code.
+// It is used when the binary is started with arguments, for example
+// `api keys rotate`.
+func Command(args []string) int {
+	switch args[0] {
+	case "clients":
+		return clientsCommand(args[1:])
+	case "keys":
+		return keysCommand(args[1:])
+	case "roles":
+		return rolesCommand(args[1:])
+	case "service-clients":
+		return serviceClientsCommand(args[1:])
+	case "tokens":
+		return tokensCommand(args[1:])
+	case "help", "-h", "-help", "--help":
+		usage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		usage()
+		return 2
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: api [command]
+
+Without a command the HTTP server is started.
+
+Commands:
+  clients create                 Register an OpenID Connect client and print its secret
+  clients list                   List registered OpenID Connect clients
+  clients delete                 Delete a client and end its sessions
+  keys generate                  Generate a new signing key (published, not yet signing)
+  keys rotate                    Promote a key to signing key and retire the previous one
+  keys list                      List keys in the key store
+  keys prune                     Delete retired keys past their removal time
+  roles grant                    Grant a role to a user, e.g. to bootstrap the first admin
+  roles revoke                   Revoke a role from a user
+  service-clients create         Register a service client and print its secret
+  service-clients list           List service clients
+  service-clients rotate-secret  Replace the secret of a service client
+  service-clients delete         Delete a service client
+  tokens purge                   Delete expired and revoked refresh tokens past retention
+`)
+}
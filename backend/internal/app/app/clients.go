@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"app/internal/config"
+	"app/internal/db"
+	"app/internal/oidc"
+	"app/internal/refreshtoken"
+)
+
+func clientsCommand(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	fs := flag.NewFlagSet("clients "+args[0], flag.ContinueOnError)
+
+	var run func(ctx context.Context, clients *oidc.Clients) error
+	switch args[0] {
+	case "create":
+		name := fs.String("name", "", "name shown on the consent prompt")
+		var redirectURIs stringList
+		fs.Var(&redirectURIs, "redirect-uri", "allowed redirect URI; repeat for several")
+		scopes := fs.String("scopes", strings.Join(oidc.SupportedScopes, " "), "space-separated scopes the client may request")
+		public := fs.Bool("public", false, "public client (SPA, mobile app) without a secret")
+		skipConsent := fs.Bool("skip-consent", false, "first-party client that does not ask for consent")
+		run = func(ctx context.Context, clients *oidc.Clients) error {
+			client, secret, err := clients.Register(ctx, oidc.ClientSpec{
+				Name:         *name,
+				RedirectURIs: redirectURIs,
+				Scopes:       strings.Fields(*scopes),
+				Public:       *public,
+				SkipConsent:  *skipConsent,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("client_id:     %s\n", client.ClientID)
+			if secret != "" {
+				fmt.Printf("client_secret: %s\n", secret)
+				fmt.Println("the secret is not stored and cannot be shown again")
+			}
+			return nil
+		}
+	case "list":
+		run = func(ctx context.Context, clients *oidc.Clients) error {
+			list, err := clients.List(ctx)
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "CLIENT ID\tNAME\tTYPE\tSCOPES\tREDIRECT URIS\tCREATED")
+			for _, c := range list {
+				typ := "confidential"
+				if oidc.IsPublic(c) {
+					typ = "public"
+				}
+				if c.SkipConsent {
+					typ += ", first-party"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", c.ClientID, c.Name, typ, strings.Join(c.Scopes, " "), strings.Join(c.RedirectUris, " "), c.CreatedAt.Format(time.RFC3339))
+			}
+			return tw.Flush()
+		}
+	case "delete":
+		clientID := fs.String("client-id", "", "client to delete")
+		run = func(ctx context.Context, clients *oidc.Clients) error {
+			if *clientID == "" {
+				return fmt.Errorf("-client-id is required")
+			}
+			if err := clients.Delete(ctx, *clientID); err != nil {
+				return err
+			}
+			fmt.Printf("deleted client %s and ended its sessions\n", *clientID)
+			return nil
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown clients command %q\n\n", args[0])
+		usage()
+		return 2
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg := config.MustLoad()
+	db := db.NewDbClient(cfg.Database.Url)
+	defer db.Close()
+
+	sessions := refreshtoken.NewService(refreshtoken.NewPostgresRepo(db), refreshtoken.Config{})
+	if err := run(context.Background(), oidc.NewClients(oidc.NewPostgresRepo(db), sessions)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// stringList is a flag that can be given several times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/labstack/gommon/log"
@@ -10,6 +11,15 @@ func Run() {
 	app := NewApp()
 	defer app.Db.Close()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if app.KeyStore != nil {
+		go app.KeyStore.Watch(ctx, app.JWT.Keys(), app.Cfg.Jwt.KeysReload)
+	}
+
+	go app.Janitor.Run(ctx, app.Cfg.Janitor.Interval)
+
 	log.Info("Start server on ", app.Cfg.Http.Port)
 
 	if err := http.ListenAndServe(app.Cfg.Http.Port, app.Router.Handler()); err != nil {
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"app/internal/auth"
+	"app/internal/config"
+)
+
+func keysCommand(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	cfg := config.MustLoad()
+
+	fs := flag.NewFlagSet("keys "+args[0], flag.ContinueOnError)
+	dir := fs.String("dir", cfg.Jwt.KeysDir, "key store directory")
+
+	var run func(store *auth.KeyStore) error
+	switch args[0] {
+	case "generate":
+		alg := fs.String("alg", "ES256", "signing algorithm: RS256, ES256, ES384, ES512 or EdDSA")
+		run = func(store *auth.KeyStore) error {
+			key, err := store.Generate(*alg)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("generated %s key %s\n", key.Method.Alg(), key.ID)
+			return nil
+		}
+	case "rotate":
+		kid := fs.String("kid", "", "key to promote (default: newest staged key)")
+		// Tokens signed by the old key must stay verifiable for their whole
+		// lifetime, and other instances keep signing with it until they reload.
+		retireAfter := fs.Duration("retire-after", cfg.Jwt.AccessTtlHours+cfg.Jwt.KeysReload, "how long the previous key stays valid for verification")
+		run = func(store *auth.KeyStore) error {
+			current, err := store.Rotate(*kid, *retireAfter)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("signing with %s, previous key retires in %s\n", current, *retireAfter)
+			return nil
+		}
+	case "list":
+		run = func(store *auth.KeyStore) error {
+			keys, err := store.List()
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "KID\tALG\tSTATUS\tCREATED\tRETIRES")
+			for _, k := range keys {
+				status, retires := "staged", "-"
+				switch {
+				case k.Current:
+					status = "current"
+				case k.RetireAt != nil:
+					status = "retired"
+					retires = k.RetireAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", k.ID, k.Alg, status, k.CreatedAt.Format(time.RFC3339), retires)
+			}
+			return tw.Flush()
+		}
+	case "prune":
+		run = func(store *auth.KeyStore) error {
+			removed, err := store.Prune(time.Now())
+			if err != nil {
+				return err
+			}
+			for _, kid := range removed {
+				fmt.Printf("removed %s\n", kid)
+			}
+			return nil
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown keys command %q\n\n", args[0])
+		usage()
+		return 2
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *dir == "" {
+		fmt.Fprintln(os.Stderr, "jwt.keysDir is not configured; pass -dir")
+		return 2
+	}
+
+	if err := run(auth.NewKeyStore(*dir)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"app/internal/config"
+	"app/internal/db"
+	"app/internal/rbac"
+	"app/internal/user"
+)
+
+func rolesCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "grant" && args[0] != "revoke") {
+		usage()
+		return 2
+	}
+
+	fs := flag.NewFlagSet("roles "+args[0], flag.ContinueOnError)
+	email := fs.String("email", "", "email of the user")
+	role := fs.String("role", rbac.RoleAdmin, "role name")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *email == "" {
+		fmt.Fprintln(os.Stderr, "-email is required")
+		return 2
+	}
+
+	cfg := config.MustLoad()
+	db := db.NewDbClient(cfg.Database.Url)
+	defer db.Close()
+
+	ctx := context.Background()
+	rbacService := rbac.NewService(rbac.NewPostgresRepo(db))
+	if err := rbacService.EnsureDefaults(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	u, err := user.NewPostgresRepo(db).GetByEmail(ctx, *email)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	verb := "granted"
+	if args[0] == "grant" {
+		err = rbacService.Grant(ctx, u.ID, *role)
+	} else {
+		verb = "revoked"
+		err = rbacService.Revoke(ctx, u.ID, *role)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Printf("%s role %s for %s\n", verb, *role, u.Email)
+	return 0
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"app/internal/config"
+	"app/internal/db"
+	"app/internal/serviceclient"
+)
+
+func serviceClientsCommand(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	fs := flag.NewFlagSet("service-clients "+args[0], flag.ContinueOnError)
+
+	var run func(ctx context.Context, clients *serviceclient.Service) error
+	switch args[0] {
+	case "create":
+		name := fs.String("name", "", "name of the calling service")
+		scopes := fs.String("scopes", "", "space-separated scopes the client may request, e.g. \"orders:read orders:write\"")
+		run = func(ctx context.Context, clients *serviceclient.Service) error {
+			client, secret, err := clients.Register(ctx, *name, strings.Fields(*scopes))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("client_id:     %s\n", client.ClientID)
+			fmt.Printf("client_secret: %s\n", secret)
+			fmt.Println("the secret is not stored and cannot be shown again")
+			return nil
+		}
+	case "list":
+		run = func(ctx context.Context, clients *serviceclient.Service) error {
+			list, err := clients.List(ctx)
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "CLIENT ID\tNAME\tSCOPES\tCREATED\tLAST USED")
+			for _, c := range list {
+				lastUsed := "-"
+				if c.LastUsedAt != nil {
+					lastUsed = c.LastUsedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.ClientID, c.Name, strings.Join(c.Scopes, " "), c.CreatedAt.Format(time.RFC3339), lastUsed)
+			}
+			return tw.Flush()
+		}
+	case "rotate-secret":
+		clientID := fs.String("client-id", "", "client whose secret to replace")
+		run = func(ctx context.Context, clients *serviceclient.Service) error {
+			if *clientID == "" {
+				return fmt.Errorf("-client-id is required")
+			}
+			secret, err := clients.RotateSecret(ctx, *clientID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("client_secret: %s\n", secret)
+			fmt.Println("the previous secret no longer works")
+			return nil
+		}
+	case "delete":
+		clientID := fs.String("client-id", "", "client to delete")
+		run = func(ctx context.Context, clients *serviceclient.Service) error {
+			if *clientID == "" {
+				return fmt.Errorf("-client-id is required")
+			}
+			if err := clients.Delete(ctx, *clientID); err != nil {
+				return err
+			}
+			fmt.Printf("deleted service client %s\n", *clientID)
+			return nil
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown service-clients command %q\n\n", args[0])
+		usage()
+		return 2
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg := config.MustLoad()
+	db := db.NewDbClient(cfg.Database.Url)
+	defer db.Close()
+
+	if err := run(context.Background(), serviceclient.NewService(serviceclient.NewPostgresRepo(db), nil)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"app/internal/config"
+	"app/internal/db"
+	"app/internal/refreshtoken"
+)
+
+func tokensCommand(args []string) int {
+	if len(args) == 0 || args[0] != "purge" {
+		usage()
+		return 2
+	}
+
+	cfg := config.MustLoad()
+
+	fs := flag.NewFlagSet("tokens purge", flag.ContinueOnError)
+	retention := fs.Duration("retention", cfg.Janitor.Retention, "keep tokens expired or revoked for less than this")
+	batchSize := fs.Int("batch-size", cfg.Janitor.BatchSize, "rows deleted per statement")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	db := db.NewDbClient(cfg.Database.Url)
+	defer db.Close()
+
+	janitor := refreshtoken.NewJanitor(refreshtoken.NewPostgresRepo(db), *retention, *batchSize)
+	n, err := janitor.Purge(context.Background())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Printf("purged %d refresh tokens\n", n)
+	return 0
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"net/http"
+
+	"app/internal/response"
+)
+
+type Handler struct {
+	JWT *JWT
+}
+
+func NewHandler(jwt *JWT) *Handler {
+	return &Handler{JWT: jwt}
+}
+
+// JWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys used to verify access tokens issued by this service
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} auth.JWKS "Public signing keys"
+// @Router       /.well-known/jwks.json [get]
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, h.JWT.JWKS())
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto"
+	_ "crypto/sha512" // SHA-384 and SHA-512 for at_hash
+	"encoding/base64"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IDTokenClaims are the claims of an OpenID Connect ID token. Subject must be
+// set by the caller; the issuer, audience and lifetime are filled in by
+// GenerateIDToken.
+type IDTokenClaims struct {
+	Nonce             string `json:"nonce,omitempty"`
+	AuthorizedParty   string `json:"azp,omitempty"`
+	AccessTokenHash   string `json:"at_hash,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// GenerateIDToken issues an ID token for the OAuth client clientID, valid for
+// ttl. If accessToken is set, its hash is included as at_hash. ID tokens are
+// addressed to the client, not to the API audience, so Parse rejects them.
+func (j *JWT) GenerateIDToken(claims IDTokenClaims, clientID string, accessToken string, ttl time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	key := j.keys.Current()
+	now := time.Now()
+	claims.Issuer = j.cfg.Issuer
+	claims.Audience = jwt.ClaimStrings{clientID}
+	claims.AuthorizedParty = clientID
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ID = jti
+	if accessToken != "" {
+		claims.AccessTokenHash = tokenHash(key.Method, accessToken)
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signKey)
+}
+
+// tokenHash returns the left half of the hash of token, using the hash of
+// the signing algorithm, as OpenID Connect defines at_hash.
+func tokenHash(method jwt.SigningMethod, token string) string {
+	h := crypto.SHA256
+	switch method.Alg() {
+	case "ES384", "RS384", "HS384":
+		h = crypto.SHA384
+	case "ES512", "RS512", "HS512", "EdDSA":
+		h = crypto.SHA512
+	}
+
+	d := h.New()
+	d.Write([]byte(token))
+	sum := d.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
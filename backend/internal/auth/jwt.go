@@ -1,7 +1,14 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -9,53 +16,305 @@ import (
 
 var ErrInvalidToken = errors.New("invalid token")
 
+// Config controls the registered claims written to and required of
+// access tokens.
+type Config struct {
+	TTL time.Duration
+	// Issuer is written to iss and, when set, required on parse.
+	Issuer string
+	// Audience is written to aud and, when set, Parse requires a token to
+	// name at least one of these audiences.
+	Audience []string
+	// Leeway tolerates clock skew between services when checking exp, nbf
+	// and iat.
+	Leeway time.Duration
+}
+
 type JWT struct {
-	secret []byte
-	ttl    time.Duration
+	keys *KeyRing
+	cfg  Config
 }
 
-func New(secret string, ttl time.Duration) *JWT {
+func New(keys *KeyRing, cfg Config) *JWT {
 	return &JWT{
-		secret: []byte(secret),
-		ttl:    ttl,
+		keys: keys,
+		cfg:  cfg,
 	}
 }
 
+// WildcardPermission grants every permission.
+const WildcardPermission = "*"
+
+// SubjectType tells tokens of users from tokens of service clients.
+type SubjectType string
+
+const (
+	SubjectUser   SubjectType = "user"
+	SubjectClient SubjectType = "client"
+	// SubjectDelegated is the caller behind a user token issued to an OAuth
+	// client, and SubjectAPIKey the caller behind an API key. They are never
+	// written to a token; see Claims.Caller.
+	SubjectDelegated SubjectType = "delegated"
+	SubjectAPIKey    SubjectType = "api_key"
+)
+
+// Identity describes the user or service client an access token is issued
+// for. Service client identities set SubjectType to SubjectClient and
+// ClientID instead of UserID, and carry no roles.
+type Identity struct {
+	SubjectType SubjectType
+	UserID      int
+	Roles       []string
+	Permissions []string
+	// SessionID is the refresh token family the access token belongs to.
+	SessionID string
+	// ClientID and Scope are set for tokens issued to an OAuth client and
+	// limit what the client may do on behalf of the user, or name the
+	// service client and the scopes it was granted.
+	ClientID string
+	Scope    string
+}
+
 type Claims struct {
-	UserID int `json:"user_id"`
+	SubjectType SubjectType `json:"sub_type,omitempty"`
+	UserID      int         `json:"user_id"`
+	Roles       []string    `json:"roles,omitempty"`
+	Permissions []string    `json:"permissions,omitempty"`
+	SessionID   string      `json:"sid,omitempty"`
+	ClientID    string      `json:"client_id,omitempty"`
+	Scope       string      `json:"scope,omitempty"`
+	// APIKeyID is set when the request was authenticated with an API key
+	// instead of an access token. It is never part of a token.
+	APIKeyID int `json:"-"`
 	jwt.RegisteredClaims
 }
 
-func (j *JWT) Generate(userID int) (string, error) {
+// Type returns the kind of subject the token was issued for. Tokens issued
+// before service clients were introduced are user tokens.
+func (c *Claims) Type() SubjectType {
+	if c.SubjectType == "" {
+		return SubjectUser
+	}
+	return c.SubjectType
+}
+
+// Caller returns who is making a request with the token: the user, a service
+// client, an OAuth client acting for the user or a script with the user's
+// API key. Middleware matches it against the subject types a route accepts,
+// so that routes written for a user's own logins never see a token handed to
+// a third party.
+func (c *Claims) Caller() SubjectType {
+	switch {
+	case c.IsClient():
+		return SubjectClient
+	case c.IsAPIKey():
+		return SubjectAPIKey
+	case c.ClientID != "":
+		return SubjectDelegated
+	default:
+		return SubjectUser
+	}
+}
+
+// IsClient reports whether the token was issued to a service client.
+func (c *Claims) IsClient() bool {
+	return c.Type() == SubjectClient
+}
+
+// IsAPIKey reports whether the request was authenticated with an API key.
+func (c *Claims) IsAPIKey() bool {
+	return c.APIKeyID != 0
+}
+
+// HasScope reports whether the token was granted scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(c.Scope), scope)
+}
+
+// HasRole reports whether the token carries the given role.
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+// HasPermission reports whether the token carries the given permission,
+// either directly or through the wildcard permission.
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission) ||
+		slices.Contains(c.Permissions, WildcardPermission)
+}
+
+// Generate issues an access token for id. Roles and permissions are embedded
+// as claims, so changes take effect when the token is next refreshed.
+func (j *JWT) Generate(id Identity) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	subjectType, subject := SubjectUser, strconv.Itoa(id.UserID)
+	if id.SubjectType == SubjectClient {
+		if id.ClientID == "" || id.UserID != 0 {
+			return "", errors.New("client token needs a client id and no user id")
+		}
+		subjectType, subject = SubjectClient, id.ClientID
+	}
+
+	now := time.Now()
 	claims := Claims{
-		UserID: userID,
+		SubjectType: subjectType,
+		UserID:      id.UserID,
+		Roles:       id.Roles,
+		Permissions: id.Permissions,
+		SessionID:   id.SessionID,
+		ClientID:    id.ClientID,
+		Scope:       id.Scope,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.ttl)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    j.cfg.Issuer,
+			Subject:   subject,
+			Audience:  j.cfg.Audience,
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.cfg.TTL)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	key := j.keys.Current()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signKey)
 }
 
 func (j *JWT) Parse(tokenStr string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenStr,
 		&Claims{},
-		func(t *jwt.Token) (interface{}, error) {
-			return j.secret, nil
-		},
+		j.keyFunc,
+		j.parserOptions()...,
 	)
 
 	if err != nil || !token.Valid {
 		return nil, ErrInvalidToken
 	}
 
+	if typ, _ := token.Header["typ"].(string); typ == actionTokenType {
+		return nil, ErrInvalidToken
+	}
+
 	claims, ok := token.Claims.(*Claims)
 	if !ok {
 		return nil, ErrInvalidToken
 	}
 
+	switch claims.Type() {
+	case SubjectUser:
+		// Tokens issued before sub was introduced only carry user_id.
+		if claims.Subject != "" && claims.Subject != strconv.Itoa(claims.UserID) {
+			return nil, ErrInvalidToken
+		}
+	case SubjectClient:
+		if claims.ClientID == "" || claims.Subject != claims.ClientID || claims.UserID != 0 {
+			return nil, ErrInvalidToken
+		}
+	default:
+		return nil, ErrInvalidToken
+	}
+
 	return claims, nil
 }
+
+// Verify parses an access token, so that the JWT can be used as the
+// credential verifier of middleware.Auth.
+func (j *JWT) Verify(_ context.Context, token string) (*Claims, error) {
+	return j.Parse(token)
+}
+
+// Keys returns the key ring used for signing and verification.
+func (j *JWT) Keys() *KeyRing {
+	return j.keys
+}
+
+// TTL returns the lifetime of access tokens.
+func (j *JWT) TTL() time.Duration {
+	return j.cfg.TTL
+}
+
+// Issuer returns the configured iss value.
+func (j *JWT) Issuer() string {
+	return j.cfg.Issuer
+}
+
+// JWKS returns the public verification keys. HMAC keys are never published.
+func (j *JWT) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range j.keys.Keys() {
+		if key.IsSymmetric() {
+			continue
+		}
+		if jwk, err := key.JWK(); err == nil {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+func (j *JWT) parserOptions() []jwt.ParserOption {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(j.validMethods()),
+		jwt.WithLeeway(j.cfg.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if j.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(j.cfg.Issuer))
+	}
+	if len(j.cfg.Audience) > 0 {
+		opts = append(opts, jwt.WithAudience(j.cfg.Audience...))
+	}
+	return opts
+}
+
+// validMethods pins accepted algorithms to those of the keys in the ring so
+// a token can never select its own verification algorithm.
+func (j *JWT) validMethods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range j.keys.Keys() {
+		alg := key.Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// keyFunc selects the verification key by the kid header and rejects tokens
+// whose algorithm does not match that key.
+func (j *JWT) keyFunc(t *jwt.Token) (interface{}, error) {
+	var key *Key
+	if kid, ok := t.Header["kid"].(string); ok {
+		k, found := j.keys.Lookup(kid)
+		if !found {
+			return nil, ErrKeyNotFound
+		}
+		key = k
+	} else {
+		// Tokens issued before kid headers were introduced carry none.
+		key = j.keys.Current()
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, ErrInvalidToken
+	}
+	return key.verifyKey, nil
+}
+
+// newTokenID returns a random jti value.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token id: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
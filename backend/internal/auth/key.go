@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidKey         = errors.New("invalid signing key")
+	ErrUnsupportedKeyType = errors.New("unsupported signing key type")
+)
+
+// minRSABits is the smallest RSA modulus accepted for RS256 signing.
+const minRSABits = 2048
+
+// Key is a single JWT signing key together with its verification half.
+// HMAC keys use the same secret for both; asymmetric keys expose only the
+// public part through Public and the JWKS document.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewHMACKey creates an HS256 key from a shared secret.
+// If id is empty a stable identifier is derived from the secret.
+func NewHMACKey(id string, secret string) (*Key, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("%w: empty hmac secret", ErrInvalidKey)
+	}
+
+	if id == "" {
+		sum := sha256.Sum256([]byte(secret))
+		id = base64.RawURLEncoding.EncodeToString(sum[:12])
+	}
+
+	return &Key{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}, nil
+}
+
+// NewPrivateKey wraps an RSA, ECDSA or Ed25519 private key. The signing
+// algorithm is derived from the key type: RS256 for RSA, ES256/ES384/ES512
+// depending on the curve for ECDSA, and EdDSA for Ed25519.
+// If id is empty the RFC 7638 thumbprint of the public key is used.
+func NewPrivateKey(id string, priv crypto.PrivateKey) (*Key, error) {
+	k := &Key{ID: id, signKey: priv}
+
+	switch p := priv.(type) {
+	case *rsa.PrivateKey:
+		if p.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("%w: rsa key must be at least %d bits", ErrInvalidKey, minRSABits)
+		}
+		k.Method = jwt.SigningMethodRS256
+		k.verifyKey = &p.PublicKey
+	case *ecdsa.PrivateKey:
+		switch p.Curve {
+		case elliptic.P256():
+			k.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			k.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			k.Method = jwt.SigningMethodES512
+		default:
+			return nil, fmt.Errorf("%w: unsupported ecdsa curve", ErrUnsupportedKeyType)
+		}
+		k.verifyKey = &p.PublicKey
+	case ed25519.PrivateKey:
+		k.Method = jwt.SigningMethodEdDSA
+		k.verifyKey = p.Public()
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, priv)
+	}
+
+	if k.ID == "" {
+		jwk, err := k.JWK()
+		if err != nil {
+			return nil, err
+		}
+		thumbprint, err := jwk.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		k.ID = thumbprint
+	}
+
+	return k, nil
+}
+
+// ParsePrivateKeyPEM decodes a PEM encoded PKCS#8, PKCS#1 (RSA) or SEC 1 (EC)
+// private key.
+func ParsePrivateKeyPEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidKey)
+	}
+
+	var (
+		priv crypto.PrivateKey
+		err  error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		priv, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	return NewPrivateKey(id, priv)
+}
+
+// GenerateKey creates a new private key for the given JWS algorithm.
+// Supported algorithms are RS256, ES256, ES384, ES512 and EdDSA.
+func GenerateKey(alg string) (*Key, error) {
+	var (
+		priv crypto.PrivateKey
+		err  error
+	)
+	switch alg {
+	case "RS256":
+		priv, err = rsa.GenerateKey(rand.Reader, minRSABits)
+	case "ES256":
+		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		priv, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "ES512":
+		priv, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case "EdDSA":
+		_, priv, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKeyType, alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", alg, err)
+	}
+	return NewPrivateKey("", priv)
+}
+
+// MarshalPEM encodes an asymmetric private key as PKCS#8 PEM.
+func (k *Key) MarshalPEM() ([]byte, error) {
+	if k.IsSymmetric() {
+		return nil, fmt.Errorf("%w: hmac keys cannot be exported", ErrUnsupportedKeyType)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(k.signKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// LoadPrivateKey reads a PEM encoded private key from path.
+func LoadPrivateKey(id string, path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key %s: %w", path, err)
+	}
+	return ParsePrivateKeyPEM(id, data)
+}
+
+// Public returns the public half of an asymmetric key, or nil for HMAC keys.
+func (k *Key) Public() crypto.PublicKey {
+	if k.IsSymmetric() {
+		return nil
+	}
+	return k.verifyKey
+}
+
+// IsSymmetric reports whether the key is a shared HMAC secret.
+func (k *Key) IsSymmetric() bool {
+	_, ok := k.verifyKey.([]byte)
+	return ok
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// KeyRing holds the current signing key plus any number of verification-only
+// keys selected by kid. Retired keys stay valid for verification until their
+// scheduled removal time so tokens in flight survive a rotation.
+// It is safe for concurrent use.
+type KeyRing struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]*ringEntry
+}
+
+type ringEntry struct {
+	key      *Key
+	retireAt time.Time // zero means no removal is scheduled
+}
+
+// NewKeyRing creates a ring that signs with current.
+func NewKeyRing(current *Key) *KeyRing {
+	return &KeyRing{
+		current: current.ID,
+		keys: map[string]*ringEntry{
+			current.ID: {key: current},
+		},
+	}
+}
+
+// Current returns the key used to sign new tokens.
+func (r *KeyRing) Current() *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[r.current].key
+}
+
+// Lookup returns the verification key with the given kid, unless it has
+// passed its scheduled removal time.
+func (r *KeyRing) Lookup(kid string) (*Key, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.keys[kid]
+	if !ok || e.expired(time.Now()) {
+		return nil, false
+	}
+	return e.key, true
+}
+
+// Keys returns every key that is still valid for verification, ordered by kid.
+func (r *KeyRing) Keys() []*Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]*Key, 0, len(r.keys))
+	for _, e := range r.keys {
+		if !e.expired(now) {
+			keys = append(keys, e.key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys
+}
+
+// Add registers a verification-only key. Adding a key that is already in the
+// ring replaces it and clears any scheduled removal.
+func (r *KeyRing) Add(key *Key) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[key.ID] = &ringEntry{key: key}
+}
+
+// Promote makes the key with the given kid the signing key. The previous
+// signing key remains valid for verification for retireAfter.
+func (r *KeyRing) Promote(kid string, retireAfter time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.keys[kid]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if kid == r.current {
+		return nil
+	}
+
+	r.keys[r.current].retireAt = time.Now().Add(retireAfter)
+	e.retireAt = time.Time{}
+	r.current = kid
+	return nil
+}
+
+// Retire schedules removal of a verification key. The signing key cannot be
+// retired; promote another key first.
+func (r *KeyRing) Retire(kid string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.keys[kid]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	if kid == r.current {
+		return errors.New("cannot retire the current signing key")
+	}
+	e.retireAt = at
+	return nil
+}
+
+// Prune drops keys whose removal time has passed and returns their kids.
+func (r *KeyRing) Prune(now time.Time) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed []string
+	for kid, e := range r.keys {
+		if e.expired(now) {
+			delete(r.keys, kid)
+			removed = append(removed, kid)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// Replace swaps the ring's contents for those of other. It is used to pick up
+// rotations performed by another process without restarting.
+func (r *KeyRing) Replace(other *KeyRing) {
+	other.mu.RLock()
+	current, keys := other.current, other.keys
+	other.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = current
+	r.keys = keys
+}
+
+func (e *ringEntry) expired(now time.Time) bool {
+	return !e.retireAt.IsZero() && now.After(e.retireAt)
+}
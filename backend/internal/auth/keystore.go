@@ -0,0 +1,298 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const manifestFile = "keyring.json"
+
+var ErrNoKeys = errors.New("key store has no signing key")
+
+// KeyStore persists a key ring in a directory as one PKCS#8 PEM file per key
+// plus a keyring.json manifest recording which key signs and when retired
+// keys are removed. Every API instance loads the same directory, so a
+// rotation performed by the CLI is picked up by running servers on their
+// next reload.
+type KeyStore struct {
+	Dir string
+}
+
+// KeyInfo describes a key in the store without exposing key material.
+type KeyInfo struct {
+	ID        string     `json:"kid"`
+	Alg       string     `json:"alg"`
+	File      string     `json:"file"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RetireAt  *time.Time `json:"retireAt,omitempty"`
+	Current   bool       `json:"-"`
+}
+
+type manifest struct {
+	Current string    `json:"current"`
+	Keys    []KeyInfo `json:"keys"`
+}
+
+// NewKeyStore creates a store rooted at dir.
+func NewKeyStore(dir string) *KeyStore {
+	return &KeyStore{Dir: dir}
+}
+
+// Load reads every key listed in the manifest into a new ring.
+// Keys whose removal time has already passed are skipped.
+func (s *KeyStore) Load() (*KeyRing, error) {
+	m, err := s.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	if m.Current == "" {
+		return nil, ErrNoKeys
+	}
+
+	now := time.Now()
+	keys := make(map[string]*Key, len(m.Keys))
+	for _, info := range m.Keys {
+		if info.RetireAt != nil && now.After(*info.RetireAt) {
+			continue
+		}
+		key, err := LoadPrivateKey(info.ID, filepath.Join(s.Dir, info.File))
+		if err != nil {
+			return nil, err
+		}
+		keys[info.ID] = key
+	}
+
+	current, ok := keys[m.Current]
+	if !ok {
+		return nil, fmt.Errorf("%w: current key %s is missing", ErrNoKeys, m.Current)
+	}
+	ring := NewKeyRing(current)
+
+	for _, info := range m.Keys {
+		key, ok := keys[info.ID]
+		if !ok || info.ID == m.Current {
+			continue
+		}
+		ring.Add(key)
+		if info.RetireAt != nil {
+			if err := ring.Retire(info.ID, *info.RetireAt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ring, nil
+}
+
+// Generate creates a new key and adds it to the store. The first key in an
+// empty store becomes the signing key; later keys are published for
+// verification only until promoted with Rotate, giving downstream services
+// time to fetch them from the JWKS endpoint.
+func (s *KeyStore) Generate(alg string) (*Key, error) {
+	m, err := s.readManifest()
+	if err != nil && !errors.Is(err, ErrNoKeys) {
+		return nil, err
+	}
+
+	key, err := GenerateKey(alg)
+	if err != nil {
+		return nil, err
+	}
+	data, err := key.MarshalPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	file := key.ID + ".pem"
+	if err := os.WriteFile(filepath.Join(s.Dir, file), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write key file: %w", err)
+	}
+
+	m.Keys = append(m.Keys, KeyInfo{
+		ID:        key.ID,
+		Alg:       key.Method.Alg(),
+		File:      file,
+		CreatedAt: time.Now().UTC(),
+	})
+	if m.Current == "" {
+		m.Current = key.ID
+	}
+
+	if err := s.writeManifest(m); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Rotate promotes the key with the given kid to signing key and schedules
+// removal of the previous signing key after retireAfter. An empty kid selects
+// the newest key that is neither current nor retired.
+// It returns the kid of the new signing key.
+func (s *KeyStore) Rotate(kid string, retireAfter time.Duration) (string, error) {
+	m, err := s.readManifest()
+	if err != nil {
+		return "", err
+	}
+
+	if kid == "" {
+		var newest *KeyInfo
+		for i := range m.Keys {
+			info := &m.Keys[i]
+			if info.ID == m.Current || info.RetireAt != nil {
+				continue
+			}
+			if newest == nil || info.CreatedAt.After(newest.CreatedAt) {
+				newest = info
+			}
+		}
+		if newest == nil {
+			return "", fmt.Errorf("%w: no staged key to promote, run keys generate first", ErrKeyNotFound)
+		}
+		kid = newest.ID
+	}
+
+	if kid == m.Current {
+		return kid, nil
+	}
+
+	retireAt := time.Now().Add(retireAfter).UTC()
+	found := false
+	for i := range m.Keys {
+		switch m.Keys[i].ID {
+		case kid:
+			m.Keys[i].RetireAt = nil
+			found = true
+		case m.Current:
+			m.Keys[i].RetireAt = &retireAt
+		}
+	}
+	if !found {
+		return "", ErrKeyNotFound
+	}
+
+	m.Current = kid
+	if err := s.writeManifest(m); err != nil {
+		return "", err
+	}
+	return kid, nil
+}
+
+// Prune deletes keys whose removal time has passed and returns their kids.
+func (s *KeyStore) Prune(now time.Time) ([]string, error) {
+	m, err := s.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		kept    []KeyInfo
+		removed []string
+	)
+	for _, info := range m.Keys {
+		if info.RetireAt != nil && now.After(*info.RetireAt) {
+			if err := os.Remove(filepath.Join(s.Dir, info.File)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+			removed = append(removed, info.ID)
+			continue
+		}
+		kept = append(kept, info)
+	}
+
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	m.Keys = kept
+	if err := s.writeManifest(m); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// List returns the keys in the store ordered by creation time.
+func (s *KeyStore) List() ([]KeyInfo, error) {
+	m, err := s.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]KeyInfo, len(m.Keys))
+	for i, info := range m.Keys {
+		info.Current = info.ID == m.Current
+		keys[i] = info
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// Watch reloads the store into ring every interval until ctx is done.
+// Load failures are logged and the previous ring contents kept.
+func (s *KeyStore) Watch(ctx context.Context, ring *KeyRing, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			loaded, err := s.Load()
+			if err != nil {
+				log.Error("Failed to reload signing keys ", err.Error())
+				continue
+			}
+			ring.Replace(loaded)
+		}
+	}
+}
+
+func (s *KeyStore) readManifest() (*manifest, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir, manifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &manifest{}, ErrNoKeys
+		}
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid key manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// writeManifest replaces the manifest atomically so a concurrent Load never
+// observes a partially written file.
+func (s *KeyStore) writeManifest(m *manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, manifestFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.Dir, manifestFile))
+}
@@ -9,14 +9,25 @@ import (
 )
 
 type Config struct {
-	Env      string `yaml:"env"`
-	Http     `yaml:"http"`
-	Database `yaml:"database"`
-	Jwt      `yaml:"jwt"`
+	Env        string `yaml:"env"`
+	Http       `yaml:"http"`
+	Database   `yaml:"database"`
+	Jwt        `yaml:"jwt"`
+	Janitor    `yaml:"janitor"`
+	Auth       `yaml:"auth"`
+	Mail       `yaml:"mail"`
+	Password   `yaml:"password"`
+	Lockout    `yaml:"lockout"`
+	WebAuthn   `yaml:"webauthn"`
+	MagicLink  `yaml:"magicLink"`
+	OIDC       `yaml:"oidc"`
+	Federation `yaml:"federation"`
+	APIKeys    `yaml:"apiKeys"`
 }
 
 type Http struct {
-	Port string `yaml:"port"`
+	Port           string   `yaml:"port"`
+	TrustedProxies []string `yaml:"trustedProxies"`
 }
 
 type Database struct {
@@ -25,8 +36,115 @@ type Database struct {
 
 type Jwt struct {
 	Secret          string        `yaml:"secret"`
+	PrivateKeyPath  string        `yaml:"privateKeyPath"`
+	KeyID           string        `yaml:"keyId"`
+	KeysDir         string        `yaml:"keysDir"`
+	KeysReload      time.Duration `yaml:"keysReloadInterval" env-default:"1m"`
+	Issuer          string        `yaml:"issuer"`
+	Audience        []string      `yaml:"audience"`
+	Leeway          time.Duration `yaml:"leeway" env-default:"30s"`
 	AccessTtlHours  time.Duration `yaml:"accessTtlHours"`
 	RefreshTtlHours time.Duration `yaml:"refreshTtlHours"`
+	RevokeOnReuse   bool          `yaml:"revokeAllOnReuse"`
+	RotationGrace   time.Duration `yaml:"rotationGracePeriod" env-default:"10s"`
+	SessionMaxAge   time.Duration `yaml:"sessionMaxAge"`
+	SessionIdle     time.Duration `yaml:"sessionIdleTimeout"`
+}
+
+type Janitor struct {
+	Interval  time.Duration `yaml:"interval" env-default:"1h"`
+	Retention time.Duration `yaml:"retention" env-default:"168h"`
+	BatchSize int           `yaml:"batchSize" env-default:"1000"`
+}
+
+type Auth struct {
+	RequireVerifiedEmail bool          `yaml:"requireVerifiedEmail"`
+	VerifyEmailUrl       string        `yaml:"verifyEmailUrl" env-default:"http://localhost:3000/verify-email"`
+	VerifyEmailTtl       time.Duration `yaml:"verifyEmailTtl" env-default:"24h"`
+	PasswordResetUrl     string        `yaml:"passwordResetUrl" env-default:"http://localhost:3000/reset-password"`
+	PasswordResetTtl     time.Duration `yaml:"passwordResetTtl" env-default:"1h"`
+	MfaIssuer            string        `yaml:"mfaIssuer" env-default:"Go-Shablon"`
+	MfaChallengeTtl      time.Duration `yaml:"mfaChallengeTtl" env-default:"5m"`
+}
+
+type Mail struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" env-default:"587"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from" env-default:"no-reply@localhost"`
+}
+
+type Password struct {
+	Algorithm         string `yaml:"algorithm" env-default:"argon2id"`
+	BcryptCost        int    `yaml:"bcryptCost" env-default:"12"`
+	Argon2Memory      uint32 `yaml:"argon2Memory" env-default:"65536"`
+	Argon2Iterations  uint32 `yaml:"argon2Iterations" env-default:"3"`
+	Argon2Parallelism uint8  `yaml:"argon2Parallelism" env-default:"2"`
+	MinLength         int    `yaml:"minLength" env-default:"8"`
+	MaxLength         int    `yaml:"maxLength" env-default:"128"`
+	RequireUpper      bool   `yaml:"requireUppercase"`
+	RequireLower      bool   `yaml:"requireLowercase"`
+	RequireDigit      bool   `yaml:"requireDigit"`
+	RequireSymbol     bool   `yaml:"requireSymbol"`
+	BreachedFile      string `yaml:"breachedPasswordsFile"`
+}
+
+type Lockout struct {
+	FreeAttempts   int           `yaml:"freeAttempts" env-default:"3"`
+	MaxFailures    int           `yaml:"maxFailures" env-default:"10"`
+	IPFreeAttempts int           `yaml:"ipFreeAttempts" env-default:"20"`
+	IPMaxFailures  int           `yaml:"ipMaxFailures" env-default:"100"`
+	BaseDelay      time.Duration `yaml:"baseDelay" env-default:"1s"`
+	MaxDelay       time.Duration `yaml:"maxDelay" env-default:"1m"`
+	Duration       time.Duration `yaml:"duration" env-default:"15m"`
+	Window         time.Duration `yaml:"window" env-default:"1h"`
+	UnlockUrl      string        `yaml:"unlockUrl" env-default:"http://localhost:3000/unlock"`
+	UnlockTtl      time.Duration `yaml:"unlockTtl" env-default:"1h"`
+}
+
+type WebAuthn struct {
+	RPID          string        `yaml:"rpId" env-default:"localhost"`
+	RPDisplayName string        `yaml:"rpName" env-default:"Go-Shablon"`
+	RPOrigins     []string      `yaml:"rpOrigins" env-default:"http://localhost:3000"`
+	Timeout       time.Duration `yaml:"timeout" env-default:"5m"`
+}
+
+type MagicLink struct {
+	Url               string        `yaml:"url" env-default:"http://localhost:3000/magic-link"`
+	Ttl               time.Duration `yaml:"ttl" env-default:"15m"`
+	MaxAttempts       int           `yaml:"maxAttempts" env-default:"5"`
+	MaxPerWindow      int           `yaml:"maxPerWindow" env-default:"3"`
+	Window            time.Duration `yaml:"window" env-default:"1h"`
+	AllowRegistration bool          `yaml:"allowRegistration"`
+}
+
+type OIDC struct {
+	LoginUrl   string        `yaml:"loginUrl" env-default:"http://localhost:3000/authorize"`
+	CodeTtl    time.Duration `yaml:"codeTtl" env-default:"1m"`
+	IDTokenTtl time.Duration `yaml:"idTokenTtl" env-default:"1h"`
+}
+
+type Federation struct {
+	CallbackUrl        string               `yaml:"callbackUrl" env-default:"http://localhost:3000/auth/callback"`
+	StateTtl           time.Duration        `yaml:"stateTtl" env-default:"10m"`
+	AllowRegistration  bool                 `yaml:"allowRegistration" env-default:"true"`
+	LinkVerifiedEmails bool                 `yaml:"linkVerifiedEmails" env-default:"true"`
+	Providers          []FederationProvider `yaml:"providers"`
+}
+
+type FederationProvider struct {
+	Name         string   `yaml:"name"`
+	DisplayName  string   `yaml:"displayName"`
+	Issuer       string   `yaml:"issuer"`
+	ClientId     string   `yaml:"clientId"`
+	ClientSecret string   `yaml:"clientSecret"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+type APIKeys struct {
+	MaxPerUser int           `yaml:"maxPerUser" env-default:"20"`
+	MaxTtl     time.Duration `yaml:"maxTtl"`
 }
 
 func MustLoad() *Config {
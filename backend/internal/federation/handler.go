@@ -0,0 +1,262 @@
+package federation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/domain"
+	"app/ent"
+	"app/internal/middleware"
+	"app/internal/response"
+	"app/internal/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// LoginCompleter finishes a login the way password login does, asking for
+// the second factor if the user has one.
+type LoginCompleter interface {
+	CompleteLogin(w http.ResponseWriter, r *http.Request, u *ent.User)
+}
+
+type Handler struct {
+	Service *Service
+	Logins  LoginCompleter
+}
+
+func NewHandler(s *Service, logins LoginCompleter) *Handler {
+	return &Handler{Service: s, Logins: logins}
+}
+
+// Providers godoc
+// @Summary      List identity providers
+// @Description  List the external identity providers users can sign in with
+// @Tags         auth
+// @Produce      json
+// @Success      200 {array} domain.FederationProviderResponse "Identity providers"
+// @Router       /auth/federation/providers [get]
+func (h *Handler) Providers(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, ToProviderResponses(h.Service.Providers()))
+}
+
+// Begin godoc
+// @Summary      Begin sign-in with identity provider
+// @Description  Start signing in at an external identity provider. Send the user to authorization_url; the provider redirects back to the configured callback page with state and code, which the page sends to /auth/federation/callback after checking that state is the one returned here.
+// @Tags         auth
+// @Produce      json
+// @Param        provider path string true "Provider name"
+// @Success      200 {object} domain.FederationBeginResponse "Authorization URL"
+// @Failure      404 {object} domain.ErrorResponse "Unknown identity provider"
+// @Failure      502 {object} domain.ErrorResponse "Identity provider unavailable"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/federation/{provider}/begin [post]
+func (h *Handler) Begin(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.Service.Begin(r.Context(), chi.URLParam(r, "provider"), nil)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, domain.FederationBeginResponse{AuthorizationURL: authURL, State: state})
+}
+
+// Callback godoc
+// @Summary      Finish sign-in with identity provider
+// @Description  Log in with the state and code the identity provider redirected back with. The first sign-in with an identity links it to the account with the same email address if both the provider and the account verified it, or creates an account if registration is enabled; an existing account that cannot be linked automatically is reported with 409, and its owner can log in and link the identity at /users/me/identities. With two-factor authentication enabled the response is a domain.MFAChallengeResponse instead, to be completed at /auth/mfa/verify.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.FederationCallbackDTO true "State and code"
+// @Success      200 {object} domain.AuthResponse "Successfully logged in"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body or expired state"
+// @Failure      401 {object} domain.ErrorResponse "Identity provider rejected the code or returned an invalid ID token"
+// @Failure      403 {object} domain.ErrorResponse "Email not verified or registration disabled"
+// @Failure      409 {object} domain.ErrorResponse "Account with this email exists"
+// @Failure      502 {object} domain.ErrorResponse "Identity provider unavailable"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/federation/callback [post]
+func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
+	var dto domain.FederationCallbackDTO
+	if !decodeCallback(w, r, &dto) {
+		return
+	}
+
+	u, err := h.Service.Callback(r.Context(), dto.State, dto.Code)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	h.Logins.CompleteLogin(w, r, u)
+}
+
+// List godoc
+// @Summary      List linked identities
+// @Description  List the external identities linked to the authenticated user, oldest first
+// @Tags         identities
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.IdentityResponse "Linked identities"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/identities [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	identities, err := h.Service.Identities(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToIdentityResponses(identities))
+}
+
+// BeginLink godoc
+// @Summary      Begin linking identity
+// @Description  Start linking an identity at an external identity provider to the account. Send the user to authorization_url and the state and code it redirects back with to /users/me/identities/callback.
+// @Tags         identities
+// @Produce      json
+// @Security     BearerAuth
+// @Param        provider path string true "Provider name"
+// @Success      200 {object} domain.FederationBeginResponse "Authorization URL"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "Unknown identity provider"
+// @Failure      502 {object} domain.ErrorResponse "Identity provider unavailable"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/identities/{provider}/begin [post]
+func (h *Handler) BeginLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	authURL, state, err := h.Service.Begin(r.Context(), chi.URLParam(r, "provider"), &userID)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, domain.FederationBeginResponse{AuthorizationURL: authURL, State: state})
+}
+
+// FinishLink godoc
+// @Summary      Finish linking identity
+// @Description  Link the identity the provider authenticated to the account, whatever its email address. The identity can then be used to log in.
+// @Tags         identities
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.FederationCallbackDTO true "State and code"
+// @Success      201 {object} domain.IdentityResponse "Identity linked"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body or expired state"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized, or the identity provider rejected the code"
+// @Failure      409 {object} domain.ErrorResponse "Identity linked to another account"
+// @Failure      502 {object} domain.ErrorResponse "Identity provider unavailable"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/identities/callback [post]
+func (h *Handler) FinishLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dto domain.FederationCallbackDTO
+	if !decodeCallback(w, r, &dto) {
+		return
+	}
+
+	i, err := h.Service.Link(r.Context(), userID, dto.State, dto.Code)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, ToIdentityResponse(i))
+}
+
+// Unlink godoc
+// @Summary      Unlink identity
+// @Description  Remove an external identity from the account. It can no longer be used to log in.
+// @Tags         identities
+// @Security     BearerAuth
+// @Param        id path int true "Identity ID"
+// @Success      204 "Identity unlinked"
+// @Failure      400 {object} domain.ErrorResponse "Invalid identity id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "Identity not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/identities/{id} [delete]
+func (h *Handler) Unlink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid identity id")
+		return
+	}
+
+	if err := h.Service.Unlink(r.Context(), userID, id); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeCallback(w http.ResponseWriter, r *http.Request, dto *domain.FederationCallbackDTO) bool {
+	if err := json.NewDecoder(r.Body).Decode(dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+
+	if dto.State == "" || dto.Code == "" {
+		response.Error(w, http.StatusBadRequest, "state and code are required")
+		return false
+	}
+	return true
+}
+
+// respondWithError maps service errors to responses.
+func respondWithError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownProvider):
+		response.Error(w, http.StatusNotFound, "unknown identity provider")
+	case errors.Is(err, ErrInvalidState):
+		response.Error(w, http.StatusBadRequest, "invalid or expired sign-in state")
+	case errors.Is(err, ErrAuthenticationFailed):
+		response.Error(w, http.StatusUnauthorized, "identity provider did not authenticate the user")
+	case errors.Is(err, ErrProviderUnavailable):
+		response.Error(w, http.StatusBadGateway, "identity provider is unavailable")
+	case errors.Is(err, ErrNoEmail):
+		response.Error(w, http.StatusForbidden, "identity provider did not return an email address")
+	case errors.Is(err, ErrUnverifiedEmail):
+		response.Error(w, http.StatusForbidden, "identity provider did not verify the email address")
+	case errors.Is(err, ErrRegistrationDisabled):
+		response.Error(w, http.StatusForbidden, "registration with an identity provider is disabled")
+	case errors.Is(err, ErrAccountExists):
+		response.Error(w, http.StatusConflict, "an account with this email already exists, log in and link the identity")
+	case errors.Is(err, ErrIdentityLinked):
+		response.Error(w, http.StatusConflict, "identity is linked to another account")
+	case errors.Is(err, ErrIdentityNotFound):
+		response.Error(w, http.StatusNotFound, "identity not found")
+	case errors.Is(err, user.ErrEmailNotVerified):
+		response.Error(w, http.StatusForbidden, "email is not verified")
+	case errors.Is(err, user.ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "user not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,37 @@
+package federation
+
+import (
+	"app/domain"
+	"app/ent"
+)
+
+func ToProviderResponses(providers []ProviderConfig) []domain.FederationProviderResponse {
+	resp := make([]domain.FederationProviderResponse, len(providers))
+	for i, p := range providers {
+		name := p.DisplayName
+		if name == "" {
+			name = p.Name
+		}
+		resp[i] = domain.FederationProviderResponse{Name: p.Name, DisplayName: name}
+	}
+	return resp
+}
+
+func ToIdentityResponse(i *ent.UserIdentity) domain.IdentityResponse {
+	return domain.IdentityResponse{
+		ID:          i.ID,
+		Provider:    i.Provider,
+		Subject:     i.Subject,
+		Email:       i.Email,
+		CreatedAt:   i.CreatedAt,
+		LastLoginAt: i.LastLoginAt,
+	}
+}
+
+func ToIdentityResponses(identities []*ent.UserIdentity) []domain.IdentityResponse {
+	resp := make([]domain.IdentityResponse, len(identities))
+	for i, identity := range identities {
+		resp[i] = ToIdentityResponse(identity)
+	}
+	return resp
+}
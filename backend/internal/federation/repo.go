@@ -0,0 +1,156 @@
+package federation
+
+import (
+	"app/ent"
+	"app/ent/federationstate"
+	"app/ent/useridentity"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrStateNotFound    = errors.New("federation state not found")
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrIdentityExists   = errors.New("identity is already linked")
+)
+
+// Repository defines the interface for sign-in state and linked identity
+// data access.
+type Repository interface {
+	CreateState(ctx context.Context, provider string, stateHash string, nonce string, codeVerifier string, userID *int, expiresAt time.Time) error
+	TakeState(ctx context.Context, stateHash string) (*ent.FederationState, error)
+	DeleteExpiredStates(ctx context.Context, before time.Time) error
+	GetIdentity(ctx context.Context, provider string, subject string) (*ent.UserIdentity, error)
+	CreateIdentity(ctx context.Context, userID int, provider string, subject string, email string) (*ent.UserIdentity, error)
+	RecordLogin(ctx context.Context, id int, email string) error
+	ListIdentities(ctx context.Context, userID int) ([]*ent.UserIdentity, error)
+	DeleteIdentity(ctx context.Context, userID int, id int) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// CreateState stores a sign-in started at provider under the hash of its
+// state parameter.
+func (r *PostgresRepo) CreateState(ctx context.Context, provider string, stateHash string, nonce string, codeVerifier string, userID *int, expiresAt time.Time) error {
+	return r.Db.Client.FederationState.Create().
+		SetProvider(provider).
+		SetStateHash(stateHash).
+		SetNonce(nonce).
+		SetCodeVerifier(codeVerifier).
+		SetNillableUserID(userID).
+		SetExpiresAt(expiresAt).
+		Exec(ctx)
+}
+
+// TakeState returns the unexpired sign-in with the given state hash and
+// deletes it. Only the caller whose delete succeeds gets the state, so a
+// callback is accepted once.
+func (r *PostgresRepo) TakeState(ctx context.Context, stateHash string) (*ent.FederationState, error) {
+	s, err := r.Db.Client.FederationState.Query().
+		Where(
+			federationstate.StateHashEQ(stateHash),
+			federationstate.ExpiresAtGT(time.Now()),
+		).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrStateNotFound
+		}
+		return nil, err
+	}
+
+	n, err := r.Db.Client.FederationState.Delete().
+		Where(federationstate.IDEQ(s.ID)).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrStateNotFound
+	}
+	return s, nil
+}
+
+// DeleteExpiredStates removes sign-ins that were never completed.
+func (r *PostgresRepo) DeleteExpiredStates(ctx context.Context, before time.Time) error {
+	_, err := r.Db.Client.FederationState.Delete().
+		Where(federationstate.ExpiresAtLT(before)).
+		Exec(ctx)
+	return err
+}
+
+// GetIdentity returns the identity with the given subject at provider.
+func (r *PostgresRepo) GetIdentity(ctx context.Context, provider string, subject string) (*ent.UserIdentity, error) {
+	i, err := r.Db.Client.UserIdentity.Query().
+		Where(
+			useridentity.ProviderEQ(provider),
+			useridentity.SubjectEQ(subject),
+		).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return i, nil
+}
+
+// CreateIdentity links the subject at provider to a user.
+func (r *PostgresRepo) CreateIdentity(ctx context.Context, userID int, provider string, subject string, email string) (*ent.UserIdentity, error) {
+	i, err := r.Db.Client.UserIdentity.Create().
+		SetUserID(userID).
+		SetProvider(provider).
+		SetSubject(subject).
+		SetEmail(email).
+		SetLastLoginAt(time.Now()).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrIdentityExists
+	}
+	return i, err
+}
+
+// RecordLogin stores the time of a login with an identity and the email the
+// provider reported with it.
+func (r *PostgresRepo) RecordLogin(ctx context.Context, id int, email string) error {
+	return r.Db.Client.UserIdentity.UpdateOneID(id).
+		SetEmail(email).
+		SetLastLoginAt(time.Now()).
+		Exec(ctx)
+}
+
+// ListIdentities returns the identities linked to a user, oldest first.
+func (r *PostgresRepo) ListIdentities(ctx context.Context, userID int) ([]*ent.UserIdentity, error) {
+	return r.Db.Client.UserIdentity.Query().
+		Where(useridentity.UserIDEQ(userID)).
+		Order(ent.Asc(useridentity.FieldCreatedAt)).
+		All(ctx)
+}
+
+// DeleteIdentity unlinks an identity from a user.
+func (r *PostgresRepo) DeleteIdentity(ctx context.Context, userID int, id int) error {
+	n, err := r.Db.Client.UserIdentity.Delete().
+		Where(
+			useridentity.IDEQ(id),
+			useridentity.UserIDEQ(userID),
+		).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
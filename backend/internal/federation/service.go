@@ -0,0 +1,494 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"app/ent"
+	"app/internal/refreshtoken"
+	"app/internal/user"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/labstack/gommon/log"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrUnknownProvider      = errors.New("unknown identity provider")
+	ErrInvalidState         = errors.New("invalid or expired sign-in state")
+	ErrProviderUnavailable  = errors.New("identity provider is unavailable")
+	ErrAuthenticationFailed = errors.New("identity provider did not authenticate the user")
+	ErrNoEmail              = errors.New("identity provider did not return an email address")
+	ErrUnverifiedEmail      = errors.New("identity provider did not verify the email address")
+	ErrAccountExists        = errors.New("an account with this email already exists")
+	ErrRegistrationDisabled = errors.New("registration with an identity provider is disabled")
+	ErrIdentityLinked       = errors.New("identity is linked to another account")
+)
+
+// DefaultScopes are requested from a provider without configured scopes.
+var DefaultScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// providerTimeout bounds every request to an identity provider.
+const providerTimeout = 10 * time.Second
+
+// ProviderConfig describes an OpenID Connect identity provider users can
+// sign in with.
+type ProviderConfig struct {
+	// Name identifies the provider in URLs and linked identities, e.g.
+	// google. It must not change once users have signed in with it.
+	Name string
+	// DisplayName is shown on the sign-in button.
+	DisplayName string
+	// Issuer is the issuer URL the discovery document is fetched from.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// Config controls sign-in with external identity providers.
+type Config struct {
+	// CallbackURL is the frontend page the providers redirect to with the
+	// state and code query parameters. It is registered as the redirect URI
+	// with every provider.
+	CallbackURL string
+	// StateTTL is how long a sign-in may take at the provider.
+	StateTTL time.Duration
+	// AllowRegistration creates an account for a verified email address
+	// that has none.
+	AllowRegistration bool
+	// LinkVerifiedEmails links an identity to the account with the same
+	// email address at its first sign-in, if both the provider and the
+	// account have verified the address. Otherwise the user has to log in
+	// and link the identity.
+	LinkVerifiedEmails bool
+	Providers          []ProviderConfig
+}
+
+// Claims are the facts about a user an identity provider asserted.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+// Service is the OpenID Connect relying party. A sign-in starts by
+// redirecting the user to the provider with a random state, a nonce and a
+// PKCE challenge, all kept in a single-use FederationState. The callback
+// exchanges the code, verifies the ID token and logs in the user the
+// provider's subject is linked to. Providers are discovered on first use,
+// so an unreachable provider does not keep the server from starting.
+type Service struct {
+	Repo   Repository
+	Users  *user.Service
+	Config Config
+
+	mu          sync.Mutex
+	providers   map[string]*provider
+	discoveries map[string]*discovery
+}
+
+// discovery is a discovery of a provider in progress. Concurrent sign-ins
+// wait for it instead of fetching the discovery document themselves.
+type discovery struct {
+	done     chan struct{}
+	provider *provider
+	err      error
+}
+
+// provider is a discovered identity provider.
+type provider struct {
+	oidc     *oidc.Provider
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewService(repo Repository, users *user.Service, cfg Config) (*Service, error) {
+	seen := make(map[string]bool, len(cfg.Providers))
+	for i, p := range cfg.Providers {
+		switch {
+		case p.Name == "" || p.Issuer == "" || p.ClientID == "":
+			return nil, fmt.Errorf("identity provider %d: name, issuer and client id are required", i)
+		case seen[p.Name]:
+			return nil, fmt.Errorf("identity provider %q is configured twice", p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	return &Service{
+		Repo:        repo,
+		Users:       users,
+		Config:      cfg,
+		providers:   make(map[string]*provider),
+		discoveries: make(map[string]*discovery),
+	}, nil
+}
+
+// Providers returns the configured identity providers.
+func (s *Service) Providers() []ProviderConfig {
+	return s.Config.Providers
+}
+
+// Begin starts a sign-in at the named provider and returns the URL to send
+// the user to along with its state. userID is set when a logged in user
+// links another identity.
+func (s *Service) Begin(ctx context.Context, name string, userID *int) (string, string, error) {
+	p, err := s.provider(ctx, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := refreshtoken.Generate()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := refreshtoken.Generate()
+	if err != nil {
+		return "", "", err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	now := time.Now()
+	if err := s.Repo.DeleteExpiredStates(ctx, now); err != nil {
+		log.Error("Failed to delete expired federation states ", err.Error())
+	}
+	if err := s.Repo.CreateState(ctx, name, refreshtoken.Hash(state), nonce, verifier, userID, now.Add(s.Config.StateTTL)); err != nil {
+		return "", "", err
+	}
+
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), state, nil
+}
+
+// Callback finishes a sign-in with the state and code the provider
+// redirected with and returns the user to log in:
+//   - a linked identity logs in its user;
+//   - otherwise an account with the same verified email address gets the
+//     identity linked if LinkVerifiedEmails allows it, and ErrAccountExists
+//     is returned if not;
+//   - otherwise an account is created if AllowRegistration is set.
+//
+// New accounts and links require an email address the provider verified,
+// so that nobody can claim an address they do not own.
+func (s *Service) Callback(ctx context.Context, state string, code string) (*ent.User, error) {
+	st, err := s.takeState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	// States of linking can only be finished by the user who started them.
+	if st.UserID != nil {
+		return nil, ErrInvalidState
+	}
+
+	claims, err := s.exchange(ctx, st, code)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.signIn(ctx, st.Provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Users.Config.RequireVerifiedEmail && !u.EmailVerified {
+		return nil, user.ErrEmailNotVerified
+	}
+
+	// With two-factor authentication the failures are cleared once the
+	// second factor has been verified.
+	if s.Users.Config.LoginThrottle != nil && !u.TotpEnabled {
+		if err := s.Users.Config.LoginThrottle.Succeeded(ctx, u.Email); err != nil {
+			log.Error("Failed to reset login failures ", err.Error())
+		}
+	}
+
+	return u, nil
+}
+
+// Link finishes a sign-in started by Begin for userID and links the
+// identity to the user. The email address of the identity does not matter
+// here, as the user proved both accounts are theirs.
+func (s *Service) Link(ctx context.Context, userID int, state string, code string) (*ent.UserIdentity, error) {
+	st, err := s.takeState(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	if st.UserID == nil || *st.UserID != userID {
+		return nil, ErrInvalidState
+	}
+
+	claims, err := s.exchange(ctx, st, code)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := s.Repo.GetIdentity(ctx, st.Provider, claims.Subject)
+	switch {
+	case err == nil:
+		if i.UserID != userID {
+			return nil, ErrIdentityLinked
+		}
+		return i, nil
+	case !errors.Is(err, ErrIdentityNotFound):
+		return nil, err
+	}
+
+	i, err = s.Repo.CreateIdentity(ctx, userID, st.Provider, claims.Subject, claims.Email)
+	if errors.Is(err, ErrIdentityExists) {
+		// Linked by a concurrent callback.
+		return nil, ErrIdentityLinked
+	}
+	return i, err
+}
+
+// Identities returns the identities linked to a user.
+func (s *Service) Identities(ctx context.Context, userID int) ([]*ent.UserIdentity, error) {
+	return s.Repo.ListIdentities(ctx, userID)
+}
+
+// Unlink removes an identity of a user. It can no longer be used to log in.
+func (s *Service) Unlink(ctx context.Context, userID int, id int) error {
+	return s.Repo.DeleteIdentity(ctx, userID, id)
+}
+
+// signIn returns the user the identity belongs to, linking it or creating
+// an account at its first sign-in.
+func (s *Service) signIn(ctx context.Context, providerName string, claims *Claims) (*ent.User, error) {
+	i, err := s.Repo.GetIdentity(ctx, providerName, claims.Subject)
+	switch {
+	case err == nil:
+		email := claims.Email
+		if email == "" {
+			email = i.Email
+		}
+		if err := s.Repo.RecordLogin(ctx, i.ID, email); err != nil {
+			log.Error("Failed to record identity login ", err.Error())
+		}
+		return s.Users.GetByID(ctx, i.UserID)
+	case !errors.Is(err, ErrIdentityNotFound):
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrNoEmail
+	}
+	if !claims.EmailVerified {
+		return nil, ErrUnverifiedEmail
+	}
+
+	u, err := s.Users.GetByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// An unverified account may have been registered by someone who
+		// does not own the address.
+		if !s.Config.LinkVerifiedEmails || !u.EmailVerified {
+			return nil, ErrAccountExists
+		}
+	case errors.Is(err, user.ErrUserNotFound):
+		if !s.Config.AllowRegistration {
+			return nil, ErrRegistrationDisabled
+		}
+		u, err = s.Users.RegisterPasswordless(ctx, claims.Email)
+		if errors.Is(err, user.ErrEmailTaken) {
+			// Registered some other way in the meantime.
+			return nil, ErrAccountExists
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.Users.Repo.MarkEmailVerified(ctx, u.ID, u.Email); err != nil {
+			return nil, err
+		}
+		if u, err = s.Users.GetByID(ctx, u.ID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if _, err := s.Repo.CreateIdentity(ctx, u.ID, providerName, claims.Subject, claims.Email); err != nil {
+		if errors.Is(err, ErrIdentityExists) {
+			// Linked by a concurrent callback, possibly to another account.
+			return nil, ErrIdentityLinked
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Service) takeState(ctx context.Context, state string) (*ent.FederationState, error) {
+	st, err := s.Repo.TakeState(ctx, refreshtoken.Hash(state))
+	if err != nil {
+		if errors.Is(err, ErrStateNotFound) {
+			return nil, ErrInvalidState
+		}
+		return nil, err
+	}
+	return st, nil
+}
+
+// exchange redeems the code at the provider of a sign-in and returns the
+// claims of the verified ID token. The email address is fetched from the
+// UserInfo endpoint if the ID token does not carry it.
+func (s *Service) exchange(ctx context.Context, st *ent.FederationState, code string) (*Claims, error) {
+	p, err := s.provider(ctx, st.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(providerContext(ctx), providerTimeout)
+	defer cancel()
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(st.CodeVerifier))
+	if err != nil {
+		var retrieve *oauth2.RetrieveError
+		if errors.As(err, &retrieve) {
+			return nil, ErrAuthenticationFailed
+		}
+		log.Error("Failed to redeem code at identity provider ", err.Error())
+		return nil, ErrProviderUnavailable
+	}
+
+	raw, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, ErrAuthenticationFailed
+	}
+	idToken, err := p.verifier.Verify(ctx, raw)
+	if err != nil {
+		log.Warn("Rejected ID token from identity provider ", err.Error())
+		return nil, ErrAuthenticationFailed
+	}
+	if idToken.Nonce != st.Nonce {
+		return nil, ErrAuthenticationFailed
+	}
+
+	var c struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+	}
+	if err := idToken.Claims(&c); err != nil {
+		return nil, ErrAuthenticationFailed
+	}
+	claims := &Claims{
+		Subject:       idToken.Subject,
+		Email:         strings.TrimSpace(c.Email),
+		EmailVerified: isTrue(c.EmailVerified),
+	}
+
+	if claims.Email == "" && p.oidc.UserInfoEndpoint() != "" {
+		info, err := p.oidc.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err != nil {
+			log.Error("Failed to fetch user info from identity provider ", err.Error())
+			return nil, ErrProviderUnavailable
+		}
+		// The UserInfo response is only trusted for the same subject.
+		if info.Subject == claims.Subject {
+			claims.Email = strings.TrimSpace(info.Email)
+			claims.EmailVerified = info.EmailVerified
+		}
+	}
+
+	return claims, nil
+}
+
+// provider returns the named provider, discovering it on first use. Only a
+// successful discovery is kept; a failed one is retried by the next call.
+// The lock is not held while the provider is contacted, so a slow provider
+// does not hold up sign-ins with the others.
+func (s *Service) provider(ctx context.Context, name string) (*provider, error) {
+	var cfg *ProviderConfig
+	for i := range s.Config.Providers {
+		if s.Config.Providers[i].Name == name {
+			cfg = &s.Config.Providers[i]
+			break
+		}
+	}
+	if cfg == nil {
+		return nil, ErrUnknownProvider
+	}
+
+	s.mu.Lock()
+	if p, ok := s.providers[name]; ok {
+		s.mu.Unlock()
+		return p, nil
+	}
+	d, inProgress := s.discoveries[name]
+	if !inProgress {
+		d = &discovery{done: make(chan struct{})}
+		s.discoveries[name] = d
+	}
+	s.mu.Unlock()
+
+	if inProgress {
+		select {
+		case <-d.done:
+			return d.provider, d.err
+		case <-ctx.Done():
+			return nil, ErrProviderUnavailable
+		}
+	}
+
+	d.provider, d.err = s.discover(ctx, cfg)
+
+	s.mu.Lock()
+	if d.err == nil {
+		s.providers[name] = d.provider
+	}
+	delete(s.discoveries, name)
+	s.mu.Unlock()
+	close(d.done)
+
+	return d.provider, d.err
+}
+
+// discover fetches the discovery document of a provider.
+func (s *Service) discover(ctx context.Context, cfg *ProviderConfig) (*provider, error) {
+	ctx, cancel := context.WithTimeout(providerContext(ctx), providerTimeout)
+	defer cancel()
+
+	discovered, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		log.Error("Failed to discover identity provider ", cfg.Name, " ", err.Error())
+		return nil, ErrProviderUnavailable
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	return &provider{
+		oidc: discovered,
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     discovered.Endpoint(),
+			RedirectURL:  s.Config.CallbackURL,
+			Scopes:       scopes,
+		},
+		verifier: discovered.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// providerContext makes requests to identity providers use a client with a
+// timeout, including the key set fetches of the verifier, which outlive the
+// request that discovered the provider.
+func providerContext(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, &http.Client{Timeout: providerTimeout})
+}
+
+// isTrue reads email_verified, which some providers send as a string.
+func isTrue(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
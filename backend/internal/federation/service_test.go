@@ -0,0 +1,381 @@
+package federation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/testutil"
+	"app/internal/user"
+)
+
+const (
+	testClientID     = "rp"
+	testClientSecret = "rp-secret"
+	testCallbackURL  = "http://localhost:3000/federation/callback"
+)
+
+// idpUser is the account a user signs in with at the mock provider.
+type idpUser struct {
+	subject       string
+	email         string
+	emailVerified bool
+}
+
+// mockIdP is an OpenID Connect provider serving discovery, JWKS and token
+// endpoints. Sign-ins are answered without a login page: authorize issues a
+// code for whichever user the test names.
+type mockIdP struct {
+	t   *testing.T
+	srv *httptest.Server
+	jwt *auth.JWT
+
+	// failDiscovery makes the discovery document unavailable.
+	failDiscovery atomic.Bool
+	discoveries   atomic.Int32
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+// authorization is an issued code and the request it answers.
+type authorization struct {
+	user      idpUser
+	nonce     string
+	challenge string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	m := &mockIdP{t: t, codes: make(map[string]authorization)}
+	mux := http.NewServeMux()
+	m.srv = httptest.NewServer(mux)
+	t.Cleanup(m.srv.Close)
+
+	key, err := auth.GenerateKey("RS256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.jwt = auth.New(auth.NewKeyRing(key), auth.Config{TTL: time.Hour, Issuer: m.srv.URL})
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("GET /jwks", auth.NewHandler(m.jwt).JWKS)
+	mux.HandleFunc("POST /token", m.token)
+	return m
+}
+
+func (m *mockIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	m.discoveries.Add(1)
+	if m.failDiscovery.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"issuer":                                m.srv.URL,
+		"authorization_endpoint":                m.srv.URL + "/authorize",
+		"token_endpoint":                        m.srv.URL + "/token",
+		"jwks_uri":                              m.srv.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (m *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	a, ok := m.codes[code]
+	delete(m.codes, code)
+	m.mu.Unlock()
+
+	id, secret, _ := r.BasicAuth()
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || id != testClientID || secret != testClientSecret ||
+		r.PostForm.Get("redirect_uri") != testCallbackURL ||
+		base64.RawURLEncoding.EncodeToString(verifier[:]) != a.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	accessToken := "at-" + a.user.subject
+	claims := auth.IDTokenClaims{
+		Nonce:         a.nonce,
+		Email:         a.user.email,
+		EmailVerified: &a.user.emailVerified,
+	}
+	claims.Subject = a.user.subject
+	idToken, err := m.jwt.GenerateIDToken(claims, testClientID, accessToken, time.Minute)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+// authorize plays u signing in at the provider and returns the code it
+// redirects back with.
+func (m *mockIdP) authorize(authURL string, u idpUser) string {
+	m.t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	q := parsed.Query()
+	if q.Get("client_id") != testClientID || q.Get("redirect_uri") != testCallbackURL ||
+		q.Get("code_challenge_method") != "S256" || q.Get("nonce") == "" {
+		m.t.Fatalf("unexpected authorization request %s", authURL)
+	}
+
+	code := "code-" + q.Get("state")
+	m.mu.Lock()
+	m.codes[code] = authorization{user: u, nonce: q.Get("nonce"), challenge: q.Get("code_challenge")}
+	m.mu.Unlock()
+	return code
+}
+
+func newTestService(t *testing.T, idp *mockIdP) *Service {
+	t.Helper()
+
+	d := testutil.DB(t)
+	s, err := NewService(NewPostgresRepo(d), testutil.Users(d, user.Config{}), Config{
+		CallbackURL:        testCallbackURL,
+		StateTTL:           time.Minute,
+		AllowRegistration:  true,
+		LinkVerifiedEmails: true,
+		Providers: []ProviderConfig{
+			{Name: "mock", Issuer: idp.srv.URL, ClientID: testClientID, ClientSecret: testClientSecret},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// signIn runs a sign-in of u at the mock provider and returns the state and
+// code of the callback.
+func signIn(t *testing.T, s *Service, idp *mockIdP, userID *int, u idpUser) (string, string) {
+	t.Helper()
+
+	authURL, state, err := s.Begin(context.Background(), "mock", userID)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	return state, idp.authorize(authURL, u)
+}
+
+func newVerifiedUser(t *testing.T, s *Service, email string) *ent.User {
+	t.Helper()
+	ctx := context.Background()
+
+	u, err := s.Users.Register(ctx, email, "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Users.Repo.MarkEmailVerified(ctx, u.ID, u.Email); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestCallback(t *testing.T) {
+	idp := newMockIdP(t)
+	s := newTestService(t, idp)
+	ctx := context.Background()
+	alice := idpUser{subject: "alice", email: "alice@example.com", emailVerified: true}
+
+	state, code := signIn(t, s, idp, nil, alice)
+	u, err := s.Callback(ctx, state, code)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if u.Email != "alice@example.com" || !u.EmailVerified {
+		t.Fatalf("registered user = %+v", u)
+	}
+
+	// The identity logs in the same user again.
+	state, code = signIn(t, s, idp, nil, alice)
+	again, err := s.Callback(ctx, state, code)
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if again.ID != u.ID {
+		t.Fatalf("second sign-in logged in user %d, want %d", again.ID, u.ID)
+	}
+}
+
+func TestCallbackReplayedState(t *testing.T) {
+	idp := newMockIdP(t)
+	s := newTestService(t, idp)
+	ctx := context.Background()
+	alice := idpUser{subject: "alice", email: "alice@example.com", emailVerified: true}
+
+	state, code := signIn(t, s, idp, nil, alice)
+	if _, err := s.Callback(ctx, state, code); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if _, err := s.Callback(ctx, state, code); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("replayed state: got %v, want ErrInvalidState", err)
+	}
+
+	// A state is used up even if the code is rejected.
+	state, _ = signIn(t, s, idp, nil, alice)
+	if _, err := s.Callback(ctx, state, "bogus"); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Fatalf("bogus code: got %v, want ErrAuthenticationFailed", err)
+	}
+	if _, err := s.Callback(ctx, state, "bogus"); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("state of a failed sign-in: got %v, want ErrInvalidState", err)
+	}
+}
+
+func TestCallbackUnverifiedEmail(t *testing.T) {
+	idp := newMockIdP(t)
+	s := newTestService(t, idp)
+	ctx := context.Background()
+	carol := newVerifiedUser(t, s, "carol@example.com")
+
+	// Anybody can claim carol's address at a provider that does not verify
+	// it, so the identity must not be linked to her account.
+	state, code := signIn(t, s, idp, nil, idpUser{subject: "mallory", email: "carol@example.com"})
+	if _, err := s.Callback(ctx, state, code); !errors.Is(err, ErrUnverifiedEmail) {
+		t.Fatalf("unverified email of an account: got %v, want ErrUnverifiedEmail", err)
+	}
+	identities, err := s.Identities(ctx, carol.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(identities) != 0 {
+		t.Fatalf("identity linked to carol: %+v", identities)
+	}
+
+	// Nor does it create an account.
+	state, code = signIn(t, s, idp, nil, idpUser{subject: "eve", email: "eve@example.com"})
+	if _, err := s.Callback(ctx, state, code); !errors.Is(err, ErrUnverifiedEmail) {
+		t.Fatalf("unverified email: got %v, want ErrUnverifiedEmail", err)
+	}
+	if _, err := s.Users.GetByEmail(ctx, "eve@example.com"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("account for an unverified email: %v", err)
+	}
+
+	// With the address verified, the identity is linked to the account.
+	state, code = signIn(t, s, idp, nil, idpUser{subject: "carol", email: "carol@example.com", emailVerified: true})
+	u, err := s.Callback(ctx, state, code)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if u.ID != carol.ID {
+		t.Fatalf("verified email logged in user %d, want %d", u.ID, carol.ID)
+	}
+}
+
+func TestLink(t *testing.T) {
+	idp := newMockIdP(t)
+	s := newTestService(t, idp)
+	ctx := context.Background()
+	bob, err := s.Users.Register(ctx, "bob@example.com", "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := newVerifiedUser(t, s, "other@example.com")
+	// The address at the provider need not match, nor be verified.
+	bobAtIdP := idpUser{subject: "bob", email: "bob@example.org"}
+
+	// A link state is only good for the user who started it.
+	state, code := signIn(t, s, idp, &bob.ID, bobAtIdP)
+	if _, err := s.Link(ctx, other.ID, state, code); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("link finished by another user: got %v, want ErrInvalidState", err)
+	}
+	state, code = signIn(t, s, idp, &bob.ID, bobAtIdP)
+	if _, err := s.Callback(ctx, state, code); !errors.Is(err, ErrInvalidState) {
+		t.Fatalf("link state used to log in: got %v, want ErrInvalidState", err)
+	}
+
+	state, code = signIn(t, s, idp, &bob.ID, bobAtIdP)
+	i, err := s.Link(ctx, bob.ID, state, code)
+	if err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if i.UserID != bob.ID || i.Provider != "mock" || i.Subject != "bob" {
+		t.Fatalf("linked identity = %+v", i)
+	}
+
+	state, code = signIn(t, s, idp, nil, bobAtIdP)
+	u, err := s.Callback(ctx, state, code)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if u.ID != bob.ID {
+		t.Fatalf("linked identity logged in user %d, want %d", u.ID, bob.ID)
+	}
+
+	state, code = signIn(t, s, idp, &other.ID, bobAtIdP)
+	if _, err := s.Link(ctx, other.ID, state, code); !errors.Is(err, ErrIdentityLinked) {
+		t.Fatalf("identity of another user: got %v, want ErrIdentityLinked", err)
+	}
+}
+
+func TestProviderDiscovery(t *testing.T) {
+	idp := newMockIdP(t)
+	s := newTestService(t, idp)
+	ctx := context.Background()
+
+	if _, err := s.provider(ctx, "unknown"); !errors.Is(err, ErrUnknownProvider) {
+		t.Fatalf("unknown provider: got %v, want ErrUnknownProvider", err)
+	}
+
+	// A failed discovery is not kept.
+	idp.failDiscovery.Store(true)
+	if _, err := s.provider(ctx, "mock"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("unavailable provider: got %v, want ErrProviderUnavailable", err)
+	}
+	idp.failDiscovery.Store(false)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.provider(ctx, "mock"); err != nil {
+				t.Errorf("provider: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := s.provider(ctx, "mock"); err != nil {
+		t.Fatal(err)
+	}
+
+	// One failed attempt, then a single successful one shared by all.
+	if n := idp.discoveries.Load(); n != 2 {
+		t.Fatalf("discovery document fetched %d times, want 2", n)
+	}
+}
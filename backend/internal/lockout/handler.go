@@ -0,0 +1,89 @@
+package lockout
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/domain"
+	"app/internal/response"
+	"app/internal/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	Service *Service
+}
+
+func NewHandler(s *Service) *Handler {
+	return &Handler{Service: s}
+}
+
+// Unlock godoc
+// @Summary      Unlock account
+// @Description  Lift a login lockout with the token from the lockout email
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.UnlockAccountDTO true "Unlock token"
+// @Success      204 "Account unlocked"
+// @Failure      400 {object} domain.ErrorResponse "Invalid or expired unlock token"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/unlock [post]
+func (h *Handler) Unlock(w http.ResponseWriter, r *http.Request) {
+	var dto domain.UnlockAccountDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Token == "" {
+		response.Error(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	if err := h.Service.Unlock(r.Context(), dto.Token); err != nil {
+		if errors.Is(err, ErrInvalidUnlockToken) {
+			response.Error(w, http.StatusBadRequest, "invalid or expired unlock token")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnlockUser godoc
+// @Summary      Unlock user
+// @Description  Lift the login lockout of a user and forget their failed logins
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id path int true "User ID"
+// @Success      204 "User unlocked"
+// @Failure      400 {object} domain.ErrorResponse "Invalid user id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/unlock [post]
+func (h *Handler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if err := h.Service.UnlockUser(r.Context(), userID); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "user not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package lockout
+
+import (
+	"app/ent"
+	"app/ent/loginthrottle"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrThrottleNotFound = errors.New("login throttle not found")
+
+// Repository defines the interface for failed login data access.
+type Repository interface {
+	Get(ctx context.Context, key string) (*ent.LoginThrottle, error)
+	RecordFailure(ctx context.Context, key string, staleBefore time.Time) (*ent.LoginThrottle, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// Get retrieves the failure record of a key.
+func (r *PostgresRepo) Get(ctx context.Context, key string) (*ent.LoginThrottle, error) {
+	t, err := r.Db.Client.LoginThrottle.Query().
+		Where(loginthrottle.KeyEQ(key)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrThrottleNotFound
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// RecordFailure counts a failed login for key and returns the updated
+// record. Failures from before staleBefore are forgotten first. The counter
+// is incremented in the database, so concurrent failures are all counted.
+func (r *PostgresRepo) RecordFailure(ctx context.Context, key string, staleBefore time.Time) (*ent.LoginThrottle, error) {
+	err := r.Db.Client.LoginThrottle.Update().
+		Where(
+			loginthrottle.KeyEQ(key),
+			loginthrottle.LastFailureAtLT(staleBefore),
+		).
+		SetFailures(0).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.increment(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return r.Get(ctx, key)
+}
+
+func (r *PostgresRepo) increment(ctx context.Context, key string) error {
+	now := time.Now()
+	n, err := r.Db.Client.LoginThrottle.Update().
+		Where(loginthrottle.KeyEQ(key)).
+		AddFailures(1).
+		SetLastFailureAt(now).
+		Save(ctx)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	err = r.Db.Client.LoginThrottle.Create().
+		SetKey(key).
+		SetFailures(1).
+		SetLastFailureAt(now).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		// A concurrent failure created the record first.
+		return r.increment(ctx, key)
+	}
+	return err
+}
+
+// Lock locks key until the given time.
+func (r *PostgresRepo) Lock(ctx context.Context, key string, until time.Time) error {
+	return r.Db.Client.LoginThrottle.Update().
+		Where(loginthrottle.KeyEQ(key)).
+		SetLockedUntil(until).
+		Exec(ctx)
+}
+
+// Delete forgets every failure and lock of key.
+func (r *PostgresRepo) Delete(ctx context.Context, key string) error {
+	_, err := r.Db.Client.LoginThrottle.Delete().
+		Where(loginthrottle.KeyEQ(key)).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,264 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/mailer"
+	"app/internal/user"
+
+	"github.com/labstack/gommon/log"
+)
+
+// PurposeUnlockAccount is the purpose of account unlock action tokens.
+const PurposeUnlockAccount = "unlock_account"
+
+var ErrInvalidUnlockToken = errors.New("invalid or expired unlock token")
+
+// Limit configures throttling for one kind of key.
+type Limit struct {
+	// Free is how many failures are allowed before attempts are delayed.
+	Free int
+	// Lockout is the number of failures that locks the key. Zero disables
+	// the lockout and leaves only the delays.
+	Lockout int
+}
+
+// Config controls delays and lockouts.
+type Config struct {
+	Account Limit
+	IP      Limit
+	// BaseDelay is the delay after the first failure beyond the free ones.
+	// It doubles with each further failure, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Duration is how long a lockout lasts.
+	Duration time.Duration
+	// Window is how long failures are remembered after the last one.
+	Window time.Duration
+	// UnlockTTL is how long an unlock link stays valid.
+	UnlockTTL time.Duration
+	// UnlockLinkBaseURL is the frontend URL the unlock link points at; the
+	// token is appended as the token query parameter.
+	UnlockLinkBaseURL string
+}
+
+// Service counts failed logins per account email and per client IP. Once a
+// key has used up its free failures every further attempt must wait an
+// exponentially growing delay, and reaching the lockout threshold blocks the
+// key for a while. Locked accounts are mailed a link that lifts the lock.
+// Emails are tracked whether or not they belong to an account, so responses
+// do not reveal which accounts exist.
+type Service struct {
+	Repo   Repository
+	Users  user.Repository
+	JWT    *auth.JWT
+	Mailer mailer.Mailer
+	Config Config
+}
+
+func NewService(repo Repository, users user.Repository, jwt *auth.JWT, m mailer.Mailer, cfg Config) *Service {
+	return &Service{
+		Repo:   repo,
+		Users:  users,
+		JWT:    jwt,
+		Mailer: m,
+		Config: cfg,
+	}
+}
+
+// Check returns a *user.ThrottledError if a login for email from ip must
+// wait or is locked out. An account lockout takes precedence.
+func (s *Service) Check(ctx context.Context, email string, ip string) error {
+	now := time.Now()
+
+	account, err := s.get(ctx, accountKey(email))
+	if err != nil {
+		return err
+	}
+	if wait := lockedFor(account, now); wait > 0 {
+		return &user.ThrottledError{Locked: true, RetryAfter: wait}
+	}
+
+	var client *ent.LoginThrottle
+	if ip != "" {
+		if client, err = s.get(ctx, ipKey(ip)); err != nil {
+			return err
+		}
+		if wait := lockedFor(client, now); wait > 0 {
+			return &user.ThrottledError{RetryAfter: wait}
+		}
+	}
+
+	wait := max(s.delay(account, s.Config.Account, now), s.delay(client, s.Config.IP, now))
+	if wait > 0 {
+		return &user.ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Failed records a failed login for email from ip and applies lockouts.
+func (s *Service) Failed(ctx context.Context, email string, ip string) error {
+	staleBefore := time.Now().Add(-s.Config.Window)
+
+	account, err := s.Repo.RecordFailure(ctx, accountKey(email), staleBefore)
+	if err != nil {
+		return err
+	}
+	if reached(account, s.Config.Account) {
+		if err := s.Repo.Lock(ctx, account.Key, time.Now().Add(s.Config.Duration)); err != nil {
+			return err
+		}
+		log.Warnf("account locked after %d failed logins: email=%q ip=%q", account.Failures, email, ip)
+		if err := s.sendUnlock(ctx, email); err != nil {
+			log.Error("Failed to send unlock email ", err.Error())
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+	client, err := s.Repo.RecordFailure(ctx, ipKey(ip), staleBefore)
+	if err != nil {
+		return err
+	}
+	if reached(client, s.Config.IP) {
+		log.Warnf("ip blocked after %d failed logins: ip=%q", client.Failures, ip)
+		return s.Repo.Lock(ctx, client.Key, time.Now().Add(s.Config.Duration))
+	}
+	return nil
+}
+
+// Succeeded forgets the failures of the account. Failures of the IP are
+// kept so that one valid account cannot be used to keep guessing others.
+func (s *Service) Succeeded(ctx context.Context, email string) error {
+	return s.Repo.Delete(ctx, accountKey(email))
+}
+
+// Unlock lifts the lockout of the account named by an unlock token.
+func (s *Service) Unlock(ctx context.Context, token string) error {
+	claims, err := s.JWT.ParseAction(token, PurposeUnlockAccount)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+
+	u, err := s.Users.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return ErrInvalidUnlockToken
+		}
+		return err
+	}
+	// The link stops working when the address changes.
+	if u.Email != claims.Binding {
+		return ErrInvalidUnlockToken
+	}
+
+	return s.Repo.Delete(ctx, accountKey(u.Email))
+}
+
+// UnlockUser lifts the lockout of a user, e.g. on behalf of an administrator.
+func (s *Service) UnlockUser(ctx context.Context, id int) error {
+	u, err := s.Users.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Delete(ctx, accountKey(u.Email))
+}
+
+// sendUnlock mails an unlock link if email belongs to an account.
+func (s *Service) sendUnlock(ctx context.Context, email string) error {
+	u, err := s.Users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.JWT.GenerateAction(PurposeUnlockAccount, strconv.Itoa(u.ID), u.Email, s.Config.UnlockTTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.link(token)
+	if err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Your account has been locked",
+		Body: fmt.Sprintf(
+			"We locked your account for %s after too many failed sign-in attempts.\n\nIf that was you, unlock it now by opening the link below:\n\n%s\n\nThe link expires in %s. If it was not you, consider changing your password.\n",
+			s.Config.Duration, link, s.Config.UnlockTTL,
+		),
+	})
+}
+
+func (s *Service) get(ctx context.Context, key string) (*ent.LoginThrottle, error) {
+	t, err := s.Repo.Get(ctx, key)
+	if errors.Is(err, ErrThrottleNotFound) {
+		return nil, nil
+	}
+	return t, err
+}
+
+// delay returns how long the next attempt for t has to wait at now.
+func (s *Service) delay(t *ent.LoginThrottle, limit Limit, now time.Time) time.Duration {
+	if t == nil || t.Failures <= limit.Free || now.After(t.LastFailureAt.Add(s.Config.Window)) {
+		return 0
+	}
+
+	d := s.Config.BaseDelay
+	for i := limit.Free + 1; i < t.Failures && d < s.Config.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, s.Config.MaxDelay)
+
+	return max(t.LastFailureAt.Add(d).Sub(now), 0)
+}
+
+func (s *Service) link(token string) (string, error) {
+	u, err := url.Parse(s.Config.UnlockLinkBaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid unlock link base url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// lockedFor returns the remaining lockout of t at now.
+func lockedFor(t *ent.LoginThrottle, now time.Time) time.Duration {
+	if t == nil || t.LockedUntil == nil {
+		return 0
+	}
+	return max(t.LockedUntil.Sub(now), 0)
+}
+
+func reached(t *ent.LoginThrottle, limit Limit) bool {
+	return limit.Lockout > 0 && t.Failures >= limit.Lockout
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,117 @@
+package magiclink
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"app/domain"
+	"app/ent"
+	"app/internal/refreshtoken"
+	"app/internal/response"
+	"app/internal/user"
+
+	"github.com/labstack/gommon/log"
+)
+
+// LoginCompleter finishes a login the way password login does, asking for
+// the second factor if the user has one.
+type LoginCompleter interface {
+	CompleteLogin(w http.ResponseWriter, r *http.Request, u *ent.User)
+}
+
+type Handler struct {
+	Service *Service
+	Logins  LoginCompleter
+}
+
+func NewHandler(s *Service, logins LoginCompleter) *Handler {
+	return &Handler{Service: s, Logins: logins}
+}
+
+// Request godoc
+// @Summary      Request sign-in link
+// @Description  Email a single-use sign-in link and code if the address belongs to an account, or to any address when registration by link is enabled. The response does not reveal which. Requesting again invalidates the previous link; too many requests for an address are rejected with 429 and a Retry-After header in seconds.
+// @Tags         auth
+// @Accept       json
+// @Param        request body domain.MagicLinkRequestDTO true "Email address"
+// @Success      202 "Sign-in email sent if applicable"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Failure      429 {object} domain.ErrorResponse "Too many sign-in emails requested"
+// @Router       /auth/magic-link [post]
+func (h *Handler) Request(w http.ResponseWriter, r *http.Request) {
+	var dto domain.MagicLinkRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Email == "" {
+		response.Error(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	if err := h.Service.Request(r.Context(), dto.Email); err != nil {
+		var limited *RateLimitedError
+		if errors.As(err, &limited) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+			response.Error(w, http.StatusTooManyRequests, "too many sign-in emails requested, try again later")
+			return
+		}
+		// Other failures are only logged: an error response would tell the
+		// caller that the account exists.
+		log.Error("Failed to send sign-in email ", err.Error())
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Consume godoc
+// @Summary      Sign in with link or code
+// @Description  Log in with the token from a sign-in link, or with the email address and the code from the same email. Either works once. Wrong codes count as failed logins and are throttled the same way (423/429 with Retry-After). With two-factor authentication enabled the response is a domain.MFAChallengeResponse instead, to be completed at /auth/mfa/verify.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MagicLinkConsumeDTO true "Token, or email and code"
+// @Success      200 {object} domain.AuthResponse "Successfully logged in"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Failure      401 {object} domain.ErrorResponse "Invalid or expired link or code"
+// @Failure      423 {object} domain.ErrorResponse "Account temporarily locked"
+// @Failure      429 {object} domain.ErrorResponse "Too many login attempts"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/magic-link/consume [post]
+func (h *Handler) Consume(w http.ResponseWriter, r *http.Request) {
+	var dto domain.MagicLinkConsumeDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var u *ent.User
+	var err error
+	switch {
+	case dto.Token != "":
+		u, err = h.Service.Consume(r.Context(), dto.Token)
+	case dto.Email != "" && dto.Code != "":
+		u, err = h.Service.ConsumeCode(r.Context(), dto.Email, dto.Code, refreshtoken.ClientFromRequest(r).IP)
+	default:
+		response.Error(w, http.StatusBadRequest, "token, or email and code, are required")
+		return
+	}
+	if err != nil {
+		var throttled *user.ThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			user.RespondThrottled(w, throttled)
+		case errors.Is(err, ErrInvalidMagicLink), errors.Is(err, user.ErrUserNotFound):
+			response.Error(w, http.StatusUnauthorized, "invalid or expired sign-in link or code")
+		default:
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	h.Logins.CompleteLogin(w, r, u)
+}
@@ -0,0 +1,149 @@
+package magiclink
+
+import (
+	"app/ent"
+	"app/ent/magiclinktoken"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrTokenNotFound = errors.New("magic link token not found")
+
+// Repository defines the interface for magic link token data access.
+type Repository interface {
+	Create(ctx context.Context, email string, userID *int, tokenHash string, codeHash string, expiresAt time.Time) (*ent.MagicLinkToken, error)
+	CreatedSince(ctx context.Context, email string, since time.Time) ([]*ent.MagicLinkToken, error)
+	LatestUsable(ctx context.Context, email string) (*ent.MagicLinkToken, error)
+	Redeem(ctx context.Context, tokenHash string) (*ent.MagicLinkToken, error)
+	RedeemID(ctx context.Context, id int) (bool, error)
+	RecordFailedAttempt(ctx context.Context, id int, maxAttempts int) error
+	InvalidateForEmail(ctx context.Context, email string) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// Create inserts a new magic link token into the database.
+func (r *PostgresRepo) Create(ctx context.Context, email string, userID *int, tokenHash string, codeHash string, expiresAt time.Time) (*ent.MagicLinkToken, error) {
+	return r.Db.Client.MagicLinkToken.Create().
+		SetEmail(email).
+		SetNillableUserID(userID).
+		SetTokenHash(tokenHash).
+		SetCodeHash(codeHash).
+		SetExpiresAt(expiresAt).
+		Save(ctx)
+}
+
+// CreatedSince returns the tokens for email created after since, oldest
+// first, whether or not they have been used.
+func (r *PostgresRepo) CreatedSince(ctx context.Context, email string, since time.Time) ([]*ent.MagicLinkToken, error) {
+	return r.Db.Client.MagicLinkToken.Query().
+		Where(
+			magiclinktoken.EmailEQ(email),
+			magiclinktoken.CreatedAtGT(since),
+		).
+		Order(ent.Asc(magiclinktoken.FieldCreatedAt)).
+		All(ctx)
+}
+
+// LatestUsable returns the newest unused and unexpired token for email.
+func (r *PostgresRepo) LatestUsable(ctx context.Context, email string) (*ent.MagicLinkToken, error) {
+	token, err := r.Db.Client.MagicLinkToken.Query().
+		Where(
+			magiclinktoken.EmailEQ(email),
+			magiclinktoken.UsedAtIsNil(),
+			magiclinktoken.ExpiresAtGT(time.Now()),
+		).
+		Order(ent.Desc(magiclinktoken.FieldCreatedAt), ent.Desc(magiclinktoken.FieldID)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// Redeem marks the token as used if it is unused and unexpired and returns
+// it. The conditional update guarantees that a token is redeemed only once,
+// even by concurrent requests.
+func (r *PostgresRepo) Redeem(ctx context.Context, tokenHash string) (*ent.MagicLinkToken, error) {
+	now := time.Now()
+	n, err := r.Db.Client.MagicLinkToken.Update().
+		Where(
+			magiclinktoken.TokenHashEQ(tokenHash),
+			magiclinktoken.UsedAtIsNil(),
+			magiclinktoken.ExpiresAtGT(now),
+		).
+		SetUsedAt(now).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrTokenNotFound
+	}
+
+	return r.Db.Client.MagicLinkToken.Query().
+		Where(magiclinktoken.TokenHashEQ(tokenHash)).
+		Only(ctx)
+}
+
+// RedeemID is Redeem for a token found by its code. It reports false if the
+// token was used, superseded or expired in the meantime.
+func (r *PostgresRepo) RedeemID(ctx context.Context, id int) (bool, error) {
+	now := time.Now()
+	n, err := r.Db.Client.MagicLinkToken.Update().
+		Where(
+			magiclinktoken.IDEQ(id),
+			magiclinktoken.UsedAtIsNil(),
+			magiclinktoken.ExpiresAtGT(now),
+		).
+		SetUsedAt(now).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// RecordFailedAttempt counts a wrong code for the token and marks it as used
+// once maxAttempts wrong codes have been entered.
+func (r *PostgresRepo) RecordFailedAttempt(ctx context.Context, id int, maxAttempts int) error {
+	err := r.Db.Client.MagicLinkToken.UpdateOneID(id).
+		AddAttempts(1).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.Db.Client.MagicLinkToken.Update().
+		Where(
+			magiclinktoken.IDEQ(id),
+			magiclinktoken.AttemptsGTE(maxAttempts),
+			magiclinktoken.UsedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Exec(ctx)
+}
+
+// InvalidateForEmail marks every outstanding token for email as used.
+func (r *PostgresRepo) InvalidateForEmail(ctx context.Context, email string) error {
+	return r.Db.Client.MagicLinkToken.Update().
+		Where(
+			magiclinktoken.EmailEQ(email),
+			magiclinktoken.UsedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Exec(ctx)
+}
@@ -0,0 +1,292 @@
+package magiclink
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/mailer"
+	"app/internal/refreshtoken"
+	"app/internal/user"
+
+	"github.com/labstack/gommon/log"
+)
+
+// CodeLength is the number of digits of the code mailed with a link.
+const CodeLength = 6
+
+var ErrInvalidMagicLink = errors.New("invalid or expired sign-in link or code")
+
+// RateLimitedError rejects a request for another email to an address that
+// has been sent too many recently.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("too many sign-in emails requested, retry after %s", e.RetryAfter)
+}
+
+// Config controls sign-in links and codes.
+type Config struct {
+	// TTL is how long a link and its code stay valid.
+	TTL time.Duration
+	// LinkBaseURL is the frontend URL the link points at; the token is
+	// appended as the token query parameter.
+	LinkBaseURL string
+	// MaxAttempts is the number of wrong codes after which a code stops
+	// working.
+	MaxAttempts int
+	// MaxPerWindow limits the emails requested per address within Window.
+	// Zero disables the limit.
+	MaxPerWindow int
+	Window       time.Duration
+	// AllowRegistration creates an account when an address without one
+	// signs in.
+	AllowRegistration bool
+}
+
+// Service signs users in by email. Each request mails a link with a random
+// token and a short numeric code, either of which logs the user in once.
+// Both are stored only as SHA-256 hashes, and a new request supersedes the
+// previous one. Wrong codes count against the code and the login throttle.
+type Service struct {
+	Repo   Repository
+	Users  *user.Service
+	Mailer mailer.Mailer
+	Config Config
+}
+
+func NewService(repo Repository, users *user.Service, m mailer.Mailer, cfg Config) *Service {
+	return &Service{
+		Repo:   repo,
+		Users:  users,
+		Mailer: m,
+		Config: cfg,
+	}
+}
+
+// Request mails a sign-in link and code to email if it belongs to an account
+// or registration is allowed. Other addresses are ignored so callers cannot
+// probe which accounts exist. Too many requests for an address fail with a
+// *RateLimitedError, whether or not the address has an account.
+func (s *Service) Request(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+
+	if err := s.limit(ctx, email); err != nil {
+		return err
+	}
+
+	var userID *int
+	u, err := s.Users.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		userID = &u.ID
+	case !errors.Is(err, user.ErrUserNotFound):
+		return err
+	}
+
+	token, err := refreshtoken.Generate()
+	if err != nil {
+		return err
+	}
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+
+	if err := s.Repo.InvalidateForEmail(ctx, email); err != nil {
+		return err
+	}
+
+	// The request is recorded even when nothing is sent, so that the rate
+	// limit treats unknown addresses like existing ones.
+	expiresAt := time.Now().Add(s.Config.TTL)
+	if _, err := s.Repo.Create(ctx, email, userID, refreshtoken.Hash(token), refreshtoken.Hash(code), expiresAt); err != nil {
+		return err
+	}
+	if userID == nil && !s.Config.AllowRegistration {
+		return nil
+	}
+
+	link, err := s.link(token)
+	if err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf(
+			"Sign in by opening the link below:\n\n%s\n\nOr enter this code: %s\n\nThe link and the code expire in %s and work once. If you did not ask to sign in, ignore this email.\n",
+			link, code, s.Config.TTL,
+		),
+	})
+}
+
+// Consume redeems the token of a sign-in link and returns the user to log in.
+func (s *Service) Consume(ctx context.Context, token string) (*ent.User, error) {
+	t, err := s.Repo.Redeem(ctx, refreshtoken.Hash(token))
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return nil, ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	return s.signIn(ctx, t)
+}
+
+// ConsumeCode redeems the code last mailed to email for a client at ip and
+// returns the user to log in. Throttled attempts fail with a
+// *user.ThrottledError.
+func (s *Service) ConsumeCode(ctx context.Context, email string, code string, ip string) (*ent.User, error) {
+	email = strings.TrimSpace(email)
+
+	if s.Users.Config.LoginThrottle != nil {
+		if err := s.Users.Config.LoginThrottle.Check(ctx, email, ip); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := s.Repo.LatestUsable(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			s.failed(ctx, email, ip)
+			return nil, ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(refreshtoken.Hash(code)), []byte(t.CodeHash)) != 1 {
+		if err := s.Repo.RecordFailedAttempt(ctx, t.ID, s.Config.MaxAttempts); err != nil {
+			log.Error("Failed to record wrong sign-in code ", err.Error())
+		}
+		s.failed(ctx, email, ip)
+		return nil, ErrInvalidMagicLink
+	}
+
+	ok, err := s.Repo.RedeemID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMagicLink
+	}
+
+	return s.signIn(ctx, t)
+}
+
+// signIn returns the user a redeemed token was issued for, creating the
+// account if the address had none. Receiving the email proves the address,
+// so it is marked verified.
+func (s *Service) signIn(ctx context.Context, t *ent.MagicLinkToken) (*ent.User, error) {
+	var u *ent.User
+	var err error
+	if t.UserID != nil {
+		u, err = s.Users.GetByID(ctx, *t.UserID)
+		if err != nil {
+			return nil, err
+		}
+		// The user changed their address after the link was sent.
+		if u.Email != t.Email {
+			return nil, ErrInvalidMagicLink
+		}
+	} else {
+		if !s.Config.AllowRegistration {
+			return nil, ErrInvalidMagicLink
+		}
+		u, err = s.Users.RegisterPasswordless(ctx, t.Email)
+		if errors.Is(err, user.ErrEmailTaken) {
+			// Registered some other way since the link was sent.
+			u, err = s.Users.GetByEmail(ctx, t.Email)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !u.EmailVerified {
+		if _, err := s.Users.Repo.MarkEmailVerified(ctx, u.ID, u.Email); err != nil {
+			return nil, err
+		}
+		if u, err = s.Users.GetByID(ctx, u.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// With two-factor authentication the failures are cleared once the
+	// second factor has been verified.
+	if s.Users.Config.LoginThrottle != nil && !u.TotpEnabled {
+		if err := s.Users.Config.LoginThrottle.Succeeded(ctx, u.Email); err != nil {
+			log.Error("Failed to reset login failures ", err.Error())
+		}
+	}
+
+	return u, nil
+}
+
+// limit returns a *RateLimitedError if MaxPerWindow emails have been
+// requested for email within the window.
+func (s *Service) limit(ctx context.Context, email string) error {
+	if s.Config.MaxPerWindow <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	recent, err := s.Repo.CreatedSince(ctx, email, now.Add(-s.Config.Window))
+	if err != nil {
+		return err
+	}
+	if len(recent) < s.Config.MaxPerWindow {
+		return nil
+	}
+
+	// Another request is allowed once enough of them have left the window.
+	oldest := recent[len(recent)-s.Config.MaxPerWindow]
+	return &RateLimitedError{RetryAfter: oldest.CreatedAt.Add(s.Config.Window).Sub(now)}
+}
+
+// failed records a wrong code as a failed login. Errors are only logged so
+// that the caller still gets the invalid code error.
+func (s *Service) failed(ctx context.Context, email string, ip string) {
+	if s.Users.Config.LoginThrottle == nil {
+		return
+	}
+	if err := s.Users.Config.LoginThrottle.Failed(ctx, email, ip); err != nil {
+		log.Error("Failed to record failed login ", err.Error())
+	}
+}
+
+func (s *Service) link(token string) (string, error) {
+	u, err := url.Parse(s.Config.LinkBaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid magic link base url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// generateCode returns a uniformly random code of CodeLength digits.
+func generateCode() (string, error) {
+	max := big.NewInt(1)
+	for range CodeLength {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", CodeLength, n), nil
+}
@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/labstack/gommon/log"
+)
+
+// Log writes messages to the application log instead of delivering them.
+// It is used in development when no SMTP host is configured.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (Log) Send(ctx context.Context, msg Message) error {
+	log.Infof("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -3,38 +3,80 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"app/internal/auth"
+	"app/internal/response"
 )
 
 type ctxKey string
 
-const UserIDKey ctxKey = "user_id"
+const claimsKey ctxKey = "claims"
+
+// Verifier checks the credential of a request and returns the claims it
+// stands for. *auth.JWT verifies access tokens; other verifiers may accept
+// further kinds of credentials, such as API keys.
+type Verifier interface {
+	Verify(ctx context.Context, credential string) (*auth.Claims, error)
+}
+
+// Auth requires a valid credential whose caller is one of the accepted
+// subject types. Without any, only users are accepted, so that routes written
+// for users never see a service client or a token the user handed to an
+// OAuth client.
+func Auth(verifier Verifier, accept ...auth.SubjectType) func(http.Handler) http.Handler {
+	if len(accept) == 0 {
+		accept = []auth.SubjectType{auth.SubjectUser}
+	}
 
-func Auth(jwt *auth.JWT) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			h := r.Header.Get("Authorization")
 			if h == "" {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
 				return
 			}
 
 			parts := strings.Split(h, " ")
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
 				return
 			}
 
-			claims, err := jwt.Parse(parts[1])
+			claims, err := verifier.Verify(r.Context(), parts[1])
 			if err != nil {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			if !slices.Contains(accept, claims.Caller()) {
+				response.Error(w, http.StatusForbidden, "forbidden")
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
 		})
 	}
 }
+
+// WithClaims returns a copy of ctx carrying the authenticated token claims.
+func WithClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext returns the claims stored by Auth, if any.
+func ClaimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*auth.Claims)
+	return claims, ok && claims != nil
+}
+
+// UserIDFromContext returns the ID of the authenticated user.
+// The second result is false when the request did not pass through Auth or
+// was made by a service client.
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.IsClient() {
+		return 0, false
+	}
+	return claims.UserID, true
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"app/internal/auth"
+	"app/internal/response"
+)
+
+// RequireRole allows the request if the token carries at least one of roles.
+// It must be mounted after Auth.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			for _, role := range roles {
+				if claims.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			response.Error(w, http.StatusForbidden, "forbidden")
+		})
+	}
+}
+
+// RequirePermission allows the request only if the token carries every one
+// of permissions. It must be mounted after Auth.
+func RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			for _, permission := range permissions {
+				if !claims.HasPermission(permission) {
+					response.Error(w, http.StatusForbidden, "forbidden")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope allows the request only if the token was granted every one of
+// scopes. Scopes limit service clients, OAuth clients acting for a user and
+// API keys; tokens from a first-party login are not limited by them and
+// pass. It must be mounted after Auth.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				response.Error(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			if claims.Caller() == auth.SubjectUser {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, scope := range scopes {
+				if !claims.HasScope(scope) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+strings.Join(scopes, " ")+`"`)
+					response.Error(w, http.StatusForbidden, "insufficient scope")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
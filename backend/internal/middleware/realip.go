@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseProxies parses the addresses of trusted reverse proxies, each a CIDR
+// range or a single IP address.
+func ParseProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		if prefix, err := netip.ParsePrefix(p); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", p)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// RealIP replaces RemoteAddr with the address of the client when the request
+// was forwarded by one of the trusted proxies. The X-Forwarded-For header is
+// read from the right, skipping trusted proxies, so that addresses a client
+// puts in the header itself are never used; X-Real-IP is used without it.
+// Requests from other addresses keep their connection address, as anyone
+// can set these headers. Without trusted proxies the headers are ignored.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, p := range trusted {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(trusted) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			host := r.RemoteAddr
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			remote, err := netip.ParseAddr(host)
+			if err != nil || !isTrusted(remote) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if ip, ok := forwardedFor(r, isTrusted); ok {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedFor returns the client address reported by trusted proxies: the
+// last address in X-Forwarded-For that is not a trusted proxy, or X-Real-IP.
+func forwardedFor(r *http.Request, isTrusted func(netip.Addr) bool) (netip.Addr, bool) {
+	var hops []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+
+	if len(hops) > 0 {
+		var client netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// Everything left of a malformed entry is unverifiable.
+				break
+			}
+			client = addr.Unmap()
+			if !isTrusted(addr) {
+				break
+			}
+		}
+		return client, client.IsValid()
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP")))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,169 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"app/ent"
+	"app/internal/refreshtoken"
+	"app/internal/serviceclient"
+)
+
+var ErrInvalidClientSpec = errors.New("invalid client registration")
+
+// ClientSpec describes a client to register.
+type ClientSpec struct {
+	Name         string
+	RedirectURIs []string
+	// Scopes the client may request. Empty allows all SupportedScopes.
+	Scopes []string
+	// Public clients, such as SPAs and mobile apps, get no secret.
+	Public      bool
+	SkipConsent bool
+}
+
+// Clients manages the registered OAuth clients.
+type Clients struct {
+	Repo     Repository
+	Sessions *refreshtoken.Service
+}
+
+func NewClients(repo Repository, sessions *refreshtoken.Service) *Clients {
+	return &Clients{Repo: repo, Sessions: sessions}
+}
+
+// Register stores a new client and returns it together with its secret,
+// which is not stored and cannot be shown again. Public clients get no
+// secret.
+func (c *Clients) Register(ctx context.Context, spec ClientSpec) (*ent.OAuthClient, string, error) {
+	if len(spec.Scopes) == 0 {
+		spec.Scopes = SupportedScopes
+	}
+	if err := spec.validate(); err != nil {
+		return nil, "", err
+	}
+
+	clientID, err := newClientID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var secret, secretHash string
+	if !spec.Public {
+		if secret, err = refreshtoken.Generate(); err != nil {
+			return nil, "", err
+		}
+		secretHash = refreshtoken.Hash(secret)
+	}
+
+	client, err := c.Repo.CreateClient(ctx, clientID, secretHash, spec)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+// List returns every registered client.
+func (c *Clients) List(ctx context.Context) ([]*ent.OAuthClient, error) {
+	return c.Repo.ListClients(ctx)
+}
+
+// Delete removes a client and ends every session authorized for it.
+func (c *Clients) Delete(ctx context.Context, clientID string) error {
+	if err := c.Repo.DeleteClient(ctx, clientID); err != nil {
+		return err
+	}
+	return c.Sessions.RevokeAllForClient(ctx, clientID)
+}
+
+// IsPublic reports whether client authenticates without a secret.
+func IsPublic(client *ent.OAuthClient) bool {
+	return client.SecretHash == ""
+}
+
+// checkSecret reports whether secret is the secret of a confidential client.
+func checkSecret(client *ent.OAuthClient, secret string) bool {
+	if IsPublic(client) || secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(refreshtoken.Hash(secret)), []byte(client.SecretHash)) == 1
+}
+
+func (spec ClientSpec) validate() error {
+	if spec.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidClientSpec)
+	}
+	if len(spec.RedirectURIs) == 0 {
+		return fmt.Errorf("%w: at least one redirect uri is required", ErrInvalidClientSpec)
+	}
+	for _, uri := range spec.RedirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return fmt.Errorf("%w: redirect uri %q: %v", ErrInvalidClientSpec, uri, err)
+		}
+	}
+	if !slices.Contains(spec.Scopes, ScopeOpenID) {
+		return fmt.Errorf("%w: scopes must include %s", ErrInvalidClientSpec, ScopeOpenID)
+	}
+	for _, scope := range spec.Scopes {
+		if !slices.Contains(SupportedScopes, scope) {
+			return fmt.Errorf("%w: unsupported scope %q", ErrInvalidClientSpec, scope)
+		}
+	}
+	return nil
+}
+
+// validateRedirectURI accepts absolute URIs without a fragment. Plain http is
+// only allowed for loopback addresses, for local development and native apps;
+// native apps may also use a private scheme such as com.example.app.
+func validateRedirectURI(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" {
+		return errors.New("must be absolute")
+	}
+	if u.Fragment != "" {
+		return errors.New("must not contain a fragment")
+	}
+	if u.Scheme == "http" && !isLoopback(u.Hostname()) {
+		return errors.New("http is only allowed for localhost")
+	}
+	return nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newClientID returns a random client ID. IDs that start like those of
+// service clients are drawn again, as the token endpoints tell the two kinds
+// of clients apart by the prefix alone.
+func newClientID() (string, error) {
+	return generateClientID(rand.Reader)
+}
+
+func generateClientID(random io.Reader) (string, error) {
+	b := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(random, b); err != nil {
+			return "", fmt.Errorf("failed to generate client id: %w", err)
+		}
+		if id := base64.RawURLEncoding.EncodeToString(b); !strings.HasPrefix(id, serviceclient.ClientIDPrefix) {
+			return id, nil
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package oidc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"app/internal/serviceclient"
+)
+
+func TestGenerateClientIDAvoidsServiceClientPrefix(t *testing.T) {
+	// 16 random bytes whose encoding starts with the service client prefix,
+	// followed by an ordinary draw.
+	prefixed, err := base64.RawURLEncoding.DecodeString(serviceclient.ClientIDPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := append(prefixed, make([]byte, 16-len(prefixed))...)
+	if !strings.HasPrefix(base64.RawURLEncoding.EncodeToString(first), serviceclient.ClientIDPrefix) {
+		t.Fatal("first draw does not have the service client prefix")
+	}
+	second := make([]byte, 16)
+	if _, err := rand.Read(second); err != nil {
+		t.Fatal(err)
+	}
+	second[0] = 0
+
+	id, err := generateClientID(bytes.NewReader(append(first, second...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasPrefix(id, serviceclient.ClientIDPrefix) {
+		t.Fatalf("client id %q has the service client prefix", id)
+	}
+	if want := base64.RawURLEncoding.EncodeToString(second); id != want {
+		t.Fatalf("client id = %q, want the second draw %q", id, want)
+	}
+}
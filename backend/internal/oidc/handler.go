@@ -0,0 +1,527 @@
+package oidc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"app/domain"
+	"app/internal/middleware"
+	"app/internal/refreshtoken"
+	"app/internal/response"
+	"app/internal/serviceclient"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/labstack/gommon/log"
+)
+
+type Handler struct {
+	Service *Service
+	// Services handles the client_credentials grant for service clients,
+	// which are registered separately from OAuth clients.
+	Services *serviceclient.Service
+}
+
+func NewHandler(s *Service, services *serviceclient.Service) *Handler {
+	return &Handler{Service: s, Services: services}
+}
+
+// Discovery godoc
+// @Summary      OpenID Connect discovery
+// @Description  Provider metadata for OpenID Connect clients
+// @Tags         oidc
+// @Produce      json
+// @Success      200 {object} domain.OpenIDConfiguration "Provider metadata"
+// @Router       /.well-known/openid-configuration [get]
+func (h *Handler) Discovery(w http.ResponseWriter, r *http.Request) {
+	issuer := h.Service.JWT.Issuer()
+	base := strings.TrimSuffix(issuer, "/")
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	response.JSON(w, http.StatusOK, domain.OpenIDConfiguration{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             base + "/authorize",
+		TokenEndpoint:                     base + "/token",
+		UserInfoEndpoint:                  base + "/userinfo",
+		IntrospectionEndpoint:             base + "/oauth/introspect",
+		RevocationEndpoint:                base + "/oauth/revoke",
+		JWKSURI:                           base + "/.well-known/jwks.json",
+		ScopesSupported:                   SupportedScopes,
+		ResponseTypesSupported:            []string{"code"},
+		ResponseModesSupported:            []string{"query"},
+		GrantTypesSupported:               []string{GrantAuthorizationCode, GrantRefreshToken, GrantClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{h.Service.JWT.Keys().Current().Method.Alg()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		IntrospectionEndpointAuthMethods:  []string{"client_secret_basic", "client_secret_post"},
+		RevocationEndpointAuthMethods:     []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{CodeChallengeS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "azp", "at_hash", "email", "email_verified", "preferred_username"},
+		PromptValuesSupported:             []string{"none", "consent"},
+		AuthorizationResponseIssParameter: true,
+	})
+}
+
+// Authorize godoc
+// @Summary      Authorization endpoint
+// @Description  Start the authorization code flow. PKCE with S256 and the openid scope are required. A valid request is forwarded to the frontend login page with its parameters, which completes it at /oauth/authorize; an invalid one is redirected back to the client with an OAuth error. An unknown client or unregistered redirect_uri is reported here instead.
+// @Tags         oidc
+// @Param        client_id query string true "Client ID"
+// @Param        redirect_uri query string true "Registered redirect URI"
+// @Param        response_type query string true "Must be code"
+// @Param        scope query string true "Space-separated scopes, including openid"
+// @Param        state query string false "Opaque value returned to the client"
+// @Param        nonce query string false "Value copied into the ID token"
+// @Param        code_challenge query string true "Base64url-encoded SHA-256 digest of the code verifier"
+// @Param        code_challenge_method query string true "Must be S256"
+// @Param        prompt query string false "none or consent"
+// @Success      302 "Redirect to the login page, or back to the client with an error"
+// @Failure      400 {object} domain.ErrorResponse "Unknown client or redirect_uri"
+// @Router       /authorize [get]
+func (h *Handler) Authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	req := AuthorizationRequest{
+		ClientID:            r.Form.Get("client_id"),
+		RedirectURI:         r.Form.Get("redirect_uri"),
+		ResponseType:        r.Form.Get("response_type"),
+		Scope:               r.Form.Get("scope"),
+		State:               r.Form.Get("state"),
+		Nonce:               r.Form.Get("nonce"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+		Prompt:              r.Form.Get("prompt"),
+	}
+
+	if _, _, err := h.Service.Validate(r.Context(), req); err != nil {
+		var oerr *Error
+		switch {
+		case errors.As(err, &oerr):
+			http.Redirect(w, r, h.Service.ErrorRedirect(req, oerr), http.StatusFound)
+		case errors.Is(err, ErrUnknownClient), errors.Is(err, ErrInvalidRedirectURI):
+			response.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	to, err := h.Service.LoginRedirect(req)
+	if err != nil {
+		log.Error("Failed to build oidc login redirect ", err.Error())
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	http.Redirect(w, r, to, http.StatusFound)
+}
+
+// Complete godoc
+// @Summary      Complete authorization request
+// @Description  Complete an authorization request forwarded by /authorize for the authenticated user. If the user has not yet consented to the requested scopes the response asks for consent; send the request again with decision allow or deny. Otherwise redirect_to is the client callback carrying the authorization code or an OAuth error.
+// @Tags         oidc
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.OAuthAuthorizeDTO true "Authorization request and consent decision"
+// @Success      200 {object} domain.OAuthAuthorizeResponse "Consent prompt or redirect"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body, unknown client or redirect_uri"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /oauth/authorize [post]
+func (h *Handler) Complete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dto domain.OAuthAuthorizeDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	decision := Decision(dto.Decision)
+	if decision != DecisionNone && decision != DecisionAllow && decision != DecisionDeny {
+		response.Error(w, http.StatusBadRequest, "decision must be allow or deny")
+		return
+	}
+
+	req := AuthorizationRequest{
+		ClientID:            dto.ClientID,
+		RedirectURI:         dto.RedirectURI,
+		ResponseType:        dto.ResponseType,
+		Scope:               dto.Scope,
+		State:               dto.State,
+		Nonce:               dto.Nonce,
+		CodeChallenge:       dto.CodeChallenge,
+		CodeChallengeMethod: dto.CodeChallengeMethod,
+		Prompt:              dto.Prompt,
+	}
+
+	authz, err := h.Service.Authorize(r.Context(), userID, req, decision)
+	if err != nil {
+		if errors.Is(err, ErrUnknownClient) || errors.Is(err, ErrInvalidRedirectURI) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToAuthorizeResponse(authz))
+}
+
+// Token godoc
+// @Summary      Token endpoint
+// @Description  Exchange an authorization code together with its PKCE code_verifier, or rotate a refresh token. Confidential clients authenticate with HTTP Basic or client_id and client_secret form fields; public clients send only client_id. A refresh token is issued for the offline_access scope, an ID token for openid. A replayed authorization code ends the session started with it. Service clients use the client_credentials grant and get an access token for the requested scope, or all of their scopes.
+// @Tags         oidc
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        grant_type formData string true "authorization_code, refresh_token or client_credentials"
+// @Param        code formData string false "Authorization code"
+// @Param        redirect_uri formData string false "Redirect URI of the authorization request"
+// @Param        code_verifier formData string false "PKCE code verifier"
+// @Param        refresh_token formData string false "Refresh token"
+// @Param        scope formData string false "Space-separated scopes (client_credentials)"
+// @Param        client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param        client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success      200 {object} domain.OAuthTokenResponse "Tokens issued"
+// @Failure      400 {object} domain.OAuthErrorResponse "Invalid request or grant"
+// @Failure      401 {object} domain.OAuthErrorResponse "Client authentication failed"
+// @Failure      500 {object} domain.OAuthErrorResponse "Internal server error"
+// @Router       /token [post]
+func (h *Handler) Token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	if err := r.ParseForm(); err != nil {
+		respondError(w, oauthError("invalid_request", "the request body must be form-encoded"))
+		return
+	}
+
+	clientID, secret, basic, err := requestCredentials(r)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if r.PostForm.Get("grant_type") == GrantClientCredentials {
+		h.clientCredentials(w, r, clientID, secret, basic)
+		return
+	}
+
+	client, err := h.Service.AuthenticateClient(r.Context(), clientID, secret)
+	if err != nil {
+		if basic && errors.Is(err, ErrInvalidClient) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+		}
+		respondError(w, err)
+		return
+	}
+
+	tokens, err := h.Service.Exchange(r.Context(), client, TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+	}, refreshtoken.ClientFromRequest(r))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToTokenResponse(tokens))
+}
+
+// Introspect godoc
+// @Summary      Token introspection endpoint
+// @Description  Tell whether an access or refresh token is active (RFC 7662). Service clients registered with the introspect scope, e.g. resource servers, may introspect every token; other clients only the tokens issued to themselves, others are reported inactive. An access token of a session is inactive once the session has ended. Public clients cannot introspect tokens.
+// @Tags         oidc
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        token formData string true "Access or refresh token"
+// @Param        token_type_hint formData string false "access_token or refresh_token; ignored, the kind of token is told from the token"
+// @Param        client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param        client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success      200 {object} domain.OAuthIntrospectionResponse "Token description"
+// @Failure      400 {object} domain.OAuthErrorResponse "Invalid request"
+// @Failure      401 {object} domain.OAuthErrorResponse "Client authentication failed"
+// @Failure      500 {object} domain.OAuthErrorResponse "Internal server error"
+// @Router       /oauth/introspect [post]
+func (h *Handler) Introspect(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	token, caller, err := h.tokenRequest(w, r, false)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	in, err := h.Service.Introspect(r.Context(), caller, token)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToIntrospectionResponse(in))
+}
+
+// Revoke godoc
+// @Summary      Token revocation endpoint
+// @Description  Revoke a refresh token issued to the client, or an access token issued to it with one, by ending their session (RFC 7009). Unknown tokens and tokens of other clients are ignored, also for service clients with the introspect scope. Access tokens stay valid for the API until they expire, but are reported inactive by introspection. Access tokens without a refresh token cannot be revoked.
+// @Tags         oidc
+// @Accept       x-www-form-urlencoded
+// @Produce      json
+// @Param        token formData string true "Access or refresh token"
+// @Param        token_type_hint formData string false "access_token or refresh_token; ignored, the kind of token is told from the token"
+// @Param        client_id formData string false "Client ID, unless sent with HTTP Basic"
+// @Param        client_secret formData string false "Client secret, unless sent with HTTP Basic"
+// @Success      200 "Token revoked or ignored"
+// @Failure      400 {object} domain.OAuthErrorResponse "Invalid request or unsupported token type"
+// @Failure      401 {object} domain.OAuthErrorResponse "Client authentication failed"
+// @Failure      500 {object} domain.OAuthErrorResponse "Internal server error"
+// @Router       /oauth/revoke [post]
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	token, caller, err := h.tokenRequest(w, r, true)
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	if err := h.Service.Revoke(r.Context(), caller, token); err != nil {
+		respondError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// UserInfo godoc
+// @Summary      UserInfo endpoint
+// @Description  Claims about the user released for the scopes of the access token, which must have been issued to an OAuth client for the openid scope
+// @Tags         oidc
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.UserInfoResponse "User claims"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.OAuthErrorResponse "Token lacks the openid scope"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /userinfo [get]
+func (h *Handler) UserInfo(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	info, err := h.Service.UserInfo(r.Context(), claims)
+	if err != nil {
+		var oerr *Error
+		if errors.As(err, &oerr) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="`+oerr.Code+`"`)
+			if oerr.Code == "insufficient_scope" {
+				response.JSON(w, http.StatusForbidden, toErrorResponse(oerr))
+			} else {
+				response.JSON(w, http.StatusUnauthorized, toErrorResponse(oerr))
+			}
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToUserInfoResponse(info))
+}
+
+// ListConsents godoc
+// @Summary      List consents
+// @Description  List the OAuth clients the authenticated user has shared scopes with
+// @Tags         oidc
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.OAuthConsentResponse "Consents"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/consents [get]
+func (h *Handler) ListConsents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	consents, err := h.Service.Consents(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToConsentResponses(consents))
+}
+
+// RevokeConsent godoc
+// @Summary      Revoke consent
+// @Description  Withdraw the consent given to a client and end its sessions of the authenticated user. The client has to ask for consent again. Access tokens already issued to it stay valid until they expire.
+// @Tags         oidc
+// @Security     BearerAuth
+// @Param        client_id path string true "Client ID"
+// @Success      204 "Consent revoked"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "Consent not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/consents/{client_id} [delete]
+func (h *Handler) RevokeConsent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.Service.RevokeConsent(r.Context(), userID, chi.URLParam(r, "client_id")); err != nil {
+		if errors.Is(err, ErrConsentNotFound) {
+			response.Error(w, http.StatusNotFound, "consent not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clientCredentials issues an access token to a service client.
+func (h *Handler) clientCredentials(w http.ResponseWriter, r *http.Request, clientID string, secret string, basic bool) {
+	token, err := h.Services.Token(r.Context(), clientID, secret, r.PostForm.Get("scope"))
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceclient.ErrInvalidClient):
+			if basic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+			}
+			respondError(w, ErrInvalidClient)
+		case errors.Is(err, serviceclient.ErrInvalidScope):
+			respondError(w, oauthError("invalid_scope", err.Error()))
+		default:
+			respondError(w, err)
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusOK, domain.OAuthTokenResponse{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(token.ExpiresIn.Seconds()),
+		Scope:       token.Scope,
+	})
+}
+
+// tokenRequest authenticates the client of an introspection or revocation
+// request and returns the token it is about. Public clients are accepted if
+// allowPublic is set.
+func (h *Handler) tokenRequest(w http.ResponseWriter, r *http.Request, allowPublic bool) (string, Caller, error) {
+	if err := r.ParseForm(); err != nil {
+		return "", Caller{}, oauthError("invalid_request", "the request body must be form-encoded")
+	}
+
+	clientID, secret, basic, err := requestCredentials(r)
+	if err != nil {
+		return "", Caller{}, err
+	}
+
+	caller, err := h.authenticateCaller(r, clientID, secret, allowPublic)
+	if err != nil {
+		if basic && errors.Is(err, ErrInvalidClient) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+		}
+		return "", Caller{}, err
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		return "", Caller{}, oauthError("invalid_request", "token is required")
+	}
+	return token, caller, nil
+}
+
+// authenticateCaller authenticates a service client or an OAuth client,
+// told apart by the client ID. Only service clients registered with the
+// introspect scope act as resource servers.
+func (h *Handler) authenticateCaller(r *http.Request, clientID string, secret string, allowPublic bool) (Caller, error) {
+	if strings.HasPrefix(clientID, serviceclient.ClientIDPrefix) {
+		client, err := h.Services.Authenticate(r.Context(), clientID, secret)
+		if err != nil {
+			if errors.Is(err, serviceclient.ErrInvalidClient) {
+				return Caller{}, ErrInvalidClient
+			}
+			return Caller{}, err
+		}
+		return Caller{
+			ClientID:       client.ClientID,
+			ResourceServer: slices.Contains(client.Scopes, serviceclient.ScopeIntrospect),
+		}, nil
+	}
+
+	client, err := h.Service.AuthenticateClient(r.Context(), clientID, secret)
+	if err != nil {
+		return Caller{}, err
+	}
+	if IsPublic(client) && !allowPublic {
+		return Caller{}, ErrInvalidClient
+	}
+	return Caller{ClientID: client.ClientID}, nil
+}
+
+// requestCredentials returns the client ID and secret of a token request and
+// whether they were sent with HTTP Basic authentication.
+func requestCredentials(r *http.Request) (string, string, bool, error) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"), false, nil
+	}
+
+	if r.PostForm.Get("client_secret") != "" {
+		return "", "", true, oauthError("invalid_request", "client credentials must be sent only once")
+	}
+	// The credentials are form-encoded before being put in the header.
+	id, err := url.QueryUnescape(id)
+	if err != nil {
+		return "", "", true, ErrInvalidClient
+	}
+	secret, err = url.QueryUnescape(secret)
+	if err != nil {
+		return "", "", true, ErrInvalidClient
+	}
+	if formID := r.PostForm.Get("client_id"); formID != "" && formID != id {
+		return "", "", true, ErrInvalidClient
+	}
+	return id, secret, true, nil
+}
+
+// respondError writes err as an OAuth error response.
+func respondError(w http.ResponseWriter, err error) {
+	var oerr *Error
+	if !errors.As(err, &oerr) {
+		log.Error("Token request failed ", err.Error())
+		response.JSON(w, http.StatusInternalServerError, domain.OAuthErrorResponse{Error: "server_error"})
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oerr.Code == ErrInvalidClient.Code {
+		status = http.StatusUnauthorized
+	}
+	response.JSON(w, status, toErrorResponse(oerr))
+}
+
+func toErrorResponse(err *Error) domain.OAuthErrorResponse {
+	return domain.OAuthErrorResponse{Error: err.Code, ErrorDescription: err.Description}
+}
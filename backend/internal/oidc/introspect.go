@@ -0,0 +1,167 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"app/internal/auth"
+	"app/internal/refreshtoken"
+)
+
+// Caller is the authenticated client of an introspection or revocation
+// request.
+type Caller struct {
+	ClientID string
+	// ResourceServer is set for service clients registered with the
+	// introspect scope. They may introspect every token, while other clients
+	// only see the tokens issued to themselves. It grants no revocations.
+	ResourceServer bool
+}
+
+// owns reports whether the token issued to clientID belongs to the caller.
+func (c Caller) owns(clientID string) bool {
+	return clientID != "" && clientID == c.ClientID
+}
+
+// Introspection describes a token. Only Active is set for a token that is
+// invalid, expired, revoked or hidden from the caller.
+type Introspection struct {
+	Active    bool
+	TokenType string
+	Scope     string
+	ClientID  string
+	Subject   string
+	Issuer    string
+	Audience  []string
+	SessionID string
+	TokenID   string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+	NotBefore time.Time
+}
+
+// Introspect reports whether token is active. An access token is active
+// while it is valid and, if it belongs to a session, the session has not
+// ended; a refresh token while it could be used for a refresh. The kind of
+// token is told from the token itself.
+func (s *Service) Introspect(ctx context.Context, caller Caller, token string) (*Introspection, error) {
+	if claims, err := s.JWT.Parse(token); err == nil {
+		return s.introspectAccessToken(ctx, caller, claims)
+	}
+
+	rt, err := s.Sessions.Inspect(ctx, token)
+	if err != nil {
+		if isInvalidRefreshToken(err) {
+			return &Introspection{}, nil
+		}
+		return nil, err
+	}
+	if !caller.ResourceServer && !caller.owns(rt.OauthClientID) {
+		return &Introspection{}, nil
+	}
+
+	return &Introspection{
+		Active:    true,
+		TokenType: "refresh_token",
+		Scope:     rt.Scope,
+		ClientID:  rt.OauthClientID,
+		Subject:   strconv.Itoa(rt.UserID),
+		Issuer:    s.JWT.Issuer(),
+		SessionID: rt.FamilyID,
+		ExpiresAt: rt.ExpiresAt,
+		IssuedAt:  rt.CreatedAt,
+	}, nil
+}
+
+func (s *Service) introspectAccessToken(ctx context.Context, caller Caller, claims *auth.Claims) (*Introspection, error) {
+	if !caller.ResourceServer && !caller.owns(claims.ClientID) {
+		return &Introspection{}, nil
+	}
+
+	if !claims.IsClient() && claims.SessionID != "" {
+		active, err := s.Sessions.SessionActive(ctx, claims.UserID, claims.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return &Introspection{}, nil
+		}
+	}
+
+	in := &Introspection{
+		Active:    true,
+		TokenType: "Bearer",
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		SessionID: claims.SessionID,
+		TokenID:   claims.ID,
+	}
+	// Tokens issued before sub was introduced only carry user_id.
+	if in.Subject == "" {
+		in.Subject = strconv.Itoa(claims.UserID)
+	}
+	if claims.ExpiresAt != nil {
+		in.ExpiresAt = claims.ExpiresAt.Time
+	}
+	if claims.IssuedAt != nil {
+		in.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.NotBefore != nil {
+		in.NotBefore = claims.NotBefore.Time
+	}
+	return in, nil
+}
+
+// Revoke ends the session of a refresh token, or of an access token that
+// belongs to one, issued to the caller. Unknown tokens and tokens of other
+// clients are ignored, as the request has then nothing to revoke; a
+// resource server may inspect such tokens but not revoke them. Access
+// tokens without a session cannot be revoked and stay valid until they
+// expire, as do those of a revoked session when presented to the API.
+func (s *Service) Revoke(ctx context.Context, caller Caller, token string) error {
+	if claims, err := s.JWT.Parse(token); err == nil {
+		if !caller.owns(claims.ClientID) {
+			return nil
+		}
+		if claims.IsClient() || claims.SessionID == "" {
+			return oauthError("unsupported_token_type", "access tokens without a refresh token cannot be revoked")
+		}
+		return s.revokeSession(ctx, claims.UserID, claims.SessionID)
+	}
+
+	rt, err := s.Sessions.Find(ctx, token)
+	if err != nil {
+		if errors.Is(err, refreshtoken.ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+	if !caller.owns(rt.OauthClientID) {
+		return nil
+	}
+	return s.revokeSession(ctx, rt.UserID, rt.FamilyID)
+}
+
+func (s *Service) revokeSession(ctx context.Context, userID int, sessionID string) error {
+	err := s.Sessions.RevokeSession(ctx, userID, sessionID)
+	if err != nil && !errors.Is(err, refreshtoken.ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
+
+// isInvalidRefreshToken reports whether err means the refresh token cannot
+// be used, as opposed to a failure to look it up.
+func isInvalidRefreshToken(err error) bool {
+	return errors.Is(err, refreshtoken.ErrInvalidRefreshToken) ||
+		errors.Is(err, refreshtoken.ErrExpiredRefreshToken) ||
+		errors.Is(err, refreshtoken.ErrRevokedRefreshToken) ||
+		errors.Is(err, refreshtoken.ErrRefreshTokenReused) ||
+		errors.Is(err, refreshtoken.ErrSessionLifetimeExceeded) ||
+		errors.Is(err, refreshtoken.ErrSessionIdleTimeout)
+}
@@ -0,0 +1,92 @@
+package oidc
+
+import (
+	"time"
+
+	"app/domain"
+	"app/ent"
+	"app/internal/auth"
+)
+
+func ToTokenResponse(t *Tokens) domain.OAuthTokenResponse {
+	return domain.OAuthTokenResponse{
+		AccessToken:  t.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(t.ExpiresIn.Seconds()),
+		RefreshToken: t.RefreshToken,
+		IDToken:      t.IDToken,
+		Scope:        t.Scope,
+	}
+}
+
+func ToUserInfoResponse(c *auth.IDTokenClaims) domain.UserInfoResponse {
+	return domain.UserInfoResponse{
+		Subject:           c.Subject,
+		Email:             c.Email,
+		EmailVerified:     c.EmailVerified,
+		PreferredUsername: c.PreferredUsername,
+	}
+}
+
+func ToAuthorizeResponse(a *Authorization) domain.OAuthAuthorizeResponse {
+	resp := domain.OAuthAuthorizeResponse{
+		RedirectTo:      a.RedirectTo,
+		ConsentRequired: a.ConsentRequired,
+		Scopes:          a.Scopes,
+	}
+	if a.Client != nil {
+		resp.ClientID = a.Client.ClientID
+		resp.ClientName = a.Client.Name
+	}
+	return resp
+}
+
+// ToConsentResponse maps a consent. The client edge must have been loaded.
+func ToConsentResponse(c *ent.OAuthConsent) domain.OAuthConsentResponse {
+	resp := domain.OAuthConsentResponse{
+		Scopes:    c.Scopes,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	if c.Edges.Client != nil {
+		resp.ClientID = c.Edges.Client.ClientID
+		resp.ClientName = c.Edges.Client.Name
+	}
+	return resp
+}
+
+func ToConsentResponses(consents []*ent.OAuthConsent) []domain.OAuthConsentResponse {
+	resp := make([]domain.OAuthConsentResponse, len(consents))
+	for i, c := range consents {
+		resp[i] = ToConsentResponse(c)
+	}
+	return resp
+}
+
+func ToIntrospectionResponse(in *Introspection) domain.OAuthIntrospectionResponse {
+	if !in.Active {
+		return domain.OAuthIntrospectionResponse{}
+	}
+	return domain.OAuthIntrospectionResponse{
+		Active:    true,
+		TokenType: in.TokenType,
+		Scope:     in.Scope,
+		ClientID:  in.ClientID,
+		Subject:   in.Subject,
+		Issuer:    in.Issuer,
+		Audience:  in.Audience,
+		SessionID: in.SessionID,
+		TokenID:   in.TokenID,
+		ExpiresAt: unixTime(in.ExpiresAt),
+		IssuedAt:  unixTime(in.IssuedAt),
+		NotBefore: unixTime(in.NotBefore),
+	}
+}
+
+// unixTime returns t in seconds since the epoch, or zero for the zero time.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
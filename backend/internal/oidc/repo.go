@@ -0,0 +1,242 @@
+package oidc
+
+import (
+	"app/ent"
+	"app/ent/authorizationcode"
+	"app/ent/oauthclient"
+	"app/ent/oauthconsent"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrClientNotFound  = errors.New("oauth client not found")
+	ErrClientExists    = errors.New("oauth client already exists")
+	ErrCodeNotFound    = errors.New("authorization code not found")
+	ErrConsentNotFound = errors.New("consent not found")
+)
+
+// Repository defines the interface for OAuth client, authorization code and
+// consent data access.
+type Repository interface {
+	CreateClient(ctx context.Context, clientID string, secretHash string, spec ClientSpec) (*ent.OAuthClient, error)
+	GetClient(ctx context.Context, clientID string) (*ent.OAuthClient, error)
+	ListClients(ctx context.Context) ([]*ent.OAuthClient, error)
+	DeleteClient(ctx context.Context, clientID string) error
+	CreateCode(ctx context.Context, codeHash string, clientID int, userID int, req AuthorizationRequest, scopes []string, expiresAt time.Time) error
+	GetCode(ctx context.Context, codeHash string) (*ent.AuthorizationCode, error)
+	RedeemCode(ctx context.Context, codeHash string) (*ent.AuthorizationCode, error)
+	SetCodeSession(ctx context.Context, id int, sessionID string) error
+	DeleteExpiredCodes(ctx context.Context, before time.Time) error
+	GetConsent(ctx context.Context, userID int, clientID int) (*ent.OAuthConsent, error)
+	SaveConsent(ctx context.Context, userID int, clientID int, scopes []string) error
+	ListConsents(ctx context.Context, userID int) ([]*ent.OAuthConsent, error)
+	DeleteConsent(ctx context.Context, userID int, clientID int) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// CreateClient inserts a new client. Public clients have no secret hash.
+func (r *PostgresRepo) CreateClient(ctx context.Context, clientID string, secretHash string, spec ClientSpec) (*ent.OAuthClient, error) {
+	c, err := r.Db.Client.OAuthClient.Create().
+		SetClientID(clientID).
+		SetSecretHash(secretHash).
+		SetName(spec.Name).
+		SetRedirectUris(spec.RedirectURIs).
+		SetScopes(spec.Scopes).
+		SetSkipConsent(spec.SkipConsent).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrClientExists
+	}
+	return c, err
+}
+
+// GetClient returns the client with the given client_id.
+func (r *PostgresRepo) GetClient(ctx context.Context, clientID string) (*ent.OAuthClient, error) {
+	c, err := r.Db.Client.OAuthClient.Query().
+		Where(oauthclient.ClientIDEQ(clientID)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrClientNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListClients returns every client, oldest first.
+func (r *PostgresRepo) ListClients(ctx context.Context) ([]*ent.OAuthClient, error) {
+	return r.Db.Client.OAuthClient.Query().
+		Order(ent.Asc(oauthclient.FieldCreatedAt), ent.Asc(oauthclient.FieldID)).
+		All(ctx)
+}
+
+// DeleteClient removes a client together with its codes and consents.
+func (r *PostgresRepo) DeleteClient(ctx context.Context, clientID string) error {
+	n, err := r.Db.Client.OAuthClient.Delete().
+		Where(oauthclient.ClientIDEQ(clientID)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrClientNotFound
+	}
+	return nil
+}
+
+// CreateCode stores a new authorization code for the request.
+func (r *PostgresRepo) CreateCode(ctx context.Context, codeHash string, clientID int, userID int, req AuthorizationRequest, scopes []string, expiresAt time.Time) error {
+	return r.Db.Client.AuthorizationCode.Create().
+		SetCodeHash(codeHash).
+		SetOauthClientID(clientID).
+		SetUserID(userID).
+		SetRedirectURI(req.RedirectURI).
+		SetScopes(scopes).
+		SetNonce(req.Nonce).
+		SetCodeChallenge(req.CodeChallenge).
+		SetCodeChallengeMethod(req.CodeChallengeMethod).
+		SetExpiresAt(expiresAt).
+		Exec(ctx)
+}
+
+// GetCode returns a code whether or not it has been used or has expired.
+func (r *PostgresRepo) GetCode(ctx context.Context, codeHash string) (*ent.AuthorizationCode, error) {
+	c, err := r.Db.Client.AuthorizationCode.Query().
+		Where(authorizationcode.CodeHashEQ(codeHash)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrCodeNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// RedeemCode marks the code as used if it is unused and unexpired and
+// returns it. The conditional update guarantees that a code is exchanged
+// only once, even by concurrent requests.
+func (r *PostgresRepo) RedeemCode(ctx context.Context, codeHash string) (*ent.AuthorizationCode, error) {
+	now := time.Now()
+	n, err := r.Db.Client.AuthorizationCode.Update().
+		Where(
+			authorizationcode.CodeHashEQ(codeHash),
+			authorizationcode.UsedAtIsNil(),
+			authorizationcode.ExpiresAtGT(now),
+		).
+		SetUsedAt(now).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrCodeNotFound
+	}
+
+	return r.GetCode(ctx, codeHash)
+}
+
+// SetCodeSession records the session started by exchanging a code.
+func (r *PostgresRepo) SetCodeSession(ctx context.Context, id int, sessionID string) error {
+	return r.Db.Client.AuthorizationCode.UpdateOneID(id).
+		SetSessionID(sessionID).
+		Exec(ctx)
+}
+
+// DeleteExpiredCodes removes codes that expired before the given time. Used
+// codes are kept until then so that a replay is still recognised.
+func (r *PostgresRepo) DeleteExpiredCodes(ctx context.Context, before time.Time) error {
+	_, err := r.Db.Client.AuthorizationCode.Delete().
+		Where(authorizationcode.ExpiresAtLT(before)).
+		Exec(ctx)
+	return err
+}
+
+// GetConsent returns the consent a user gave a client.
+func (r *PostgresRepo) GetConsent(ctx context.Context, userID int, clientID int) (*ent.OAuthConsent, error) {
+	c, err := r.Db.Client.OAuthConsent.Query().
+		Where(
+			oauthconsent.UserIDEQ(userID),
+			oauthconsent.OauthClientIDEQ(clientID),
+		).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrConsentNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// SaveConsent records that the user agreed to share scopes with the client,
+// replacing an earlier consent.
+func (r *PostgresRepo) SaveConsent(ctx context.Context, userID int, clientID int, scopes []string) error {
+	n, err := r.Db.Client.OAuthConsent.Update().
+		Where(
+			oauthconsent.UserIDEQ(userID),
+			oauthconsent.OauthClientIDEQ(clientID),
+		).
+		SetScopes(scopes).
+		Save(ctx)
+	if err != nil || n > 0 {
+		return err
+	}
+
+	err = r.Db.Client.OAuthConsent.Create().
+		SetUserID(userID).
+		SetOauthClientID(clientID).
+		SetScopes(scopes).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		// A concurrent request created it first.
+		return r.Db.Client.OAuthConsent.Update().
+			Where(
+				oauthconsent.UserIDEQ(userID),
+				oauthconsent.OauthClientIDEQ(clientID),
+			).
+			SetScopes(scopes).
+			Exec(ctx)
+	}
+	return err
+}
+
+// ListConsents returns the consents of a user with their clients, oldest
+// first.
+func (r *PostgresRepo) ListConsents(ctx context.Context, userID int) ([]*ent.OAuthConsent, error) {
+	return r.Db.Client.OAuthConsent.Query().
+		Where(oauthconsent.UserIDEQ(userID)).
+		WithClient().
+		Order(ent.Asc(oauthconsent.FieldCreatedAt), ent.Asc(oauthconsent.FieldID)).
+		All(ctx)
+}
+
+// DeleteConsent removes the consent a user gave a client.
+func (r *PostgresRepo) DeleteConsent(ctx context.Context, userID int, clientID int) error {
+	n, err := r.Db.Client.OAuthConsent.Delete().
+		Where(
+			oauthconsent.UserIDEQ(userID),
+			oauthconsent.OauthClientIDEQ(clientID),
+		).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrConsentNotFound
+	}
+	return nil
+}
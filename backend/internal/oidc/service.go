@@ -0,0 +1,349 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/refreshtoken"
+	"app/internal/user"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	ScopeOpenID        = "openid"
+	ScopeProfile       = "profile"
+	ScopeEmail         = "email"
+	ScopeOfflineAccess = "offline_access"
+)
+
+// SupportedScopes are the scopes clients can be registered for.
+var SupportedScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopeOfflineAccess}
+
+// CodeChallengeS256 is the only PKCE method accepted. PKCE is required of
+// every client, confidential ones included.
+const CodeChallengeS256 = "S256"
+
+// codeChallengePattern matches a base64url-encoded SHA-256 digest.
+var codeChallengePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)
+
+var (
+	// ErrUnknownClient and ErrInvalidRedirectURI are shown to the user
+	// instead of redirecting, as the redirect URI cannot be trusted.
+	ErrUnknownClient      = errors.New("unknown client_id")
+	ErrInvalidRedirectURI = errors.New("redirect_uri is not registered for the client")
+)
+
+// Error is an OAuth 2.0 error, sent to the client in the error and
+// error_description parameters.
+type Error struct {
+	Code        string
+	Description string
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(code string, description string) *Error {
+	return &Error{Code: code, Description: description}
+}
+
+// Config controls the OpenID Connect provider.
+type Config struct {
+	// LoginURL is the frontend page an authorization request is forwarded
+	// to. It logs the user in, asks for consent and completes the request
+	// at /oauth/authorize.
+	LoginURL string
+	// CodeTTL is how long an authorization code can be exchanged.
+	CodeTTL time.Duration
+	// IDTokenTTL is the lifetime of ID tokens.
+	IDTokenTTL time.Duration
+}
+
+// Service is an OpenID Connect provider supporting the authorization code
+// flow with PKCE. The provider has no pages of its own: the authorization
+// endpoint forwards the request to the frontend, which logs the user in with
+// the regular API and completes the request on their behalf. Access tokens
+// are the API's own, limited to the client and scope they were issued for;
+// sessions are regular refresh token families bound to the client.
+type Service struct {
+	Repo     Repository
+	Users    *user.Service
+	JWT      *auth.JWT
+	Sessions *refreshtoken.Service
+	Config   Config
+}
+
+func NewService(repo Repository, users *user.Service, jwt *auth.JWT, sessions *refreshtoken.Service, cfg Config) *Service {
+	return &Service{
+		Repo:     repo,
+		Users:    users,
+		JWT:      jwt,
+		Sessions: sessions,
+		Config:   cfg,
+	}
+}
+
+// AuthorizationRequest holds the parameters of an authorization request.
+type AuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Prompt              string
+}
+
+// Decision is the user's answer to the consent prompt.
+type Decision string
+
+const (
+	DecisionNone  Decision = ""
+	DecisionAllow Decision = "allow"
+	DecisionDeny  Decision = "deny"
+)
+
+// Authorization is the outcome of Authorize: either the URI to send the
+// user back to the client with, or the consent prompt to show.
+type Authorization struct {
+	RedirectTo      string
+	ConsentRequired bool
+	Client          *ent.OAuthClient
+	Scopes          []string
+}
+
+// Validate checks an authorization request and returns its client and
+// scopes. ErrUnknownClient and ErrInvalidRedirectURI are returned as is; any
+// other problem is an *Error to be sent back to the redirect URI.
+func (s *Service) Validate(ctx context.Context, req AuthorizationRequest) (*ent.OAuthClient, []string, error) {
+	client, err := s.Repo.GetClient(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return nil, nil, ErrUnknownClient
+		}
+		return nil, nil, err
+	}
+	if !slices.Contains(client.RedirectUris, req.RedirectURI) {
+		return nil, nil, ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return client, nil, oauthError("unsupported_response_type", "only the code response type is supported")
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if !slices.Contains(scopes, ScopeOpenID) {
+		return client, nil, oauthError("invalid_scope", "the openid scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			return client, nil, oauthError("invalid_scope", fmt.Sprintf("scope %q is not allowed for the client", scope))
+		}
+	}
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	if req.CodeChallenge == "" {
+		return client, nil, oauthError("invalid_request", "code_challenge is required")
+	}
+	if req.CodeChallengeMethod != CodeChallengeS256 {
+		return client, nil, oauthError("invalid_request", "code_challenge_method must be S256")
+	}
+	if !codeChallengePattern.MatchString(req.CodeChallenge) {
+		return client, nil, oauthError("invalid_request", "code_challenge is not a base64url-encoded SHA-256 digest")
+	}
+
+	return client, scopes, nil
+}
+
+// LoginRedirect returns the URI of the frontend page that completes a
+// validated authorization request. The request parameters are passed on.
+func (s *Service) LoginRedirect(req AuthorizationRequest) (string, error) {
+	u, err := url.Parse(s.Config.LoginURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid oidc login url: %w", err)
+	}
+
+	q := u.Query()
+	for k, v := range req.params() {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// ErrorRedirect returns the redirect URI of req carrying err.
+func (s *Service) ErrorRedirect(req AuthorizationRequest, err *Error) string {
+	params := url.Values{"error": {err.Code}, "error_description": {err.Description}}
+	return s.redirect(req, params)
+}
+
+// Authorize completes an authorization request for the logged in user. If
+// the user has not yet agreed to share the requested scopes with the client,
+// the consent prompt is returned unless decision answers it. First-party
+// clients skip consent. prompt=consent asks again even if consent was given;
+// prompt=none fails instead of asking.
+func (s *Service) Authorize(ctx context.Context, userID int, req AuthorizationRequest, decision Decision) (*Authorization, error) {
+	client, scopes, err := s.Validate(ctx, req)
+	if err != nil {
+		var oerr *Error
+		if errors.As(err, &oerr) {
+			return &Authorization{RedirectTo: s.ErrorRedirect(req, oerr)}, nil
+		}
+		return nil, err
+	}
+
+	prompts := strings.Fields(req.Prompt)
+	switch {
+	case decision == DecisionDeny:
+		return &Authorization{RedirectTo: s.ErrorRedirect(req, oauthError("access_denied", "the user denied the request"))}, nil
+	case decision == DecisionAllow:
+		if err := s.Repo.SaveConsent(ctx, userID, client.ID, s.consentedScopes(ctx, userID, client, scopes)); err != nil {
+			return nil, err
+		}
+	case client.SkipConsent:
+	case slices.Contains(prompts, "none"):
+		ok, err := s.hasConsent(ctx, userID, client, scopes)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return &Authorization{RedirectTo: s.ErrorRedirect(req, oauthError("consent_required", "the user has not consented to the requested scopes"))}, nil
+		}
+	default:
+		ok, err := s.hasConsent(ctx, userID, client, scopes)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || slices.Contains(prompts, "consent") {
+			return &Authorization{ConsentRequired: true, Client: client, Scopes: scopes}, nil
+		}
+	}
+
+	code, err := s.issueCode(ctx, userID, client, req, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &Authorization{RedirectTo: s.redirect(req, url.Values{"code": {code}})}, nil
+}
+
+// Consents returns the clients a user has given consent to.
+func (s *Service) Consents(ctx context.Context, userID int) ([]*ent.OAuthConsent, error) {
+	return s.Repo.ListConsents(ctx, userID)
+}
+
+// RevokeConsent withdraws the consent a user gave a client and ends the
+// client's sessions of that user.
+func (s *Service) RevokeConsent(ctx context.Context, userID int, clientID string) error {
+	client, err := s.Repo.GetClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return ErrConsentNotFound
+		}
+		return err
+	}
+
+	if err := s.Repo.DeleteConsent(ctx, userID, client.ID); err != nil {
+		return err
+	}
+	return s.Sessions.RevokeClient(ctx, userID, client.ClientID)
+}
+
+func (s *Service) issueCode(ctx context.Context, userID int, client *ent.OAuthClient, req AuthorizationRequest, scopes []string) (string, error) {
+	now := time.Now()
+	if err := s.Repo.DeleteExpiredCodes(ctx, now); err != nil {
+		log.Error("Failed to delete expired authorization codes ", err.Error())
+	}
+
+	code, err := refreshtoken.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.Repo.CreateCode(ctx, refreshtoken.Hash(code), client.ID, userID, req, scopes, now.Add(s.Config.CodeTTL)); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// hasConsent reports whether the user has agreed to share scopes with the
+// client.
+func (s *Service) hasConsent(ctx context.Context, userID int, client *ent.OAuthClient, scopes []string) (bool, error) {
+	consent, err := s.Repo.GetConsent(ctx, userID, client.ID)
+	if err != nil {
+		if errors.Is(err, ErrConsentNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// consentedScopes adds scopes to those the user already agreed to.
+func (s *Service) consentedScopes(ctx context.Context, userID int, client *ent.OAuthClient, scopes []string) []string {
+	merged := slices.Clone(scopes)
+	if consent, err := s.Repo.GetConsent(ctx, userID, client.ID); err == nil {
+		merged = append(merged, consent.Scopes...)
+	}
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+// redirect returns the redirect URI of req with params, the state and the
+// issuer added to its query.
+func (s *Service) redirect(req AuthorizationRequest, params url.Values) string {
+	u, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		// Registered redirect URIs are validated, so this cannot happen.
+		return req.RedirectURI
+	}
+
+	q := u.Query()
+	for k, v := range params {
+		q[k] = v
+	}
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	q.Set("iss", s.JWT.Issuer())
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (req AuthorizationRequest) params() map[string]string {
+	params := map[string]string{
+		"client_id":             req.ClientID,
+		"redirect_uri":          req.RedirectURI,
+		"response_type":         req.ResponseType,
+		"scope":                 req.Scope,
+		"state":                 req.State,
+		"nonce":                 req.Nonce,
+		"code_challenge":        req.CodeChallenge,
+		"code_challenge_method": req.CodeChallengeMethod,
+		"prompt":                req.Prompt,
+	}
+	for k, v := range params {
+		if v == "" {
+			delete(params, k)
+		}
+	}
+	return params
+}
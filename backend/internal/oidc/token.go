@@ -0,0 +1,261 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/refreshtoken"
+	"app/internal/user"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantRefreshToken      = "refresh_token"
+	GrantClientCredentials = "client_credentials"
+)
+
+// ErrInvalidClient is returned when client authentication fails.
+var ErrInvalidClient = oauthError("invalid_client", "client authentication failed")
+
+// TokenRequest holds the parameters of a token request.
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+}
+
+// Tokens is a successful token response.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+	ExpiresIn    time.Duration
+	Scope        string
+}
+
+// AuthenticateClient returns the client clientID if secret is its secret.
+// Public clients must send no secret.
+func (s *Service) AuthenticateClient(ctx context.Context, clientID string, secret string) (*ent.OAuthClient, error) {
+	if clientID == "" {
+		return nil, ErrInvalidClient
+	}
+
+	client, err := s.Repo.GetClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if IsPublic(client) {
+		if secret != "" {
+			return nil, ErrInvalidClient
+		}
+		return client, nil
+	}
+	if !checkSecret(client, secret) {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// Exchange handles a token request of an authenticated client. device is
+// the user agent the tokens are issued to, recorded with the session.
+func (s *Service) Exchange(ctx context.Context, client *ent.OAuthClient, req TokenRequest, device refreshtoken.Client) (*Tokens, error) {
+	switch req.GrantType {
+	case GrantAuthorizationCode:
+		return s.exchangeCode(ctx, client, req, device)
+	case GrantRefreshToken:
+		return s.refresh(ctx, client, req, device)
+	case "":
+		return nil, oauthError("invalid_request", "grant_type is required")
+	default:
+		return nil, oauthError("unsupported_grant_type", "grant_type must be authorization_code or refresh_token")
+	}
+}
+
+// exchangeCode redeems an authorization code. A code is accepted once; if it
+// is presented again, the session started with it is ended, as the code has
+// leaked.
+func (s *Service) exchangeCode(ctx context.Context, client *ent.OAuthClient, req TokenRequest, device refreshtoken.Client) (*Tokens, error) {
+	if req.Code == "" {
+		return nil, oauthError("invalid_request", "code is required")
+	}
+
+	codeHash := refreshtoken.Hash(req.Code)
+	code, err := s.Repo.RedeemCode(ctx, codeHash)
+	if err != nil {
+		if errors.Is(err, ErrCodeNotFound) {
+			s.revokeReplayedCode(ctx, codeHash)
+			return nil, oauthError("invalid_grant", "the authorization code is invalid, expired or already used")
+		}
+		return nil, err
+	}
+
+	if code.OauthClientID != client.ID {
+		return nil, oauthError("invalid_grant", "the authorization code was issued to another client")
+	}
+	if req.RedirectURI != code.RedirectURI {
+		return nil, oauthError("invalid_grant", "redirect_uri does not match the authorization request")
+	}
+	if !verifyCodeChallenge(code.CodeChallenge, req.CodeVerifier) {
+		return nil, oauthError("invalid_grant", "code_verifier does not match the code_challenge")
+	}
+
+	u, err := s.Users.GetByID(ctx, code.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, oauthError("invalid_grant", "the user no longer exists")
+		}
+		return nil, err
+	}
+
+	scope := strings.Join(code.Scopes, " ")
+	var sessionID, refreshToken string
+	if slices.Contains(code.Scopes, ScopeOfflineAccess) {
+		issued, err := s.Sessions.GenerateForClient(ctx, u.ID, device, refreshtoken.Grant{ClientID: client.ClientID, Scope: scope})
+		if err != nil {
+			return nil, err
+		}
+		sessionID, refreshToken = issued.SessionID, issued.Token
+
+		if err := s.Repo.SetCodeSession(ctx, code.ID, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.issue(u, client, scope, sessionID, refreshToken, code.Nonce)
+}
+
+// refresh rotates a refresh token issued to the client.
+func (s *Service) refresh(ctx context.Context, client *ent.OAuthClient, req TokenRequest, device refreshtoken.Client) (*Tokens, error) {
+	if req.RefreshToken == "" {
+		return nil, oauthError("invalid_request", "refresh_token is required")
+	}
+
+	issued, err := s.Sessions.RotateForClient(ctx, req.RefreshToken, device, client.ClientID)
+	if err != nil {
+		if isInvalidRefreshToken(err) {
+			return nil, oauthError("invalid_grant", "the refresh token is invalid or expired")
+		}
+		return nil, err
+	}
+
+	u, err := s.Users.GetByID(ctx, issued.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, oauthError("invalid_grant", "the user no longer exists")
+		}
+		return nil, err
+	}
+
+	return s.issue(u, client, issued.Grant.Scope, issued.SessionID, issued.Token, "")
+}
+
+// issue creates the access token and, for the openid scope, the ID token.
+// The access token names the user, the client and the scope it was issued
+// for. It carries none of the user's roles, as the client may only do what
+// the scope allows, and first-party routes reject it.
+func (s *Service) issue(u *ent.User, client *ent.OAuthClient, scope string, sessionID string, refreshToken string, nonce string) (*Tokens, error) {
+	accessToken, err := s.JWT.Generate(auth.Identity{
+		SubjectType: auth.SubjectUser,
+		UserID:      u.ID,
+		SessionID:   sessionID,
+		ClientID:    client.ClientID,
+		Scope:       scope,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := &Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.JWT.TTL(),
+		Scope:        scope,
+	}
+
+	scopes := strings.Fields(scope)
+	if slices.Contains(scopes, ScopeOpenID) {
+		claims := idTokenClaims(u, scopes)
+		claims.Nonce = nonce
+		if tokens.IDToken, err = s.JWT.GenerateIDToken(claims, client.ClientID, accessToken, s.Config.IDTokenTTL); err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+// UserInfo returns the claims about the user the access token grants access
+// to. The token must have been issued for the openid scope.
+func (s *Service) UserInfo(ctx context.Context, claims *auth.Claims) (*auth.IDTokenClaims, error) {
+	scopes := strings.Fields(claims.Scope)
+	if !slices.Contains(scopes, ScopeOpenID) {
+		return nil, oauthError("insufficient_scope", "the access token was not issued for the openid scope")
+	}
+
+	u, err := s.Users.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, oauthError("invalid_token", "the user no longer exists")
+		}
+		return nil, err
+	}
+
+	info := idTokenClaims(u, scopes)
+	return &info, nil
+}
+
+// revokeReplayedCode ends the session started with an already used code.
+func (s *Service) revokeReplayedCode(ctx context.Context, codeHash string) {
+	code, err := s.Repo.GetCode(ctx, codeHash)
+	if err != nil || code.UsedAt == nil || code.SessionID == "" {
+		return
+	}
+
+	log.Warn("Authorization code replayed, revoking session of user ", code.UserID)
+	err = s.Sessions.RevokeSession(ctx, code.UserID, code.SessionID)
+	if err != nil && !errors.Is(err, refreshtoken.ErrSessionNotFound) {
+		log.Error("Failed to revoke session of replayed authorization code ", err.Error())
+	}
+}
+
+// idTokenClaims returns the claims about u released for scopes.
+func idTokenClaims(u *ent.User, scopes []string) auth.IDTokenClaims {
+	var claims auth.IDTokenClaims
+	claims.Subject = strconv.Itoa(u.ID)
+	if slices.Contains(scopes, ScopeEmail) {
+		verified := u.EmailVerified
+		claims.Email = u.Email
+		claims.EmailVerified = &verified
+	}
+	if slices.Contains(scopes, ScopeProfile) {
+		claims.PreferredUsername = u.Username
+	}
+	return claims
+}
+
+// verifyCodeChallenge checks verifier against an S256 code challenge.
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
@@ -0,0 +1,230 @@
+package passkey
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/domain"
+	"app/ent"
+	"app/internal/middleware"
+	"app/internal/response"
+	"app/internal/user"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SessionStarter issues the tokens of a new login and writes them, the same
+// way password login does.
+type SessionStarter interface {
+	StartSession(w http.ResponseWriter, r *http.Request, u *ent.User)
+}
+
+type Handler struct {
+	Service  *Service
+	Sessions SessionStarter
+}
+
+func NewHandler(s *Service, sessions SessionStarter) *Handler {
+	return &Handler{Service: s, Sessions: sessions}
+}
+
+// BeginRegistration godoc
+// @Summary      Begin passkey registration
+// @Description  Start adding a passkey to the account. Pass the publicKey member of the response to navigator.credentials.create() and send the result to /users/me/passkeys/register/finish.
+// @Tags         passkeys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} object "WebAuthn credential creation options"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/passkeys/register/begin [post]
+func (h *Handler) BeginRegistration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	creation, err := h.Service.BeginRegistration(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, creation)
+}
+
+// FinishRegistration godoc
+// @Summary      Finish passkey registration
+// @Description  Verify the credential created by the authenticator and add it to the account
+// @Tags         passkeys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.PasskeyRegisterDTO true "Name and credential"
+// @Success      201 {object} domain.PasskeyResponse "Passkey registered"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body, invalid credential or expired challenge"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      409 {object} domain.ErrorResponse "Passkey already registered"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/passkeys/register/finish [post]
+func (h *Handler) FinishRegistration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dto domain.PasskeyRegisterDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(dto.Credential) == 0 {
+		response.Error(w, http.StatusBadRequest, "credential is required")
+		return
+	}
+
+	c, err := h.Service.FinishRegistration(r.Context(), userID, dto.Name, dto.Credential)
+	if err != nil {
+		respondWithError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, ToPasskeyResponse(c))
+}
+
+// List godoc
+// @Summary      List passkeys
+// @Description  List the passkeys of the authenticated user, oldest first
+// @Tags         passkeys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.PasskeyResponse "Registered passkeys"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/passkeys [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	creds, err := h.Service.List(r.Context(), userID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToPasskeyResponses(creds))
+}
+
+// Delete godoc
+// @Summary      Delete passkey
+// @Description  Remove a passkey from the account. It can no longer be used to log in.
+// @Tags         passkeys
+// @Security     BearerAuth
+// @Param        id path int true "Passkey ID"
+// @Success      204 "Passkey deleted"
+// @Failure      400 {object} domain.ErrorResponse "Invalid passkey id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "Passkey not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/passkeys/{id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid passkey id")
+		return
+	}
+
+	if err := h.Service.Delete(r.Context(), userID, id); err != nil {
+		respondWithError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BeginLogin godoc
+// @Summary      Begin passkey login
+// @Description  Start a passwordless login. Pass the publicKey member of the response to navigator.credentials.get() and send the result to /auth/passkeys/login/finish.
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} object "WebAuthn credential request options"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/passkeys/login/begin [post]
+func (h *Handler) BeginLogin(w http.ResponseWriter, r *http.Request) {
+	assertion, err := h.Service.BeginLogin(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, assertion)
+}
+
+// FinishLogin godoc
+// @Summary      Finish passkey login
+// @Description  Verify the assertion signed by the authenticator and log in the user the passkey belongs to. Passkeys verify the user on the authenticator, so no TOTP code is asked for.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.PasskeyLoginDTO true "Assertion"
+// @Success      200 {object} domain.AuthResponse "Successfully logged in"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body or expired challenge"
+// @Failure      401 {object} domain.ErrorResponse "Invalid passkey"
+// @Failure      403 {object} domain.ErrorResponse "Email not verified"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/passkeys/login/finish [post]
+func (h *Handler) FinishLogin(w http.ResponseWriter, r *http.Request) {
+	var dto domain.PasskeyLoginDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if len(dto.Credential) == 0 {
+		response.Error(w, http.StatusBadRequest, "credential is required")
+		return
+	}
+
+	u, err := h.Service.FinishLogin(r.Context(), dto.Credential)
+	if err != nil {
+		respondWithError(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	h.Sessions.StartSession(w, r, u)
+}
+
+// respondWithError maps service errors to responses. A credential failing
+// verification is answered with invalidStatus.
+func respondWithError(w http.ResponseWriter, err error, invalidStatus int) {
+	switch {
+	case errors.Is(err, ErrInvalidCredential), errors.Is(err, ErrPossibleClone):
+		response.Error(w, invalidStatus, "invalid passkey")
+	case errors.Is(err, ErrInvalidSession):
+		response.Error(w, http.StatusBadRequest, "invalid or expired passkey challenge")
+	case errors.Is(err, ErrCredentialExists):
+		response.Error(w, http.StatusConflict, "passkey is already registered")
+	case errors.Is(err, ErrCredentialNotFound):
+		response.Error(w, http.StatusNotFound, "passkey not found")
+	case errors.Is(err, user.ErrEmailNotVerified):
+		response.Error(w, http.StatusForbidden, "email is not verified")
+	case errors.Is(err, user.ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "user not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
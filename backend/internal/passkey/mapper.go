@@ -0,0 +1,91 @@
+package passkey
+
+import (
+	"bytes"
+	"strconv"
+
+	"app/domain"
+	"app/ent"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func ToPasskeyResponse(c *ent.WebAuthnCredential) domain.PasskeyResponse {
+	return domain.PasskeyResponse{
+		ID:         c.ID,
+		Name:       c.Name,
+		BackedUp:   protocol.AuthenticatorFlags(c.Flags).HasBackupState(),
+		CreatedAt:  c.CreatedAt,
+		LastUsedAt: c.LastUsedAt,
+	}
+}
+
+func ToPasskeyResponses(creds []*ent.WebAuthnCredential) []domain.PasskeyResponse {
+	resp := make([]domain.PasskeyResponse, len(creds))
+	for i, c := range creds {
+		resp[i] = ToPasskeyResponse(c)
+	}
+	return resp
+}
+
+// account presents a user and their passkeys to the WebAuthn library. The
+// user handle stored on authenticators is the decimal user ID, which
+// identifies the user at login without revealing anything personal.
+type account struct {
+	user        *ent.User
+	records     []*ent.WebAuthnCredential
+	credentials []webauthn.Credential
+}
+
+func (a *account) WebAuthnID() []byte {
+	return []byte(strconv.Itoa(a.user.ID))
+}
+
+func (a *account) WebAuthnName() string {
+	return a.user.Email
+}
+
+func (a *account) WebAuthnDisplayName() string {
+	if a.user.Username != "" {
+		return a.user.Username
+	}
+	return a.user.Email
+}
+
+func (a *account) WebAuthnCredentials() []webauthn.Credential {
+	return a.credentials
+}
+
+// stored returns the record of the credential with the given ID.
+func (a *account) stored(credentialID []byte) *ent.WebAuthnCredential {
+	for _, c := range a.records {
+		if bytes.Equal(c.CredentialID, credentialID) {
+			return c
+		}
+	}
+	return nil
+}
+
+// toCredential converts a stored credential into the form the WebAuthn
+// library verifies assertions against.
+func toCredential(c *ent.WebAuthnCredential) webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, len(c.Transports))
+	for i, t := range c.Transports {
+		transports[i] = protocol.AuthenticatorTransport(t)
+	}
+
+	return webauthn.Credential{
+		ID:                c.CredentialID,
+		PublicKey:         c.PublicKey,
+		AttestationType:   c.AttestationType,
+		AttestationFormat: c.AttestationFormat,
+		Transport:         transports,
+		Flags:             webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(c.Flags)),
+		Authenticator: webauthn.Authenticator{
+			AAGUID:     c.Aaguid,
+			SignCount:  c.SignCount,
+			Attachment: protocol.AuthenticatorAttachment(c.Attachment),
+		},
+	}
+}
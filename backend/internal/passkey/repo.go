@@ -0,0 +1,159 @@
+package passkey
+
+import (
+	"app/ent"
+	"app/ent/webauthncredential"
+	"app/ent/webauthnsession"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("webauthn session not found")
+	ErrCredentialNotFound = errors.New("passkey not found")
+	ErrCredentialExists   = errors.New("passkey is already registered")
+)
+
+// Repository defines the interface for passkey and ceremony data access.
+type Repository interface {
+	CreateSession(ctx context.Context, ceremony webauthnsession.Ceremony, userID *int, challenge string, data []byte, expiresAt time.Time) error
+	TakeSession(ctx context.Context, ceremony webauthnsession.Ceremony, challenge string) (*ent.WebAuthnSession, error)
+	DeleteExpiredSessions(ctx context.Context, before time.Time) error
+	CreateCredential(ctx context.Context, userID int, name string, c *webauthn.Credential) (*ent.WebAuthnCredential, error)
+	ListCredentials(ctx context.Context, userID int) ([]*ent.WebAuthnCredential, error)
+	RecordUse(ctx context.Context, id int, prevSignCount uint32, c *webauthn.Credential) (bool, error)
+	DeleteCredential(ctx context.Context, userID int, id int) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// CreateSession stores the session data of a ceremony under its challenge.
+func (r *PostgresRepo) CreateSession(ctx context.Context, ceremony webauthnsession.Ceremony, userID *int, challenge string, data []byte, expiresAt time.Time) error {
+	return r.Db.Client.WebAuthnSession.Create().
+		SetCeremony(ceremony).
+		SetNillableUserID(userID).
+		SetChallenge(challenge).
+		SetData(data).
+		SetExpiresAt(expiresAt).
+		Exec(ctx)
+}
+
+// TakeSession returns the unexpired session of a ceremony and deletes it.
+// Only the caller whose delete succeeds gets the session, so a challenge
+// can be answered once.
+func (r *PostgresRepo) TakeSession(ctx context.Context, ceremony webauthnsession.Ceremony, challenge string) (*ent.WebAuthnSession, error) {
+	s, err := r.Db.Client.WebAuthnSession.Query().
+		Where(
+			webauthnsession.ChallengeEQ(challenge),
+			webauthnsession.CeremonyEQ(ceremony),
+			webauthnsession.ExpiresAtGT(time.Now()),
+		).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	n, err := r.Db.Client.WebAuthnSession.Delete().
+		Where(webauthnsession.IDEQ(s.ID)).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrSessionNotFound
+	}
+	return s, nil
+}
+
+// DeleteExpiredSessions removes sessions of abandoned ceremonies.
+func (r *PostgresRepo) DeleteExpiredSessions(ctx context.Context, before time.Time) error {
+	_, err := r.Db.Client.WebAuthnSession.Delete().
+		Where(webauthnsession.ExpiresAtLT(before)).
+		Exec(ctx)
+	return err
+}
+
+// CreateCredential stores a newly registered credential of a user.
+func (r *PostgresRepo) CreateCredential(ctx context.Context, userID int, name string, c *webauthn.Credential) (*ent.WebAuthnCredential, error) {
+	transports := make([]string, len(c.Transport))
+	for i, t := range c.Transport {
+		transports[i] = string(t)
+	}
+
+	cred, err := r.Db.Client.WebAuthnCredential.Create().
+		SetUserID(userID).
+		SetName(name).
+		SetCredentialID(c.ID).
+		SetPublicKey(c.PublicKey).
+		SetAttestationType(c.AttestationType).
+		SetAttestationFormat(c.AttestationFormat).
+		SetAaguid(c.Authenticator.AAGUID).
+		SetSignCount(c.Authenticator.SignCount).
+		SetTransports(transports).
+		SetAttachment(string(c.Authenticator.Attachment)).
+		SetFlags(uint8(c.Flags.ProtocolValue())).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrCredentialExists
+	}
+	return cred, err
+}
+
+// ListCredentials returns the credentials of a user, oldest first.
+func (r *PostgresRepo) ListCredentials(ctx context.Context, userID int) ([]*ent.WebAuthnCredential, error) {
+	return r.Db.Client.WebAuthnCredential.Query().
+		Where(webauthncredential.UserIDEQ(userID)).
+		Order(ent.Asc(webauthncredential.FieldCreatedAt)).
+		All(ctx)
+}
+
+// RecordUse stores the counter and flags of a successful assertion. The
+// update only applies while the stored counter is still prevSignCount, so
+// of two assertions with the same counter only one succeeds.
+func (r *PostgresRepo) RecordUse(ctx context.Context, id int, prevSignCount uint32, c *webauthn.Credential) (bool, error) {
+	n, err := r.Db.Client.WebAuthnCredential.Update().
+		Where(
+			webauthncredential.IDEQ(id),
+			webauthncredential.SignCountEQ(prevSignCount),
+		).
+		SetSignCount(c.Authenticator.SignCount).
+		SetFlags(uint8(c.Flags.ProtocolValue())).
+		SetLastUsedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// DeleteCredential removes a credential of a user.
+func (r *PostgresRepo) DeleteCredential(ctx context.Context, userID int, id int) error {
+	n, err := r.Db.Client.WebAuthnCredential.Delete().
+		Where(
+			webauthncredential.IDEQ(id),
+			webauthncredential.UserIDEQ(userID),
+		).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCredentialNotFound
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package passkey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/ent/webauthnsession"
+	"app/internal/user"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/labstack/gommon/log"
+)
+
+// MaxNameLength limits the label a user gives a passkey.
+const MaxNameLength = 64
+
+var (
+	ErrInvalidSession    = errors.New("invalid or expired passkey challenge")
+	ErrInvalidCredential = errors.New("invalid passkey response")
+	ErrPossibleClone     = errors.New("passkey signature counter did not increase")
+)
+
+// Config describes the relying party passkeys are registered with.
+type Config struct {
+	// RPID is the domain passkeys are scoped to, e.g. example.com.
+	RPID string
+	// RPDisplayName is shown by authenticators during registration.
+	RPDisplayName string
+	// RPOrigins are the origins of the frontends allowed to use passkeys.
+	RPOrigins []string
+	// Timeout is how long a ceremony may take between begin and finish.
+	Timeout time.Duration
+}
+
+// Service is the WebAuthn relying party. Registration and login are two-step
+// ceremonies: begin stores a random challenge with its session data and
+// returns the options for navigator.credentials, finish looks the session up
+// by the challenge the authenticator signed and verifies the response.
+// Sessions are single-use. Logins use discoverable credentials, so the user
+// is identified by the passkey and no email is needed. Passkeys require user
+// verification on the authenticator and therefore skip the TOTP step.
+type Service struct {
+	Repo     Repository
+	Users    *user.Service
+	WebAuthn *webauthn.WebAuthn
+	Config   Config
+}
+
+func NewService(repo Repository, users *user.Service, cfg Config) (*Service, error) {
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.Timeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.Timeout},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn config: %w", err)
+	}
+
+	return &Service{
+		Repo:     repo,
+		Users:    users,
+		WebAuthn: w,
+		Config:   cfg,
+	}, nil
+}
+
+// BeginRegistration starts adding a passkey to a user's account. Passkeys
+// the user already has are excluded, so an authenticator is not registered
+// twice.
+func (s *Service) BeginRegistration(ctx context.Context, userID int) (*protocol.CredentialCreation, error) {
+	a, err := s.account(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := s.WebAuthn.BeginRegistration(a,
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		}),
+		webauthn.WithExclusions(webauthn.Credentials(a.credentials).CredentialDescriptors()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveSession(ctx, webauthnsession.CeremonyRegistration, &userID, session); err != nil {
+		return nil, err
+	}
+	return creation, nil
+}
+
+// FinishRegistration verifies the authenticator's response to
+// BeginRegistration and stores the new passkey under name.
+func (s *Service) FinishRegistration(ctx context.Context, userID int, name string, response []byte) (*ent.WebAuthnCredential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, invalid(err)
+	}
+
+	session, err := s.takeSession(ctx, webauthnsession.CeremonyRegistration, parsed.Response.CollectedClientData.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	if session.userID == nil || *session.userID != userID {
+		return nil, ErrInvalidSession
+	}
+
+	a, err := s.account(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.WebAuthn.CreateCredential(a, session.data, parsed)
+	if err != nil {
+		return nil, invalid(err)
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey " + strconv.Itoa(len(a.credentials)+1)
+	}
+	if r := []rune(name); len(r) > MaxNameLength {
+		name = string(r[:MaxNameLength])
+	}
+
+	return s.Repo.CreateCredential(ctx, userID, name, c)
+}
+
+// BeginLogin starts a passkey login. The options name no user or
+// credentials; the authenticator offers the passkeys it has for the site.
+func (s *Service) BeginLogin(ctx context.Context) (*protocol.CredentialAssertion, error) {
+	assertion, session, err := s.WebAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveSession(ctx, webauthnsession.CeremonyLogin, nil, session); err != nil {
+		return nil, err
+	}
+	return assertion, nil
+}
+
+// FinishLogin verifies the authenticator's response to BeginLogin and
+// returns the user the passkey belongs to. An assertion whose signature
+// counter did not grow is rejected, as the passkey may have been cloned.
+func (s *Service) FinishLogin(ctx context.Context, response []byte) (*ent.User, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, invalid(err)
+	}
+
+	session, err := s.takeSession(ctx, webauthnsession.CeremonyLogin, parsed.Response.CollectedClientData.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	var a *account
+	discover := func(rawID, userHandle []byte) (webauthn.User, error) {
+		id, err := strconv.Atoi(string(userHandle))
+		if err != nil {
+			return nil, ErrInvalidCredential
+		}
+		if a, err = s.account(ctx, id); err != nil {
+			return nil, err
+		}
+		return a, nil
+	}
+
+	c, err := s.WebAuthn.ValidateDiscoverableLogin(discover, session.data, parsed)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, ErrInvalidCredential
+		}
+		return nil, invalid(err)
+	}
+
+	stored := a.stored(c.ID)
+	if stored == nil {
+		return nil, ErrInvalidCredential
+	}
+	if c.Authenticator.CloneWarning {
+		log.Warnf("passkey signature counter did not increase: user=%d credential=%d", a.user.ID, stored.ID)
+		return nil, ErrPossibleClone
+	}
+	ok, err := s.Repo.RecordUse(ctx, stored.ID, stored.SignCount, c)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Another login with the same counter was recorded first.
+		log.Warnf("passkey signature counter reused concurrently: user=%d credential=%d", a.user.ID, stored.ID)
+		return nil, ErrPossibleClone
+	}
+
+	if s.Users.Config.LoginThrottle != nil {
+		if err := s.Users.Config.LoginThrottle.Succeeded(ctx, a.user.Email); err != nil {
+			log.Error("Failed to reset login failures ", err.Error())
+		}
+	}
+
+	if s.Users.Config.RequireVerifiedEmail && !a.user.EmailVerified {
+		return nil, user.ErrEmailNotVerified
+	}
+
+	return a.user, nil
+}
+
+// List returns the passkeys of a user.
+func (s *Service) List(ctx context.Context, userID int) ([]*ent.WebAuthnCredential, error) {
+	return s.Repo.ListCredentials(ctx, userID)
+}
+
+// Delete removes a passkey of a user.
+func (s *Service) Delete(ctx context.Context, userID int, id int) error {
+	return s.Repo.DeleteCredential(ctx, userID, id)
+}
+
+type session struct {
+	userID *int
+	data   webauthn.SessionData
+}
+
+func (s *Service) saveSession(ctx context.Context, ceremony webauthnsession.Ceremony, userID *int, data *webauthn.SessionData) error {
+	now := time.Now()
+	if err := s.Repo.DeleteExpiredSessions(ctx, now); err != nil {
+		log.Error("Failed to delete expired webauthn sessions ", err.Error())
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.Repo.CreateSession(ctx, ceremony, userID, data.Challenge, encoded, now.Add(s.Config.Timeout))
+}
+
+func (s *Service) takeSession(ctx context.Context, ceremony webauthnsession.Ceremony, challenge string) (*session, error) {
+	stored, err := s.Repo.TakeSession(ctx, ceremony, challenge)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal(stored.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode webauthn session: %w", err)
+	}
+	return &session{userID: stored.UserID, data: data}, nil
+}
+
+func (s *Service) account(ctx context.Context, userID int) (*account, error) {
+	u, err := s.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.Repo.ListCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &account{user: u, records: stored}
+	for _, c := range stored {
+		a.credentials = append(a.credentials, toCredential(c))
+	}
+	return a, nil
+}
+
+// invalid wraps a verification error of the WebAuthn library, logging the
+// details that are not returned to the client.
+func invalid(err error) error {
+	var perr *protocol.Error
+	if errors.As(err, &perr) {
+		log.Debugf("passkey verification failed: %s: %s", perr.Details, perr.DevInfo)
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+}
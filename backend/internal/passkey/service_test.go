@@ -0,0 +1,342 @@
+package passkey
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"app/ent"
+	"app/internal/testutil"
+	"app/internal/user"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+)
+
+const (
+	testRPID   = "localhost"
+	testOrigin = "http://localhost:3000"
+)
+
+// authenticator is a software passkey: an ES256 key that answers
+// registration and login ceremonies like a platform authenticator with user
+// verification and "none" attestation.
+type authenticator struct {
+	t       *testing.T
+	id      []byte
+	key     *ecdsa.PrivateKey
+	handle  []byte
+	counter uint32
+}
+
+func newAuthenticator(t *testing.T) *authenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+	return &authenticator{t: t, id: id, key: key}
+}
+
+// register creates the credential for user u in answer to challenge and
+// returns the JSON response of navigator.credentials.create().
+func (a *authenticator) register(u *ent.User, challenge []byte) []byte {
+	a.t.Helper()
+	a.handle = []byte(strconv.Itoa(u.ID))
+
+	x := a.key.PublicKey.X.FillBytes(make([]byte, 32))
+	y := a.key.PublicKey.Y.FillBytes(make([]byte, 32))
+	// COSE key: kty EC2, alg ES256, crv P-256.
+	publicKey, err := webauthncbor.Marshal(map[int]any{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+
+	// Flags: user present, user verified, attested credential data.
+	authData := a.authData(0x45)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+
+	return a.marshal(map[string]any{
+		"clientDataJSON":    b64(a.clientData("webauthn.create", challenge)),
+		"attestationObject": b64(attestation),
+		"transports":        []string{"internal"},
+	})
+}
+
+// login signs challenge with the credential and returns the JSON response of
+// navigator.credentials.get().
+func (a *authenticator) login(challenge []byte) []byte {
+	a.t.Helper()
+
+	// Flags: user present, user verified.
+	authData := a.authData(0x05)
+	clientData := a.clientData("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatal(err)
+	}
+
+	return a.marshal(map[string]any{
+		"clientDataJSON":    b64(clientData),
+		"authenticatorData": b64(authData),
+		"signature":         b64(signature),
+		"userHandle":        b64(a.handle),
+	})
+}
+
+func (a *authenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.counter)
+}
+
+func (a *authenticator) clientData(typ string, challenge []byte) []byte {
+	data, err := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": b64(challenge),
+		"origin":    testOrigin,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return data
+}
+
+func (a *authenticator) marshal(response map[string]any) []byte {
+	data, err := json.Marshal(map[string]any{
+		"id":       b64(a.id),
+		"rawId":    b64(a.id),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return data
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func newTestService(t *testing.T) (*Service, *user.Service) {
+	t.Helper()
+
+	d := testutil.DB(t)
+	users := testutil.Users(d, user.Config{})
+	s, err := NewService(NewPostgresRepo(d), users, Config{
+		RPID:          testRPID,
+		RPDisplayName: "Test",
+		RPOrigins:     []string{testOrigin},
+		Timeout:       time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, users
+}
+
+func newTestUser(t *testing.T, users *user.Service, email string) *ent.User {
+	t.Helper()
+
+	u, err := users.Register(context.Background(), email, "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// registerPasskey runs a registration ceremony for u with a.
+func registerPasskey(t *testing.T, s *Service, u *ent.User, a *authenticator) *ent.WebAuthnCredential {
+	t.Helper()
+
+	creation, err := s.BeginRegistration(context.Background(), u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := s.FinishRegistration(context.Background(), u.ID, "Laptop", a.register(u, creation.Response.Challenge))
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	return c
+}
+
+// loginWithPasskey runs a login ceremony with a.
+func loginWithPasskey(t *testing.T, s *Service, a *authenticator) (*ent.User, error) {
+	t.Helper()
+
+	assertion, err := s.BeginLogin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.FinishLogin(context.Background(), a.login(assertion.Response.Challenge))
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	s, users := newTestService(t)
+	u := newTestUser(t, users, "alice@example.com")
+	a := newAuthenticator(t)
+
+	c := registerPasskey(t, s, u, a)
+	if c.Name != "Laptop" || c.UserID != u.ID {
+		t.Fatalf("stored credential = %+v", c)
+	}
+
+	a.counter = 1
+	got, err := loginWithPasskey(t, s, a)
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if got.ID != u.ID {
+		t.Fatalf("logged in user %d, want %d", got.ID, u.ID)
+	}
+
+	creds, err := s.List(context.Background(), u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(creds) != 1 || creds[0].SignCount != 1 || creds[0].LastUsedAt == nil {
+		t.Fatalf("credential after login = %+v", creds)
+	}
+}
+
+func TestWrongChallenge(t *testing.T) {
+	s, users := newTestService(t)
+	ctx := context.Background()
+	u := newTestUser(t, users, "alice@example.com")
+	a := newAuthenticator(t)
+
+	if _, err := s.BeginRegistration(ctx, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.FinishRegistration(ctx, u.ID, "", a.register(u, []byte("a challenge never issued")))
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("registration with a wrong challenge: got %v, want ErrInvalidSession", err)
+	}
+
+	registerPasskey(t, s, u, a)
+
+	if _, err := s.BeginLogin(ctx); err != nil {
+		t.Fatal(err)
+	}
+	a.counter = 1
+	_, err = s.FinishLogin(ctx, a.login([]byte("a challenge never issued")))
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("login with a wrong challenge: got %v, want ErrInvalidSession", err)
+	}
+
+	// A registration challenge cannot be answered with a login.
+	creation, err := s.BeginRegistration(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.FinishLogin(ctx, a.login(creation.Response.Challenge))
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("login with a registration challenge: got %v, want ErrInvalidSession", err)
+	}
+
+	// Each challenge is answered once.
+	assertion, err := s.BeginLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.counter = 2
+	response := a.login(assertion.Response.Challenge)
+	if _, err := s.FinishLogin(ctx, response); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if _, err := s.FinishLogin(ctx, response); !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("replayed login: got %v, want ErrInvalidSession", err)
+	}
+}
+
+func TestSignCount(t *testing.T) {
+	s, users := newTestService(t)
+	u := newTestUser(t, users, "alice@example.com")
+	a := newAuthenticator(t)
+	registerPasskey(t, s, u, a)
+
+	a.counter = 5
+	if _, err := loginWithPasskey(t, s, a); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+
+	// The same counter again, as from a replayed or cloned authenticator.
+	if _, err := loginWithPasskey(t, s, a); !errors.Is(err, ErrPossibleClone) {
+		t.Fatalf("repeated sign count: got %v, want ErrPossibleClone", err)
+	}
+
+	a.counter = 3
+	if _, err := loginWithPasskey(t, s, a); !errors.Is(err, ErrPossibleClone) {
+		t.Fatalf("backward sign count: got %v, want ErrPossibleClone", err)
+	}
+
+	a.counter = 6
+	if _, err := loginWithPasskey(t, s, a); err != nil {
+		t.Fatalf("FinishLogin after a rejected clone: %v", err)
+	}
+}
+
+func TestCredentialOfAnotherUser(t *testing.T) {
+	s, users := newTestService(t)
+	ctx := context.Background()
+	alice := newTestUser(t, users, "alice@example.com")
+	bob := newTestUser(t, users, "bob@example.com")
+
+	a := newAuthenticator(t)
+	registerPasskey(t, s, alice, a)
+	registerPasskey(t, s, bob, newAuthenticator(t))
+
+	// Alice's passkey claiming to be Bob's.
+	a.handle = []byte(strconv.Itoa(bob.ID))
+	a.counter = 1
+	if _, err := loginWithPasskey(t, s, a); !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("login as another user: got %v, want ErrInvalidCredential", err)
+	}
+
+	// A registration begun by Alice cannot be finished by Bob.
+	creation, err := s.BeginRegistration(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.FinishRegistration(ctx, bob.ID, "", newAuthenticator(t).register(bob, creation.Response.Challenge))
+	if !errors.Is(err, ErrInvalidSession) {
+		t.Fatalf("registration finished by another user: got %v, want ErrInvalidSession", err)
+	}
+
+	// Nor can one user delete another's passkey.
+	creds, err := s.List(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, bob.ID, creds[0].ID); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("deleting another user's passkey: got %v, want ErrCredentialNotFound", err)
+	}
+}
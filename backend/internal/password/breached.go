@@ -0,0 +1,83 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// sha1PrefixLength is the length of the hex prefix buckets are keyed by,
+// matching the k-anonymity range API of Have I Been Pwned.
+const sha1PrefixLength = 5
+
+// BreachedList is an in-memory corpus of breached password SHA-1 hashes,
+// bucketed by hash prefix like the Pwned Passwords range API.
+type BreachedList struct {
+	buckets map[string][]string
+	size    int
+}
+
+// LoadBreachedList reads a corpus file with one uppercase or lowercase
+// SHA-1 hex digest per line, optionally followed by ":<count>" as in the
+// Pwned Passwords downloads. Blank lines and lines starting with # are
+// ignored.
+func LoadBreachedList(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	l := &BreachedList{buckets: make(map[string][]string)}
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		digest, _, _ := strings.Cut(text, ":")
+		digest = strings.ToUpper(digest)
+		if len(digest) != sha1.Size*2 {
+			return nil, fmt.Errorf("breached password list line %d: invalid SHA-1 digest", line)
+		}
+		if _, err := hex.DecodeString(digest); err != nil {
+			return nil, fmt.Errorf("breached password list line %d: invalid SHA-1 digest", line)
+		}
+
+		prefix, suffix := digest[:sha1PrefixLength], digest[sha1PrefixLength:]
+		l.buckets[prefix] = append(l.buckets[prefix], suffix)
+		l.size++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	for _, suffixes := range l.buckets {
+		sort.Strings(suffixes)
+	}
+	return l, nil
+}
+
+// Len returns the number of hashes in the corpus.
+func (l *BreachedList) Len() int {
+	return l.size
+}
+
+// Breached reports whether the SHA-1 digest of password is in the corpus.
+func (l *BreachedList) Breached(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes := l.buckets[digest[:sha1PrefixLength]]
+	suffix := digest[sha1PrefixLength:]
+	i := sort.SearchStrings(suffixes, suffix)
+	return i < len(suffixes) && suffixes[i] == suffix
+}
@@ -0,0 +1,78 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Algorithm hashes and verifies passwords in one self-describing format.
+type Algorithm interface {
+	// Name is the algorithm identifier, e.g. "argon2id".
+	Name() string
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (bool, error)
+	// Identifies reports whether encoded was produced by this algorithm.
+	Identifies(encoded string) bool
+	// NeedsRehash reports whether encoded uses weaker parameters than the
+	// algorithm is configured with.
+	NeedsRehash(encoded string) bool
+}
+
+// Hasher hashes new passwords with a preferred algorithm and verifies hashes
+// produced by any of its algorithms, so stored hashes can be upgraded one
+// login at a time.
+type Hasher struct {
+	preferred  Algorithm
+	algorithms []Algorithm
+}
+
+// NewHasher creates a hasher preferring the first algorithm. Legacy
+// algorithms are only used to verify existing hashes.
+func NewHasher(preferred Algorithm, legacy ...Algorithm) *Hasher {
+	return &Hasher{
+		preferred:  preferred,
+		algorithms: append([]Algorithm{preferred}, legacy...),
+	}
+}
+
+// New creates a hasher preferring the named algorithm that still verifies
+// hashes of the other supported algorithm.
+func New(name string, bcryptCost int, argon Argon2idParams) (*Hasher, error) {
+	b := NewBcrypt(bcryptCost)
+	a := NewArgon2id(argon)
+
+	switch name {
+	case "", a.Name():
+		return NewHasher(a, b), nil
+	case b.Name():
+		return NewHasher(b, a), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", name)
+	}
+}
+
+// Hash hashes password with the preferred algorithm.
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify reports whether password matches encoded.
+func (h *Hasher) Verify(password string, encoded string) (bool, error) {
+	for _, a := range h.algorithms {
+		if a.Identifies(encoded) {
+			return a.Verify(password, encoded)
+		}
+	}
+	return false, ErrUnknownHash
+}
+
+// NeedsRehash reports whether encoded should be replaced by a hash from the
+// preferred algorithm with the current parameters.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if !h.preferred.Identifies(encoded) {
+		return true
+	}
+	return h.preferred.NeedsRehash(encoded)
+}
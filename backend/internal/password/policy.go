@@ -0,0 +1,137 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy rule identifiers reported in violations.
+const (
+	RuleMinLength    = "min_length"
+	RuleMaxLength    = "max_length"
+	RuleUppercase    = "uppercase"
+	RuleLowercase    = "lowercase"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+)
+
+// Violation describes one rule a password breaks.
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// PolicyError lists every rule a password breaks.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return "password does not meet the policy: " + strings.Join(msgs, "; ")
+}
+
+// BreachChecker reports whether a password is known from a data breach.
+type BreachChecker interface {
+	Breached(password string) bool
+}
+
+// Policy decides which passwords are acceptable. Lengths are counted in
+// characters; a zero MaxLength disables the upper bound.
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// MaxBytes limits the encoded length of a password, for algorithms such
+	// as bcrypt that cannot hash longer ones. Zero disables it.
+	MaxBytes      int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// Breached, if set, rejects passwords found in breach corpora.
+	Breached BreachChecker
+}
+
+// Check validates password and returns a *PolicyError listing every
+// violated rule, or nil. Personal values such as the email address and
+// username may not be used as the password.
+func (p *Policy) Check(password string, personal ...string) error {
+	var violations []Violation
+	add := func(rule string, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	n := utf8.RuneCountInString(password)
+	if n < p.MinLength {
+		add(RuleMinLength, "must be at least %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		add(RuleMaxLength, "must be at most %d characters", p.MaxLength)
+	} else if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		add(RuleMaxLength, "must be at most %d bytes", p.MaxBytes)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		add(RuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		add(RuleLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		add(RuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add(RuleSymbol, "must contain a symbol")
+	}
+
+	if isPersonal(password, personal) {
+		add(RulePersonalInfo, "must not be your email address or username")
+	}
+
+	// Skip the corpus lookup for passwords already rejected for length.
+	if p.Breached != nil && n >= p.MinLength && p.Breached.Breached(password) {
+		add(RuleBreached, "has appeared in a data breach, choose another")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// isPersonal reports whether password equals one of the personal values, or
+// the local part of an email address among them, ignoring case.
+func isPersonal(password string, personal []string) bool {
+	for _, v := range personal {
+		if v == "" {
+			continue
+		}
+		if strings.EqualFold(password, v) {
+			return true
+		}
+		if local, _, ok := strings.Cut(v, "@"); ok && local != "" && strings.EqualFold(password, local) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package passwordreset
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"app/domain"
+	"app/internal/password"
+	"app/internal/response"
+	"app/internal/user"
+
+	"github.com/labstack/gommon/log"
+)
+
+type Handler struct {
+	Service *Service
+}
+
+func NewHandler(s *Service) *Handler {
+	return &Handler{Service: s}
+}
+
+// Forgot godoc
+// @Summary      Request password reset
+// @Description  Email a single-use password reset link if the address belongs to an account. The response does not reveal whether it does.
+// @Tags         auth
+// @Accept       json
+// @Param        request body domain.ForgotPasswordDTO true "Email address"
+// @Success      202 "Reset email sent if applicable"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Router       /auth/password/forgot [post]
+func (h *Handler) Forgot(w http.ResponseWriter, r *http.Request) {
+	var dto domain.ForgotPasswordDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Email == "" {
+		response.Error(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	// Failures are only logged: an error response would tell the caller
+	// that the account exists.
+	if err := h.Service.Request(r.Context(), dto.Email); err != nil {
+		log.Error("Failed to send password reset email ", err.Error())
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Reset godoc
+// @Summary      Reset password
+// @Description  Set a new password with the token from the reset email. All sessions of the user are logged out.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.ResetPasswordDTO true "Reset token and new password"
+// @Success      204 "Password changed"
+// @Failure      400 {object} domain.PasswordPolicyErrorResponse "Invalid request body or token, or password policy violation"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/password/reset [post]
+func (h *Handler) Reset(w http.ResponseWriter, r *http.Request) {
+	var dto domain.ResetPasswordDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Token == "" || dto.Password == "" {
+		response.Error(w, http.StatusBadRequest, "token and password are required")
+		return
+	}
+
+	if err := h.Service.Reset(r.Context(), dto.Token, dto.Password); err != nil {
+		var policyErr *password.PolicyError
+		switch {
+		case errors.Is(err, ErrInvalidResetToken):
+			response.Error(w, http.StatusBadRequest, "invalid or expired password reset token")
+		case errors.As(err, &policyErr):
+			response.JSON(w, http.StatusBadRequest, user.ToPolicyErrorResponse(policyErr))
+		default:
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package passwordreset
+
+import (
+	"app/ent"
+	"app/ent/passwordresettoken"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrResetTokenNotFound = errors.New("password reset token not found")
+
+// Repository defines the interface for password reset token data access.
+type Repository interface {
+	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (*ent.PasswordResetToken, error)
+	GetUsable(ctx context.Context, tokenHash string) (*ent.PasswordResetToken, error)
+	Redeem(ctx context.Context, tokenHash string) (*ent.PasswordResetToken, error)
+	InvalidateForUser(ctx context.Context, userID int) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// Create inserts a new reset token into the database.
+func (r *PostgresRepo) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (*ent.PasswordResetToken, error) {
+	return r.Db.Client.PasswordResetToken.Create().
+		SetUserID(userID).
+		SetTokenHash(tokenHash).
+		SetExpiresAt(expiresAt).
+		Save(ctx)
+}
+
+// GetUsable returns the token if it is unused and unexpired.
+func (r *PostgresRepo) GetUsable(ctx context.Context, tokenHash string) (*ent.PasswordResetToken, error) {
+	token, err := r.Db.Client.PasswordResetToken.Query().
+		Where(
+			passwordresettoken.TokenHashEQ(tokenHash),
+			passwordresettoken.UsedAtIsNil(),
+			passwordresettoken.ExpiresAtGT(time.Now()),
+		).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrResetTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// Redeem marks the token as used if it is unused and unexpired and returns
+// it. The conditional update guarantees that a token is redeemed only once,
+// even by concurrent requests.
+func (r *PostgresRepo) Redeem(ctx context.Context, tokenHash string) (*ent.PasswordResetToken, error) {
+	now := time.Now()
+	n, err := r.Db.Client.PasswordResetToken.Update().
+		Where(
+			passwordresettoken.TokenHashEQ(tokenHash),
+			passwordresettoken.UsedAtIsNil(),
+			passwordresettoken.ExpiresAtGT(now),
+		).
+		SetUsedAt(now).
+		Save(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, ErrResetTokenNotFound
+	}
+
+	return r.Db.Client.PasswordResetToken.Query().
+		Where(passwordresettoken.TokenHashEQ(tokenHash)).
+		Only(ctx)
+}
+
+// InvalidateForUser marks every outstanding reset token of a user as used.
+func (r *PostgresRepo) InvalidateForUser(ctx context.Context, userID int) error {
+	return r.Db.Client.PasswordResetToken.Update().
+		Where(
+			passwordresettoken.UserIDEQ(userID),
+			passwordresettoken.UsedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Exec(ctx)
+}
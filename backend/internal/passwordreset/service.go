@@ -0,0 +1,129 @@
+package passwordreset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"app/internal/mailer"
+	"app/internal/refreshtoken"
+	"app/internal/user"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// Config controls reset links.
+type Config struct {
+	// TTL is how long a reset link stays valid.
+	TTL time.Duration
+	// LinkBaseURL is the frontend URL the link points at; the token is
+	// appended as the token query parameter.
+	LinkBaseURL string
+}
+
+// Service issues and redeems password reset tokens. Tokens are random,
+// stored only as SHA-256 hashes and usable once.
+type Service struct {
+	Repo     Repository
+	Users    *user.Service
+	Sessions *refreshtoken.Service
+	Mailer   mailer.Mailer
+	Config   Config
+}
+
+func NewService(repo Repository, users *user.Service, sessions *refreshtoken.Service, m mailer.Mailer, cfg Config) *Service {
+	return &Service{
+		Repo:     repo,
+		Users:    users,
+		Sessions: sessions,
+		Mailer:   m,
+		Config:   cfg,
+	}
+}
+
+// Request mails a reset link if email belongs to an account. Unknown
+// addresses are ignored so callers cannot probe which accounts exist.
+func (s *Service) Request(ctx context.Context, email string) error {
+	u, err := s.Users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := refreshtoken.Generate()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(s.Config.TTL)
+	if _, err := s.Repo.Create(ctx, u.ID, refreshtoken.Hash(token), expiresAt); err != nil {
+		return err
+	}
+
+	link, err := s.link(token)
+	if err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Choose a new password by opening the link below:\n\n%s\n\nThe link expires in %s and works once. If you did not ask to reset your password, ignore this email.\n",
+			link, s.Config.TTL,
+		),
+	})
+}
+
+// Reset sets a new password for the owner of token, invalidates their other
+// reset links and logs them out of every session.
+func (s *Service) Reset(ctx context.Context, token string, password string) error {
+	tokenHash := refreshtoken.Hash(token)
+
+	rt, err := s.Repo.GetUsable(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, ErrResetTokenNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	// Check before redeeming so a rejected password does not burn the token.
+	if err := s.Users.ValidateNewPassword(ctx, rt.UserID, password); err != nil {
+		return err
+	}
+
+	rt, err = s.Repo.Redeem(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, ErrResetTokenNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if err := s.Users.SetPassword(ctx, rt.UserID, password); err != nil {
+		return err
+	}
+
+	if err := s.Repo.InvalidateForUser(ctx, rt.UserID); err != nil {
+		return err
+	}
+
+	return s.Sessions.RevokeAllForUser(ctx, rt.UserID)
+}
+
+func (s *Service) link(token string) (string, error) {
+	u, err := url.Parse(s.Config.LinkBaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid password reset link base url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
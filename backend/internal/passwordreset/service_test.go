@@ -0,0 +1,75 @@
+package passwordreset
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/internal/mailer"
+	"app/internal/refreshtoken"
+	"app/internal/testutil"
+	"app/internal/user"
+)
+
+const testLinkBaseURL = "http://localhost:3000/reset-password"
+
+func newTestService(t *testing.T) (*Service, *mailer.Memory) {
+	t.Helper()
+
+	d := testutil.DB(t)
+	sessions := refreshtoken.NewService(refreshtoken.NewPostgresRepo(d), refreshtoken.Config{TTL: time.Hour})
+	m := mailer.NewMemory()
+	return NewService(NewPostgresRepo(d), testutil.Users(d, user.Config{}), sessions, m, Config{TTL: time.Hour, LinkBaseURL: testLinkBaseURL}), m
+}
+
+func TestResetEmail(t *testing.T) {
+	s, m := newTestService(t)
+	ctx := context.Background()
+
+	u, err := s.Users.Register(ctx, "alice@example.com", "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued, err := s.Sessions.Generate(ctx, u.ID, refreshtoken.Client{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Request(ctx, "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	msg, ok := m.Last("alice@example.com")
+	if !ok {
+		t.Fatal("no reset email sent")
+	}
+	if msg.Subject != "Reset your password" {
+		t.Fatalf("subject = %q", msg.Subject)
+	}
+
+	token := testutil.LinkToken(t, msg, testLinkBaseURL)
+	if err := s.Reset(ctx, token, "new password 456"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if _, err := s.Users.Login(ctx, "alice@example.com", "new password 456", ""); err != nil {
+		t.Fatalf("login with the new password: %v", err)
+	}
+	if _, err := s.Sessions.Validate(ctx, issued.Token); err == nil {
+		t.Fatal("session survived the reset")
+	}
+
+	if err := s.Reset(ctx, token, "another password 789"); !errors.Is(err, ErrInvalidResetToken) {
+		t.Fatalf("reused link: got %v, want ErrInvalidResetToken", err)
+	}
+}
+
+func TestResetEmailUnknownAddress(t *testing.T) {
+	s, m := newTestService(t)
+
+	if err := s.Request(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Messages()) != 0 {
+		t.Fatalf("email sent to an unknown address: %+v", m.Messages())
+	}
+}
@@ -0,0 +1,179 @@
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"app/domain"
+	"app/internal/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	Service *Service
+}
+
+func NewHandler(s *Service) *Handler {
+	return &Handler{Service: s}
+}
+
+// ListRoles godoc
+// @Summary      List roles
+// @Description  List every role and the permissions it grants
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.RoleResponse "Roles"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /admin/roles [get]
+func (h *Handler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.Service.ListRoles(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToRoleResponses(roles))
+}
+
+// CreateRole godoc
+// @Summary      Create role
+// @Description  Define a new role with a set of permissions
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateRoleDTO true "Role definition"
+// @Success      201 {object} domain.RoleResponse "Created role"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden"
+// @Failure      409 {object} domain.ErrorResponse "Role already exists"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /admin/roles [post]
+func (h *Handler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	var dto domain.CreateRoleDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ro, err := h.Service.CreateRole(r.Context(), dto.Name, dto.Description, dto.Permissions)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRole):
+			response.Error(w, http.StatusBadRequest, "role name is required")
+		case errors.Is(err, ErrRoleExists):
+			response.Error(w, http.StatusConflict, "role already exists")
+		default:
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, ToRoleResponse(ro))
+}
+
+// UserRoles godoc
+// @Summary      List user roles
+// @Description  List the roles granted to a user
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "User ID"
+// @Success      200 {array} domain.RoleResponse "Roles"
+// @Failure      400 {object} domain.ErrorResponse "Invalid user id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/roles [get]
+func (h *Handler) UserRoles(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	roles, err := h.Service.UserRoles(r.Context(), userID)
+	if err != nil {
+		h.respondWithServiceError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToRoleResponses(roles))
+}
+
+// Grant godoc
+// @Summary      Grant role
+// @Description  Grant a role to a user. Takes effect on the user's next token refresh.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id   path int    true "User ID"
+// @Param        role path string true "Role name"
+// @Success      204 "Role granted"
+// @Failure      400 {object} domain.ErrorResponse "Invalid user id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden"
+// @Failure      404 {object} domain.ErrorResponse "User or role not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/roles/{role} [put]
+func (h *Handler) Grant(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if err := h.Service.Grant(r.Context(), userID, chi.URLParam(r, "role")); err != nil {
+		h.respondWithServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Revoke godoc
+// @Summary      Revoke role
+// @Description  Remove a role from a user. Takes effect on the user's next token refresh.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id   path int    true "User ID"
+// @Param        role path string true "Role name"
+// @Success      204 "Role revoked"
+// @Failure      400 {object} domain.ErrorResponse "Invalid user id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden"
+// @Failure      404 {object} domain.ErrorResponse "User or role not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/roles/{role} [delete]
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if err := h.Service.Revoke(r.Context(), userID, chi.URLParam(r, "role")); err != nil {
+		h.respondWithServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondWithServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "user not found")
+	case errors.Is(err, ErrRoleNotFound):
+		response.Error(w, http.StatusNotFound, "role not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,22 @@
+package rbac
+
+import (
+	"app/domain"
+	"app/ent"
+)
+
+func ToRoleResponse(r *ent.Role) domain.RoleResponse {
+	return domain.RoleResponse{
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: r.Permissions,
+	}
+}
+
+func ToRoleResponses(roles []*ent.Role) []domain.RoleResponse {
+	resp := make([]domain.RoleResponse, len(roles))
+	for i, r := range roles {
+		resp[i] = ToRoleResponse(r)
+	}
+	return resp
+}
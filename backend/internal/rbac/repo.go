@@ -0,0 +1,136 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+
+	"app/ent"
+	"app/ent/role"
+	"app/ent/user"
+	"app/internal/db"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleExists   = errors.New("role already exists")
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Repository defines the interface for role data access.
+type Repository interface {
+	ListRoles(ctx context.Context) ([]*ent.Role, error)
+	GetRole(ctx context.Context, name string) (*ent.Role, error)
+	CreateRole(ctx context.Context, name string, description string, permissions []string) (*ent.Role, error)
+	UserRoles(ctx context.Context, userID int) ([]*ent.Role, error)
+	Grant(ctx context.Context, userID int, roleID int) error
+	Revoke(ctx context.Context, userID int, roleID int) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// ListRoles returns all roles ordered by name.
+func (r *PostgresRepo) ListRoles(ctx context.Context) ([]*ent.Role, error) {
+	return r.Db.Client.Role.Query().
+		Order(ent.Asc(role.FieldName)).
+		All(ctx)
+}
+
+// GetRole retrieves a role by name.
+func (r *PostgresRepo) GetRole(ctx context.Context, name string) (*ent.Role, error) {
+	ro, err := r.Db.Client.Role.Query().
+		Where(role.NameEQ(name)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return ro, nil
+}
+
+// CreateRole inserts a new role.
+func (r *PostgresRepo) CreateRole(ctx context.Context, name string, description string, permissions []string) (*ent.Role, error) {
+	ro, err := r.Db.Client.Role.Create().
+		SetName(name).
+		SetDescription(description).
+		SetPermissions(permissions).
+		Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrRoleExists
+		}
+		return nil, err
+	}
+	return ro, nil
+}
+
+// UserRoles returns the roles granted to a user ordered by name.
+func (r *PostgresRepo) UserRoles(ctx context.Context, userID int) ([]*ent.Role, error) {
+	if err := r.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return r.Db.Client.User.Query().
+		Where(user.ID(userID)).
+		QueryRoles().
+		Order(ent.Asc(role.FieldName)).
+		All(ctx)
+}
+
+// Grant adds a role to a user. Granting a role the user already has is a no-op.
+func (r *PostgresRepo) Grant(ctx context.Context, userID int, roleID int) error {
+	if err := r.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+
+	has, err := r.Db.Client.User.Query().
+		Where(user.ID(userID), user.HasRolesWith(role.ID(roleID))).
+		Exist(ctx)
+	if err != nil {
+		return err
+	}
+	if has {
+		return nil
+	}
+
+	err = r.Db.Client.User.UpdateOneID(userID).
+		AddRoleIDs(roleID).
+		Exec(ctx)
+	if ent.IsNotFound(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Revoke removes a role from a user.
+func (r *PostgresRepo) Revoke(ctx context.Context, userID int, roleID int) error {
+	err := r.Db.Client.User.UpdateOneID(userID).
+		RemoveRoleIDs(roleID).
+		Exec(ctx)
+	if ent.IsNotFound(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (r *PostgresRepo) ensureUser(ctx context.Context, userID int) error {
+	exists, err := r.Db.Client.User.Query().
+		Where(user.ID(userID)).
+		Exist(ctx)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return nil
+}
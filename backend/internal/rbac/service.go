@@ -0,0 +1,90 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"app/ent"
+	"app/internal/auth"
+)
+
+// Built-in role and permission names.
+const (
+	RoleAdmin = "admin"
+
+	PermUsersRead   = "users:read"
+	PermUsersWrite  = "users:write"
+	PermRolesManage = "roles:manage"
+)
+
+var ErrInvalidRole = errors.New("invalid role name")
+
+// Service handles role and permission business logic.
+type Service struct {
+	Repo Repository
+}
+
+// NewService creates a new role service.
+func NewService(repo Repository) *Service {
+	return &Service{Repo: repo}
+}
+
+// EnsureDefaults creates the built-in admin role if it does not exist yet.
+// The admin role holds the wildcard permission.
+func (s *Service) EnsureDefaults(ctx context.Context) error {
+	_, err := s.Repo.GetRole(ctx, RoleAdmin)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrRoleNotFound) {
+		return err
+	}
+
+	_, err = s.Repo.CreateRole(ctx, RoleAdmin, "Full access to every endpoint", []string{auth.WildcardPermission})
+	if errors.Is(err, ErrRoleExists) {
+		// Created concurrently by another instance.
+		return nil
+	}
+	return err
+}
+
+// ListRoles returns all roles.
+func (s *Service) ListRoles(ctx context.Context) ([]*ent.Role, error) {
+	return s.Repo.ListRoles(ctx)
+}
+
+// CreateRole defines a new role with the given permissions.
+func (s *Service) CreateRole(ctx context.Context, name string, description string, permissions []string) (*ent.Role, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrInvalidRole
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return s.Repo.CreateRole(ctx, name, description, permissions)
+}
+
+// UserRoles returns the roles granted to a user.
+func (s *Service) UserRoles(ctx context.Context, userID int) ([]*ent.Role, error) {
+	return s.Repo.UserRoles(ctx, userID)
+}
+
+// Grant gives a user the named role.
+func (s *Service) Grant(ctx context.Context, userID int, roleName string) error {
+	ro, err := s.Repo.GetRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Grant(ctx, userID, ro.ID)
+}
+
+// Revoke takes the named role away from a user.
+func (s *Service) Revoke(ctx context.Context, userID int, roleName string) error {
+	ro, err := s.Repo.GetRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+	return s.Repo.Revoke(ctx, userID, ro.ID)
+}
@@ -0,0 +1,97 @@
+package refreshtoken
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Client identifies the device presenting a refresh token.
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+// ClientFromRequest extracts the client from r. It relies on the RealIP
+// middleware having replaced RemoteAddr with the originating address when
+// the request came through a trusted proxy.
+func ClientFromRequest(r *http.Request) Client {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	return Client{
+		UserAgent: r.UserAgent(),
+		IP:        ip,
+	}
+}
+
+// Fingerprint returns a stable hash of the client attributes.
+func (c Client) Fingerprint() string {
+	return Hash(c.UserAgent + "\x00" + c.IP)
+}
+
+// Label returns a human readable description of the client such as
+// "Firefox on Linux", derived from the user agent.
+func (c Client) Label() string {
+	ua := c.UserAgent
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	for _, b := range browsers {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	platform := ""
+	for _, o := range operatingSystems {
+		if strings.Contains(ua, o.token) {
+			platform = o.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+
+	// Non-browser clients: use the product token, e.g. "curl/8.5.0".
+	product, _, _ := strings.Cut(ua, " ")
+	product, _, _ = strings.Cut(product, "/")
+	return product
+}
+
+type uaToken struct {
+	token string
+	name  string
+}
+
+// Checked in order: most user agents also name the engines they derive from.
+var browsers = []uaToken{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"Safari/", "Safari"},
+}
+
+var operatingSystems = []uaToken{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
@@ -0,0 +1,99 @@
+package refreshtoken
+
+import (
+	"errors"
+	"net/http"
+
+	"app/internal/middleware"
+	"app/internal/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	Service *Service
+}
+
+func NewHandler(s *Service) *Handler {
+	return &Handler{Service: s}
+}
+
+// List godoc
+// @Summary      List sessions
+// @Description  List the active sessions of the authenticated user, most recently used first. The session of the calling access token is marked as current.
+// @Tags         sessions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} domain.SessionResponse "Active sessions"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /sessions [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	claims, ok := middleware.ClaimsFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	sessions, err := h.Service.Sessions(r.Context(), claims.UserID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToSessionResponses(sessions, claims.SessionID))
+}
+
+// Revoke godoc
+// @Summary      Revoke session
+// @Description  Log out one session of the authenticated user. Access tokens already issued to it stay valid until they expire.
+// @Tags         sessions
+// @Security     BearerAuth
+// @Param        id path string true "Session ID"
+// @Success      204 "Session revoked"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "Session not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /sessions/{id} [delete]
+func (h *Handler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.Service.RevokeSession(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			response.Error(w, http.StatusNotFound, "session not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary      Logout everywhere
+// @Description  Revoke every session of the authenticated user, including the current one
+// @Tags         auth
+// @Security     BearerAuth
+// @Success      204 "All sessions revoked"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      500 {object} domain.ErrorResponse "Failed to logout"
+// @Router       /auth/logout-all [post]
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.Service.RevokeAllForUser(r.Context(), userID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to logout")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
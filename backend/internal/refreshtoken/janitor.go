@@ -0,0 +1,76 @@
+package refreshtoken
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const defaultBatchSize = 1000
+
+// Janitor deletes refresh tokens that expired or were revoked more than
+// Retention ago. Rotated tokens are kept for the retention period so that
+// replaying them is still detected as reuse.
+type Janitor struct {
+	Repo      Repository
+	Retention time.Duration
+	BatchSize int
+}
+
+// NewJanitor creates a janitor deleting at most batchSize rows per statement.
+func NewJanitor(repo Repository, retention time.Duration, batchSize int) *Janitor {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &Janitor{
+		Repo:      repo,
+		Retention: retention,
+		BatchSize: batchSize,
+	}
+}
+
+// Purge deletes stale tokens in batches until none are left and returns the
+// number of rows deleted. Batches keep each statement short so the table is
+// never locked for long.
+func (j *Janitor) Purge(ctx context.Context) (int, error) {
+	before := time.Now().Add(-j.Retention)
+
+	total := 0
+	for {
+		n, err := j.Repo.DeleteStale(ctx, before, j.BatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < j.BatchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// Run purges stale tokens right away and then every interval until ctx is
+// done. Failures are logged and retried on the next tick.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := j.Purge(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error("Failed to purge refresh tokens ", err.Error())
+		}
+		if n > 0 {
+			log.Infof("Purged %d stale refresh tokens", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package refreshtoken
+
+import (
+	"app/domain"
+	"app/ent"
+)
+
+// ToSessionResponse maps the live token of a session. currentID is the
+// session of the requesting access token.
+func ToSessionResponse(rt *ent.RefreshToken, currentID string) domain.SessionResponse {
+	resp := domain.SessionResponse{
+		ID:         rt.FamilyID,
+		Label:      rt.Label,
+		UserAgent:  rt.UserAgent,
+		IP:         rt.IP,
+		CreatedAt:  rt.CreatedAt,
+		LastUsedAt: rt.CreatedAt,
+		ExpiresAt:  rt.ExpiresAt,
+		Current:    currentID != "" && rt.FamilyID == currentID,
+	}
+	if rt.SessionStartedAt != nil {
+		resp.CreatedAt = *rt.SessionStartedAt
+	}
+	if rt.LastUsedAt != nil {
+		resp.LastUsedAt = *rt.LastUsedAt
+	}
+	return resp
+}
+
+func ToSessionResponses(tokens []*ent.RefreshToken, currentID string) []domain.SessionResponse {
+	resp := make([]domain.SessionResponse, len(tokens))
+	for i, rt := range tokens {
+		resp[i] = ToSessionResponse(rt, currentID)
+	}
+	return resp
+}
@@ -6,17 +6,34 @@ import (
 	"app/internal/db"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
 var ErrRefreshTokenNotFound = errors.New("refresh token not found")
 
+// Session holds the device metadata stored with each refresh token.
+type Session struct {
+	UserAgent string
+	IP        string
+	Label     string
+	StartedAt time.Time
+	Grant     Grant
+}
+
 // Repository defines the interface for refresh token data access.
 type Repository interface {
-	Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (*ent.RefreshToken, error)
+	Create(ctx context.Context, userID int, tokenHash string, familyID string, expiresAt time.Time, session Session) (*ent.RefreshToken, error)
 	GetByTokenHash(ctx context.Context, tokenHash string) (*ent.RefreshToken, error)
+	ListActiveForUser(ctx context.Context, userID int) ([]*ent.RefreshToken, error)
+	MarkRotated(ctx context.Context, id int, nonce string, client string) (bool, error)
 	Revoke(ctx context.Context, tokenHash string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeFamilyForUser(ctx context.Context, userID int, familyID string) (int, error)
 	RevokeAllForUser(ctx context.Context, userID int) error
+	RevokeClient(ctx context.Context, clientID string, userID *int) error
+	DeleteStale(ctx context.Context, before time.Time, limit int) (int, error)
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
 }
 
 // PostgresRepo implements Repository using PostgreSQL via Ent.
@@ -30,11 +47,19 @@ func NewPostgresRepo(db *db.Db) Repository {
 }
 
 // Create inserts a new refresh token into the database.
-func (r *PostgresRepo) Create(ctx context.Context, userID int, tokenHash string, expiresAt time.Time) (*ent.RefreshToken, error) {
+func (r *PostgresRepo) Create(ctx context.Context, userID int, tokenHash string, familyID string, expiresAt time.Time, session Session) (*ent.RefreshToken, error) {
 	return r.Db.Client.RefreshToken.Create().
 		SetUserID(userID).
 		SetTokenHash(tokenHash).
+		SetFamilyID(familyID).
 		SetExpiresAt(expiresAt).
+		SetSessionStartedAt(session.StartedAt).
+		SetLastUsedAt(time.Now()).
+		SetUserAgent(session.UserAgent).
+		SetIP(session.IP).
+		SetLabel(session.Label).
+		SetOauthClientID(session.Grant.ClientID).
+		SetScope(session.Grant.Scope).
 		Save(ctx)
 }
 
@@ -52,18 +77,139 @@ func (r *PostgresRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*e
 	return token, nil
 }
 
+// ListActiveForUser returns the unrevoked, unexpired tokens of a user,
+// most recently used first.
+func (r *PostgresRepo) ListActiveForUser(ctx context.Context, userID int) ([]*ent.RefreshToken, error) {
+	return r.Db.Client.RefreshToken.Query().
+		Where(
+			refreshtoken.UserIDEQ(userID),
+			refreshtoken.RevokedEQ(false),
+			refreshtoken.ExpiresAtGT(time.Now()),
+		).
+		Order(ent.Desc(refreshtoken.FieldCreatedAt), ent.Desc(refreshtoken.FieldID)).
+		All(ctx)
+}
+
+// MarkRotated revokes the token only if it is still unrevoked and records how
+// its successor was derived. It reports false when another request revoked
+// the token first, which makes concurrent rotations of one token race-free.
+func (r *PostgresRepo) MarkRotated(ctx context.Context, id int, nonce string, client string) (bool, error) {
+	n, err := r.Db.Client.RefreshToken.Update().
+		Where(
+			refreshtoken.ID(id),
+			refreshtoken.RevokedEQ(false),
+		).
+		SetRevoked(true).
+		SetRevokedAt(time.Now()).
+		SetRotationNonce(nonce).
+		SetRotationClient(client).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
 // Revoke marks a refresh token as revoked.
 func (r *PostgresRepo) Revoke(ctx context.Context, tokenHash string) error {
 	return r.Db.Client.RefreshToken.Update().
-		Where(refreshtoken.TokenHashEQ(tokenHash)).
+		Where(
+			refreshtoken.TokenHashEQ(tokenHash),
+			refreshtoken.RevokedEQ(false),
+		).
+		SetRevoked(true).
+		SetRevokedAt(time.Now()).
+		Exec(ctx)
+}
+
+// RevokeFamily marks every token in a rotation family as revoked.
+func (r *PostgresRepo) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.Db.Client.RefreshToken.Update().
+		Where(
+			refreshtoken.FamilyIDEQ(familyID),
+			refreshtoken.RevokedEQ(false),
+		).
 		SetRevoked(true).
+		SetRevokedAt(time.Now()).
 		Exec(ctx)
 }
 
+// RevokeFamilyForUser revokes a rotation family only if it belongs to the
+// user and returns the number of tokens revoked.
+func (r *PostgresRepo) RevokeFamilyForUser(ctx context.Context, userID int, familyID string) (int, error) {
+	return r.Db.Client.RefreshToken.Update().
+		Where(
+			refreshtoken.UserIDEQ(userID),
+			refreshtoken.FamilyIDEQ(familyID),
+			refreshtoken.RevokedEQ(false),
+		).
+		SetRevoked(true).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+}
+
 // RevokeAllForUser marks all refresh tokens for a user as revoked.
 func (r *PostgresRepo) RevokeAllForUser(ctx context.Context, userID int) error {
 	return r.Db.Client.RefreshToken.Update().
-		Where(refreshtoken.UserIDEQ(userID)).
+		Where(
+			refreshtoken.UserIDEQ(userID),
+			refreshtoken.RevokedEQ(false),
+		).
+		SetRevoked(true).
+		SetRevokedAt(time.Now()).
+		Exec(ctx)
+}
+
+// RevokeClient revokes the tokens issued to an OAuth client, only those of
+// one user if userID is set.
+func (r *PostgresRepo) RevokeClient(ctx context.Context, clientID string, userID *int) error {
+	update := r.Db.Client.RefreshToken.Update().
+		Where(
+			refreshtoken.OauthClientIDEQ(clientID),
+			refreshtoken.RevokedEQ(false),
+		)
+	if userID != nil {
+		update.Where(refreshtoken.UserIDEQ(*userID))
+	}
+	return update.
 		SetRevoked(true).
+		SetRevokedAt(time.Now()).
 		Exec(ctx)
 }
+
+// DeleteStale deletes up to limit tokens that expired or were revoked before
+// the given time and returns the number deleted.
+func (r *PostgresRepo) DeleteStale(ctx context.Context, before time.Time, limit int) (int, error) {
+	ids, err := r.Db.Client.RefreshToken.Query().
+		Where(refreshtoken.Or(
+			refreshtoken.ExpiresAtLT(before),
+			refreshtoken.RevokedAtLT(before),
+		)).
+		Limit(limit).
+		IDs(ctx)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	return r.Db.Client.RefreshToken.Delete().
+		Where(refreshtoken.IDIn(ids...)).
+		Exec(ctx)
+}
+
+// WithTx runs fn inside a transaction with a repository bound to it.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+func (r *PostgresRepo) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	tx, err := r.Db.Client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&PostgresRepo{Db: &db.Db{Client: tx.Client()}}); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("%w: rolling back transaction: %v", err, rerr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
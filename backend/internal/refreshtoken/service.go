@@ -1,94 +1,228 @@
 package refreshtoken
 
 import (
+	"app/ent"
 	"context"
 	"errors"
 	"time"
+
+	"github.com/labstack/gommon/log"
 )
 
 var (
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 	ErrExpiredRefreshToken = errors.New("refresh token expired")
 	ErrRevokedRefreshToken = errors.New("refresh token revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrSessionNotFound     = errors.New("session not found")
+
+	ErrSessionLifetimeExceeded = errors.New("session lifetime exceeded")
+	ErrSessionIdleTimeout      = errors.New("session idle timeout")
 )
 
+// Config controls refresh token lifetime and reuse handling.
+type Config struct {
+	TTL time.Duration
+	// RevokeAllOnReuse revokes every session of the user, not only the
+	// affected token family, when a revoked token is presented again.
+	RevokeAllOnReuse bool
+	// RotationGrace is how long after a rotation the same client may present
+	// the old token again and receive the same successor, e.g. when the
+	// response to the first request was lost. Zero disables retries.
+	RotationGrace time.Duration
+	// MaxAge caps the lifetime of a session, counted from the first token of
+	// the family and not extended by rotation. Zero disables the limit.
+	MaxAge time.Duration
+	// IdleTimeout ends a session that has not been refreshed for this long,
+	// even if its token has not expired yet. Zero disables the limit.
+	IdleTimeout time.Duration
+}
+
+// Grant records the OAuth client a session was authorized for and the scope
+// it was granted. It is empty for sessions of first-party logins.
+type Grant struct {
+	ClientID string
+	Scope    string
+}
+
+// Issued is a refresh token handed out to a client.
+// SessionID identifies the token family and stays the same across rotations.
+type Issued struct {
+	Token     string
+	UserID    int
+	SessionID string
+	Grant     Grant
+}
+
 // Service handles refresh token business logic.
 type Service struct {
-	Repo Repository
-	TTL  time.Duration
+	Repo   Repository
+	Config Config
 }
 
 // NewService creates a new refresh token service.
-func NewService(repo Repository, ttl time.Duration) *Service {
+func NewService(repo Repository, cfg Config) *Service {
 	return &Service{
-		Repo: repo,
-		TTL:  ttl,
+		Repo:   repo,
+		Config: cfg,
 	}
 }
 
-// Generate creates a new refresh token for the given user.
-// Returns the plain token string (to send to client) and any error.
-func (s *Service) Generate(ctx context.Context, userID int) (string, error) {
+// Generate creates a new refresh token for the given user, starting a new
+// token family, i.e. a new session on the client's device.
+func (s *Service) Generate(ctx context.Context, userID int, client Client) (*Issued, error) {
+	return s.GenerateForClient(ctx, userID, client, Grant{})
+}
+
+// GenerateForClient is Generate for a session authorized for an OAuth
+// client. The grant is kept by every token of the family.
+func (s *Service) GenerateForClient(ctx context.Context, userID int, client Client, grant Grant) (*Issued, error) {
 	token, err := Generate()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	tokenHash := Hash(token)
-	expiresAt := time.Now().Add(s.TTL)
-
-	_, err = s.Repo.Create(ctx, userID, tokenHash, expiresAt)
+	familyID, err := NewFamilyID()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if err := s.store(ctx, s.Repo, userID, token, familyID, client, time.Now(), grant); err != nil {
+		return nil, err
 	}
 
-	return token, nil
+	return &Issued{Token: token, UserID: userID, SessionID: familyID, Grant: grant}, nil
 }
 
 // Validate checks if the token is valid, not expired, and not revoked.
-// Returns the associated userID if valid, or an error.
+// Returns the associated userID if valid, or an error. A token outliving its
+// session reports ErrSessionLifetimeExceeded or ErrSessionIdleTimeout.
 func (s *Service) Validate(ctx context.Context, token string) (int, error) {
-	tokenHash := Hash(token)
-
-	rt, err := s.Repo.GetByTokenHash(ctx, tokenHash)
+	rt, err := s.Inspect(ctx, token)
 	if err != nil {
-		if errors.Is(err, ErrRefreshTokenNotFound) {
-			return 0, ErrInvalidRefreshToken
-		}
 		return 0, err
 	}
+	return rt.UserID, nil
+}
+
+// Inspect is Validate returning the stored token, e.g. to report its
+// session and grant.
+func (s *Service) Inspect(ctx context.Context, token string) (*ent.RefreshToken, error) {
+	rt, err := s.get(ctx, s.Repo, token)
+	if err != nil {
+		return nil, err
+	}
 
 	if rt.Revoked {
-		return 0, ErrRevokedRefreshToken
+		return nil, ErrRevokedRefreshToken
 	}
 
-	if time.Now().After(rt.ExpiresAt) {
-		return 0, ErrExpiredRefreshToken
+	if err := s.checkExpiry(rt, time.Now()); err != nil {
+		return nil, err
 	}
 
-	return rt.UserID, nil
+	return rt, nil
 }
 
-// Rotate validates the old token, revokes it, and generates a new one.
-// This implements single-use tokens with automatic rotation.
-// Returns the new token, userID, and any error.
-func (s *Service) Rotate(ctx context.Context, oldToken string) (string, int, error) {
-	userID, err := s.Validate(ctx, oldToken)
-	if err != nil {
-		return "", 0, err
-	}
+// Find returns the stored token whatever its state, or
+// ErrInvalidRefreshToken if it is unknown.
+func (s *Service) Find(ctx context.Context, token string) (*ent.RefreshToken, error) {
+	return s.get(ctx, s.Repo, token)
+}
 
-	oldTokenHash := Hash(oldToken)
-	if err := s.Repo.Revoke(ctx, oldTokenHash); err != nil {
-		return "", 0, err
-	}
+// Rotate validates the old token, revokes it, and generates a new one in the
+// same family.
+// This implements single-use tokens with automatic rotation. The token is
+// revoked with a conditional update inside a transaction, so of two
+// concurrent rotations only one wins. The loser, or a retry by the same
+// client within the grace period, receives the same successor; any other
+// presentation of a revoked token revokes the whole family and returns
+// ErrRefreshTokenReused.
+// The successor records the presenting client as the session's latest device.
+// Tokens of sessions authorized for an OAuth client are rejected; they are
+// rotated with RotateForClient.
+func (s *Service) Rotate(ctx context.Context, oldToken string, client Client) (*Issued, error) {
+	return s.RotateForClient(ctx, oldToken, client, "")
+}
+
+// RotateForClient is Rotate for a token issued to the OAuth client clientID.
+// A token of another client, or of a first-party login, is rejected with
+// ErrInvalidRefreshToken and left untouched.
+func (s *Service) RotateForClient(ctx context.Context, oldToken string, client Client, clientID string) (*Issued, error) {
+	var (
+		issued *Issued
+		reused *ent.RefreshToken
+	)
+
+	err := s.Repo.WithTx(ctx, func(repo Repository) error {
+		rt, err := s.get(ctx, repo, oldToken)
+		if err != nil {
+			return err
+		}
+		if rt.OauthClientID != clientID {
+			return ErrInvalidRefreshToken
+		}
+
+		if !rt.Revoked {
+			if err := s.checkExpiry(rt, time.Now()); err != nil {
+				return err
+			}
+
+			nonce, err := Generate()
+			if err != nil {
+				return err
+			}
+
+			won, err := repo.MarkRotated(ctx, rt.ID, nonce, client.Fingerprint())
+			if err != nil {
+				return err
+			}
+			if won {
+				familyID := rt.FamilyID
+				if familyID == "" {
+					// Legacy token without a family: start one on first rotation.
+					if familyID, err = NewFamilyID(); err != nil {
+						return err
+					}
+				}
+
+				newToken := Derive(oldToken, nonce)
+				if err := s.store(ctx, repo, rt.UserID, newToken, familyID, client, sessionStart(rt), grantOf(rt)); err != nil {
+					return err
+				}
+				issued = &Issued{Token: newToken, UserID: rt.UserID, SessionID: familyID, Grant: grantOf(rt)}
+				return nil
+			}
+
+			// A concurrent request rotated the token first; re-read its outcome.
+			if rt, err = s.get(ctx, repo, oldToken); err != nil {
+				return err
+			}
+		}
+
+		successor, err := s.successor(ctx, repo, rt, oldToken, client)
+		if err != nil {
+			return err
+		}
+		if successor == nil {
+			reused = rt
+			return ErrRefreshTokenReused
+		}
 
-	newToken, err := s.Generate(ctx, userID)
+		issued = successor
+		return nil
+	})
+
+	if errors.Is(err, ErrRefreshTokenReused) {
+		if rerr := s.handleReuse(ctx, reused); rerr != nil {
+			return nil, rerr
+		}
+	}
 	if err != nil {
-		return "", 0, err
+		return nil, err
 	}
 
-	return newToken, userID, nil
+	return issued, nil
 }
 
 // Revoke marks the given token as revoked.
@@ -96,3 +230,178 @@ func (s *Service) Revoke(ctx context.Context, token string) error {
 	tokenHash := Hash(token)
 	return s.Repo.Revoke(ctx, tokenHash)
 }
+
+// RevokeAllForUser revokes every refresh token of the user.
+func (s *Service) RevokeAllForUser(ctx context.Context, userID int) error {
+	return s.Repo.RevokeAllForUser(ctx, userID)
+}
+
+// Sessions returns the active sessions of the user, most recently used
+// first. Each session is represented by the live token of its family.
+func (s *Service) Sessions(ctx context.Context, userID int) ([]*ent.RefreshToken, error) {
+	tokens, err := s.Repo.ListActiveForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(tokens))
+	sessions := make([]*ent.RefreshToken, 0, len(tokens))
+	for _, rt := range tokens {
+		if s.checkExpiry(rt, now) != nil {
+			continue
+		}
+		// Legacy tokens without a family get one on their next rotation and
+		// can meanwhile only be ended with logout or logout-all.
+		if rt.FamilyID == "" || seen[rt.FamilyID] {
+			continue
+		}
+		seen[rt.FamilyID] = true
+		sessions = append(sessions, rt)
+	}
+	return sessions, nil
+}
+
+// SessionActive reports whether the session sessionID of the user has not
+// been ended, i.e. whether access tokens issued for it are still current.
+func (s *Service) SessionActive(ctx context.Context, userID int, sessionID string) (bool, error) {
+	sessions, err := s.Sessions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, rt := range sessions {
+		if rt.FamilyID == sessionID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RevokeSession ends one session of the user by revoking its token family.
+func (s *Service) RevokeSession(ctx context.Context, userID int, sessionID string) error {
+	if sessionID == "" {
+		return ErrSessionNotFound
+	}
+
+	n, err := s.Repo.RevokeFamilyForUser(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeClient ends every session of the user authorized for the OAuth
+// client clientID.
+func (s *Service) RevokeClient(ctx context.Context, userID int, clientID string) error {
+	return s.Repo.RevokeClient(ctx, clientID, &userID)
+}
+
+// RevokeAllForClient ends every session authorized for the OAuth client
+// clientID, e.g. when the client is deleted.
+func (s *Service) RevokeAllForClient(ctx context.Context, clientID string) error {
+	return s.Repo.RevokeClient(ctx, clientID, nil)
+}
+
+func (s *Service) store(ctx context.Context, repo Repository, userID int, token string, familyID string, client Client, startedAt time.Time, grant Grant) error {
+	expiresAt := time.Now().Add(s.Config.TTL)
+	if s.Config.MaxAge > 0 {
+		if end := startedAt.Add(s.Config.MaxAge); end.Before(expiresAt) {
+			expiresAt = end
+		}
+	}
+	_, err := repo.Create(ctx, userID, Hash(token), familyID, expiresAt, Session{
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+		Label:     client.Label(),
+		StartedAt: startedAt,
+		Grant:     grant,
+	})
+	return err
+}
+
+// checkExpiry reports which limit, if any, rt has exceeded at now. The
+// session lifetime is checked first because it also caps expires_at.
+func (s *Service) checkExpiry(rt *ent.RefreshToken, now time.Time) error {
+	if s.Config.MaxAge > 0 && now.After(sessionStart(rt).Add(s.Config.MaxAge)) {
+		return ErrSessionLifetimeExceeded
+	}
+
+	if s.Config.IdleTimeout > 0 {
+		lastUsed := rt.CreatedAt
+		if rt.LastUsedAt != nil {
+			lastUsed = *rt.LastUsedAt
+		}
+		if now.After(lastUsed.Add(s.Config.IdleTimeout)) {
+			return ErrSessionIdleTimeout
+		}
+	}
+
+	if now.After(rt.ExpiresAt) {
+		return ErrExpiredRefreshToken
+	}
+	return nil
+}
+
+// sessionStart returns when the session of rt began. Tokens issued before
+// sessions were tracked count from their own creation.
+func sessionStart(rt *ent.RefreshToken) time.Time {
+	if rt.SessionStartedAt != nil {
+		return *rt.SessionStartedAt
+	}
+	return rt.CreatedAt
+}
+
+func (s *Service) get(ctx context.Context, repo Repository, token string) (*ent.RefreshToken, error) {
+	rt, err := repo.GetByTokenHash(ctx, Hash(token))
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return rt, nil
+}
+
+// successor returns the token that replaced rt if rt was rotated by the same
+// client within the grace period and that successor is still unused.
+// It returns nil when the presentation must be treated as reuse.
+func (s *Service) successor(ctx context.Context, repo Repository, rt *ent.RefreshToken, token string, client Client) (*Issued, error) {
+	if s.Config.RotationGrace <= 0 || rt.RevokedAt == nil || rt.RotationNonce == "" {
+		return nil, nil
+	}
+	if time.Since(*rt.RevokedAt) > s.Config.RotationGrace || rt.RotationClient != client.Fingerprint() {
+		return nil, nil
+	}
+
+	successor := Derive(token, rt.RotationNonce)
+	next, err := s.get(ctx, repo, successor)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if next.Revoked {
+		return nil, nil
+	}
+
+	return &Issued{Token: successor, UserID: next.UserID, SessionID: next.FamilyID, Grant: grantOf(next)}, nil
+}
+
+func grantOf(rt *ent.RefreshToken) Grant {
+	return Grant{ClientID: rt.OauthClientID, Scope: rt.Scope}
+}
+
+// handleReuse revokes the family of a replayed token, or every session of
+// the user when configured to (and for legacy tokens without a family).
+func (s *Service) handleReuse(ctx context.Context, rt *ent.RefreshToken) error {
+	log.Warnf("refresh token reuse detected: user_id=%d family_id=%q token_id=%d", rt.UserID, rt.FamilyID, rt.ID)
+
+	if s.Config.RevokeAllOnReuse || rt.FamilyID == "" {
+		return s.Repo.RevokeAllForUser(ctx, rt.UserID)
+	}
+	return s.Repo.RevokeFamily(ctx, rt.FamilyID)
+}
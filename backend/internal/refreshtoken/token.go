@@ -1,6 +1,7 @@
 package refreshtoken
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -17,6 +18,24 @@ func Generate() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
+// NewFamilyID creates a random identifier for a new token family.
+func NewFamilyID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate family id: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Derive computes the successor of token from a random rotation nonce.
+// Only the nonce is stored, so re-deriving the successor requires the plain
+// token, which the database never holds.
+func Derive(token string, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte(nonce))
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
 // Hash creates a SHA-256 hash of the token for secure storage.
 // The original token should never be stored in the database.
 func Hash(token string) string {
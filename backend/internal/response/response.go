@@ -0,0 +1,20 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Error writes a JSON error body of the form {"error": message}.
+func Error(w http.ResponseWriter, code int, message string) {
+	JSON(w, code, map[string]string{"error": message})
+}
+
+// JSON writes payload as a JSON response with the given status code.
+func JSON(w http.ResponseWriter, code int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
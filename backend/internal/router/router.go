@@ -1,8 +1,20 @@
 package router
 
 import (
+	"app/internal/apikey"
+	"app/internal/auth"
+	"app/internal/federation"
+	"app/internal/lockout"
+	"app/internal/magiclink"
+	appmiddleware "app/internal/middleware"
+	"app/internal/oidc"
+	"app/internal/passkey"
+	"app/internal/passwordreset"
+	"app/internal/rbac"
+	"app/internal/refreshtoken"
 	"app/internal/user"
 	"net/http"
+	"net/netip"
 
 	_ "app/docs" // Import generated docs
 
@@ -15,23 +27,111 @@ type Router struct {
 	chi *chi.Mux
 }
 
-func NewRouter(userHandler *user.Handler) *Router {
+func NewRouter(verifier appmiddleware.Verifier, userHandler *user.Handler, authHandler *auth.Handler, rbacHandler *rbac.Handler, sessionHandler *refreshtoken.Handler, passwordResetHandler *passwordreset.Handler, lockoutHandler *lockout.Handler, passkeyHandler *passkey.Handler, magicLinkHandler *magiclink.Handler, oidcHandler *oidc.Handler, federationHandler *federation.Handler, apiKeyHandler *apikey.Handler, trustedProxies []netip.Prefix) *Router {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(appmiddleware.RealIP(trustedProxies))
 
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
 
+	r.Get("/.well-known/jwks.json", authHandler.JWKS)
+	r.Get("/.well-known/openid-configuration", oidcHandler.Discovery)
+
+	r.Get("/authorize", oidcHandler.Authorize)
+	r.Post("/authorize", oidcHandler.Authorize)
+	r.Post("/token", oidcHandler.Token)
+	r.Post("/oauth/introspect", oidcHandler.Introspect)
+	r.Post("/oauth/revoke", oidcHandler.Revoke)
+	r.With(appmiddleware.Auth(verifier, auth.SubjectDelegated)).Get("/userinfo", oidcHandler.UserInfo)
+	r.With(appmiddleware.Auth(verifier, auth.SubjectDelegated)).Post("/userinfo", oidcHandler.UserInfo)
+	r.With(appmiddleware.Auth(verifier)).Post("/oauth/authorize", oidcHandler.Complete)
+
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/register", userHandler.Register)
 		r.Post("/login", userHandler.Login)
 		r.Post("/refresh", userHandler.Refresh)
 		r.Post("/logout", userHandler.Logout)
+		r.Post("/verify-email", userHandler.VerifyEmail)
+		r.Post("/verify-email/resend", userHandler.ResendVerification)
+		r.Post("/password/forgot", passwordResetHandler.Forgot)
+		r.Post("/password/reset", passwordResetHandler.Reset)
+		r.Post("/unlock", lockoutHandler.Unlock)
+		r.Post("/mfa/verify", userHandler.VerifyMFA)
+		r.Post("/passkeys/login/begin", passkeyHandler.BeginLogin)
+		r.Post("/passkeys/login/finish", passkeyHandler.FinishLogin)
+		r.Post("/magic-link", magicLinkHandler.Request)
+		r.Post("/magic-link/consume", magicLinkHandler.Consume)
+		r.Get("/federation/providers", federationHandler.Providers)
+		r.Post("/federation/{provider}/begin", federationHandler.Begin)
+		r.Post("/federation/callback", federationHandler.Callback)
+		r.With(appmiddleware.Auth(verifier)).Post("/logout-all", sessionHandler.LogoutAll)
+	})
+
+	// Routes for service clients, limited by the scopes granted to them.
+	r.Group(func(r chi.Router) {
+		r.Use(appmiddleware.Auth(verifier, auth.SubjectClient))
+
+		r.With(appmiddleware.RequireScope(user.ScopeUsersRead)).Get("/users/{id}", userHandler.Get)
+	})
+
+	r.Route("/users/me", func(r chi.Router) {
+		// Resource routes, also open to API keys within their scopes.
+		r.With(appmiddleware.Auth(verifier, auth.SubjectUser, auth.SubjectAPIKey), appmiddleware.RequireScope(user.ScopeUsersRead)).
+			Get("/", userHandler.Me)
+
+		// Account management, for the user's own logins only.
+		r.Group(func(r chi.Router) {
+			r.Use(appmiddleware.Auth(verifier))
+
+			r.Patch("/", userHandler.UpdateMe)
+			r.Delete("/", userHandler.DeleteMe)
+			r.Post("/password", userHandler.ChangePassword)
+			r.Post("/mfa/totp", userHandler.EnrollTOTP)
+			r.Post("/mfa/totp/confirm", userHandler.ConfirmTOTP)
+			r.Delete("/mfa/totp", userHandler.DisableTOTP)
+			r.Post("/mfa/recovery-codes", userHandler.RegenerateRecoveryCodes)
+			r.Get("/passkeys", passkeyHandler.List)
+			r.Post("/passkeys/register/begin", passkeyHandler.BeginRegistration)
+			r.Post("/passkeys/register/finish", passkeyHandler.FinishRegistration)
+			r.Delete("/passkeys/{id}", passkeyHandler.Delete)
+			r.Get("/consents", oidcHandler.ListConsents)
+			r.Delete("/consents/{client_id}", oidcHandler.RevokeConsent)
+			r.Get("/identities", federationHandler.List)
+			r.Post("/identities/{provider}/begin", federationHandler.BeginLink)
+			r.Post("/identities/callback", federationHandler.FinishLink)
+			r.Delete("/identities/{id}", federationHandler.Unlink)
+			r.Get("/api-keys", apiKeyHandler.List)
+			r.Post("/api-keys", apiKeyHandler.Create)
+			r.Delete("/api-keys/{id}", apiKeyHandler.Revoke)
+		})
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(appmiddleware.Auth(verifier))
+
+		r.Route("/sessions", func(r chi.Router) {
+			r.Get("/", sessionHandler.List)
+			r.Delete("/{id}", sessionHandler.Revoke)
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(appmiddleware.RequirePermission(rbac.PermRolesManage))
+
+				r.Get("/roles", rbacHandler.ListRoles)
+				r.Post("/roles", rbacHandler.CreateRole)
+				r.Get("/users/{id}/roles", rbacHandler.UserRoles)
+				r.Put("/users/{id}/roles/{role}", rbacHandler.Grant)
+				r.Delete("/users/{id}/roles/{role}", rbacHandler.Revoke)
+			})
+
+			r.With(appmiddleware.RequirePermission(rbac.PermUsersWrite)).Post("/users/{id}/unlock", lockoutHandler.UnlockUser)
+		})
 	})
 
 	return &Router{chi: r}
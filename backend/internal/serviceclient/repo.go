@@ -0,0 +1,107 @@
+package serviceclient
+
+import (
+	"app/ent"
+	"app/ent/serviceclient"
+	"app/internal/db"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrClientNotFound = errors.New("service client not found")
+	ErrClientExists   = errors.New("service client already exists")
+)
+
+// Repository defines the interface for service client data access.
+type Repository interface {
+	Create(ctx context.Context, clientID string, secretHash string, name string, scopes []string) (*ent.ServiceClient, error)
+	Get(ctx context.Context, clientID string) (*ent.ServiceClient, error)
+	List(ctx context.Context) ([]*ent.ServiceClient, error)
+	Delete(ctx context.Context, clientID string) error
+	UpdateSecret(ctx context.Context, clientID string, secretHash string) error
+	Touch(ctx context.Context, id int, at time.Time) error
+}
+
+// PostgresRepo implements Repository using PostgreSQL via Ent.
+type PostgresRepo struct {
+	Db *db.Db
+}
+
+// NewPostgresRepo creates a new PostgreSQL repository.
+func NewPostgresRepo(db *db.Db) Repository {
+	return &PostgresRepo{Db: db}
+}
+
+// Create inserts a new service client.
+func (r *PostgresRepo) Create(ctx context.Context, clientID string, secretHash string, name string, scopes []string) (*ent.ServiceClient, error) {
+	c, err := r.Db.Client.ServiceClient.Create().
+		SetClientID(clientID).
+		SetSecretHash(secretHash).
+		SetName(name).
+		SetScopes(scopes).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		return nil, ErrClientExists
+	}
+	return c, err
+}
+
+// Get returns the service client with the given client_id.
+func (r *PostgresRepo) Get(ctx context.Context, clientID string) (*ent.ServiceClient, error) {
+	c, err := r.Db.Client.ServiceClient.Query().
+		Where(serviceclient.ClientIDEQ(clientID)).
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrClientNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// List returns every service client, oldest first.
+func (r *PostgresRepo) List(ctx context.Context) ([]*ent.ServiceClient, error) {
+	return r.Db.Client.ServiceClient.Query().
+		Order(ent.Asc(serviceclient.FieldCreatedAt), ent.Asc(serviceclient.FieldID)).
+		All(ctx)
+}
+
+// Delete removes a service client.
+func (r *PostgresRepo) Delete(ctx context.Context, clientID string) error {
+	n, err := r.Db.Client.ServiceClient.Delete().
+		Where(serviceclient.ClientIDEQ(clientID)).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrClientNotFound
+	}
+	return nil
+}
+
+// UpdateSecret replaces the secret of a service client.
+func (r *PostgresRepo) UpdateSecret(ctx context.Context, clientID string, secretHash string) error {
+	n, err := r.Db.Client.ServiceClient.Update().
+		Where(serviceclient.ClientIDEQ(clientID)).
+		SetSecretHash(secretHash).
+		SetSecretRotatedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrClientNotFound
+	}
+	return nil
+}
+
+// Touch records when the client last obtained a token.
+func (r *PostgresRepo) Touch(ctx context.Context, id int, at time.Time) error {
+	return r.Db.Client.ServiceClient.UpdateOneID(id).
+		SetLastUsedAt(at).
+		Exec(ctx)
+}
@@ -0,0 +1,171 @@
+package serviceclient
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/refreshtoken"
+
+	"github.com/labstack/gommon/log"
+)
+
+var (
+	ErrInvalidSpec   = errors.New("invalid service client")
+	ErrInvalidClient = errors.New("invalid service client credentials")
+	ErrInvalidScope  = errors.New("scope not allowed for the service client")
+)
+
+// ClientIDPrefix marks the client IDs of service clients.
+const ClientIDPrefix = "svc_"
+
+// ScopeIntrospect lets a service client introspect every token, as resource
+// servers do. Without it a client only sees the tokens issued to itself.
+const ScopeIntrospect = "introspect"
+
+// scopePattern matches scopes such as orders:read.
+var scopePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]*$`)
+
+// Token is an access token issued to a service client.
+type Token struct {
+	AccessToken string
+	ExpiresIn   time.Duration
+	Scope       string
+}
+
+// Service manages service clients, the machine identities of other backend
+// services, and issues their access tokens. Client tokens have the client as
+// subject instead of a user and carry the granted scopes but no roles.
+type Service struct {
+	Repo Repository
+	JWT  *auth.JWT
+}
+
+func NewService(repo Repository, jwt *auth.JWT) *Service {
+	return &Service{Repo: repo, JWT: jwt}
+}
+
+// Register stores a new service client and returns it together with its
+// secret, which is not stored and cannot be shown again.
+func (s *Service) Register(ctx context.Context, name string, scopes []string) (*ent.ServiceClient, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrInvalidSpec)
+	}
+	for _, scope := range scopes {
+		if !scopePattern.MatchString(scope) {
+			return nil, "", fmt.Errorf("%w: invalid scope %q", ErrInvalidSpec, scope)
+		}
+	}
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, "", fmt.Errorf("failed to generate client id: %w", err)
+	}
+	clientID := ClientIDPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	secret, err := refreshtoken.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := s.Repo.Create(ctx, clientID, refreshtoken.Hash(secret), name, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+// List returns every service client.
+func (s *Service) List(ctx context.Context) ([]*ent.ServiceClient, error) {
+	return s.Repo.List(ctx)
+}
+
+// Delete removes a service client. Access tokens already issued to it stay
+// valid until they expire.
+func (s *Service) Delete(ctx context.Context, clientID string) error {
+	return s.Repo.Delete(ctx, clientID)
+}
+
+// RotateSecret replaces the secret of a service client and returns the new
+// one. The old secret stops working at once.
+func (s *Service) RotateSecret(ctx context.Context, clientID string) (string, error) {
+	secret, err := refreshtoken.Generate()
+	if err != nil {
+		return "", err
+	}
+	if err := s.Repo.UpdateSecret(ctx, clientID, refreshtoken.Hash(secret)); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// Token authenticates a service client and issues an access token for the
+// space-separated scope, which must be a subset of the client's scopes. An
+// empty scope grants all of them.
+func (s *Service) Token(ctx context.Context, clientID string, secret string, scope string) (*Token, error) {
+	client, err := s.Authenticate(ctx, clientID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, sc := range scopes {
+		if !slices.Contains(client.Scopes, sc) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, sc)
+		}
+	}
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	scopes = slices.Compact(scopes)
+	granted := strings.Join(scopes, " ")
+
+	accessToken, err := s.JWT.Generate(auth.Identity{
+		SubjectType: auth.SubjectClient,
+		ClientID:    client.ClientID,
+		Scope:       granted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.Touch(ctx, client.ID, time.Now()); err != nil {
+		log.Error("Failed to record service client use ", err.Error())
+	}
+
+	return &Token{AccessToken: accessToken, ExpiresIn: s.JWT.TTL(), Scope: granted}, nil
+}
+
+// Authenticate returns the service client clientID if secret is its secret.
+func (s *Service) Authenticate(ctx context.Context, clientID string, secret string) (*ent.ServiceClient, error) {
+	if clientID == "" || secret == "" {
+		return nil, ErrInvalidClient
+	}
+
+	client, err := s.Repo.Get(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(refreshtoken.Hash(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
@@ -0,0 +1,52 @@
+// Package testutil provides the fixtures shared by the service tests: an
+// in-memory database, a user service and the links in captured emails.
+package testutil
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"app/ent/enttest"
+	"app/internal/db"
+	"app/internal/mailer"
+	"app/internal/password"
+	"app/internal/user"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DB opens an in-memory SQLite database with the schema created. Each test
+// gets its own database, closed when the test ends.
+func DB(t *testing.T) *db.Db {
+	t.Helper()
+
+	client := enttest.Open(t, "sqlite3", "file:"+t.Name()+"?mode=memory&cache=shared&_fk=1")
+	t.Cleanup(func() { client.Close() })
+	return &db.Db{Client: client}
+}
+
+// Users returns a user service on d. Passwords are hashed with the lowest
+// bcrypt cost to keep tests fast.
+func Users(d *db.Db, cfg user.Config) *user.Service {
+	return user.NewSercie(user.NewPostgresRepo(d), password.NewHasher(password.NewBcrypt(4)), cfg)
+}
+
+// LinkToken returns the token query parameter of the link to base in the
+// body of msg.
+func LinkToken(t *testing.T, msg mailer.Message, base string) string {
+	t.Helper()
+
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if !strings.HasPrefix(line, base+"?") {
+			continue
+		}
+		u, err := url.Parse(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.Query().Get("token")
+	}
+	t.Fatalf("no link to %s in %q", base, msg.Body)
+	return ""
+}
@@ -3,46 +3,60 @@ package user
 import (
 	"encoding/json"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"app/domain"
+	"app/ent"
 	"app/internal/auth"
+	"app/internal/middleware"
+	"app/internal/password"
 	"app/internal/refreshtoken"
+	"app/internal/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/labstack/gommon/log"
 )
 
 type Handler struct {
 	Service        *Service
 	JWT            *auth.JWT
 	RefreshService *refreshtoken.Service
+	Verifier       *Verifier
+	MFA            *MFA
 }
 
-func NewHandler(s *Service, jwt *auth.JWT, refreshService *refreshtoken.Service) *Handler {
+func NewHandler(s *Service, jwt *auth.JWT, refreshService *refreshtoken.Service, verifier *Verifier, mfa *MFA) *Handler {
 	return &Handler{
 		Service:        s,
 		JWT:            jwt,
 		RefreshService: refreshService,
+		Verifier:       verifier,
+		MFA:            mfa,
 	}
 }
 
 // Register godoc
 // @Summary      Register a new user
-// @Description  Create a new user account with email and password
+// @Description  Create a new user account and send a verification email. If the email is already registered, its owner is notified instead. The response is the same in both cases and does not include tokens; log in afterwards.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body domain.RegisterDTO true "Registration credentials"
-// @Success      201 {object} domain.AuthResponse "Successfully registered"
-// @Failure      400 {object} domain.ErrorResponse "Invalid request body or validation error"
+// @Success      202 {object} domain.RegisterResponse "Registration accepted"
+// @Failure      400 {object} domain.PasswordPolicyErrorResponse "Invalid request body, validation error or password policy violation"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
 // @Router       /auth/register [post]
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var dto domain.RegisterDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		response.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if dto.Email == "" || dto.Password == "" {
-		respondWithError(w, http.StatusBadRequest, "email and password are required")
+		response.Error(w, http.StatusBadRequest, "email and password are required")
 		return
 	}
 
@@ -52,35 +66,30 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		dto.Password,
 		dto.Username,
 	)
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	accessToken, err := h.JWT.Generate(u.ID)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "failed to generate access token")
+	var policyErr *password.PolicyError
+	switch {
+	case err == nil:
+		if err := h.Verifier.Send(r.Context(), u); err != nil {
+			log.Error("Failed to send verification email ", err.Error())
+		}
+	case errors.Is(err, ErrEmailTaken):
+		if err := h.Verifier.SendAccountExists(r.Context(), dto.Email); err != nil {
+			log.Error("Failed to send account exists notice ", err.Error())
+		}
+	case errors.As(err, &policyErr):
+		response.JSON(w, http.StatusBadRequest, ToPolicyErrorResponse(policyErr))
 		return
-	}
-
-	refreshToken, err := h.RefreshService.Generate(r.Context(), u.ID)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "failed to generate refresh token")
+	default:
+		response.Error(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	resp := domain.AuthResponse{
-		User:         ToUserResponse(u),
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-	}
-
-	respondWithJSON(w, http.StatusCreated, resp)
+	response.JSON(w, http.StatusAccepted, domain.RegisterResponse{Message: "check your email to continue"})
 }
 
 // Login godoc
 // @Summary      Login user
-// @Description  Authenticate user with email and password
+// @Description  Authenticate user with email and password. Repeated failures delay further attempts (429) and lock the account (423); both carry a Retry-After header in seconds. With two-factor authentication enabled the response is a domain.MFAChallengeResponse instead, to be completed at /auth/mfa/verify.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -88,49 +97,82 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 // @Success      200 {object} domain.AuthResponse "Successfully logged in"
 // @Failure      400 {object} domain.ErrorResponse "Invalid request body"
 // @Failure      401 {object} domain.ErrorResponse "Invalid credentials"
+// @Failure      403 {object} domain.ErrorResponse "Email not verified"
+// @Failure      423 {object} domain.ErrorResponse "Account temporarily locked"
+// @Failure      429 {object} domain.ErrorResponse "Too many login attempts"
 // @Failure      500 {object} domain.ErrorResponse "Internal server error"
 // @Router       /auth/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var dto domain.LoginDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		response.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if dto.Email == "" || dto.Password == "" {
-		respondWithError(w, http.StatusBadRequest, "email and password are required")
+		response.Error(w, http.StatusBadRequest, "email and password are required")
 		return
 	}
 
-	u, err := h.Service.Login(r.Context(), dto.Email, dto.Password)
+	u, err := h.Service.Login(r.Context(), dto.Email, dto.Password, refreshtoken.ClientFromRequest(r).IP)
 	if err != nil {
+		var throttled *ThrottledError
+		if errors.As(err, &throttled) {
+			RespondThrottled(w, throttled)
+			return
+		}
 		if errors.Is(err, ErrInvalidCredentials) {
-			respondWithError(w, http.StatusUnauthorized, "invalid email or password")
+			response.Error(w, http.StatusUnauthorized, "invalid email or password")
+			return
+		}
+		if errors.Is(err, ErrEmailNotVerified) {
+			response.Error(w, http.StatusForbidden, "email is not verified")
 			return
 		}
-		respondWithError(w, http.StatusInternalServerError, "internal server error")
+		response.Error(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	accessToken, err := h.JWT.Generate(u.ID)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "failed to generate access token")
+	h.CompleteLogin(w, r, u)
+}
+
+// VerifyMFA godoc
+// @Summary      Complete login with a second factor
+// @Description  Exchange the challenge token from login and a TOTP code or an unused recovery code for the token pair. Failed codes count as failed logins.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.MFAVerifyDTO true "Challenge token and code"
+// @Success      200 {object} domain.AuthResponse "Successfully logged in"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Failure      401 {object} domain.ErrorResponse "Invalid or expired challenge, or invalid code"
+// @Failure      423 {object} domain.ErrorResponse "Account temporarily locked"
+// @Failure      429 {object} domain.ErrorResponse "Too many login attempts"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/mfa/verify [post]
+func (h *Handler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var dto domain.MFAVerifyDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	refreshToken, err := h.RefreshService.Generate(r.Context(), u.ID)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "failed to generate refresh token")
+	if dto.ChallengeToken == "" || dto.Code == "" {
+		response.Error(w, http.StatusBadRequest, "challenge_token and code are required")
 		return
 	}
 
-	resp := domain.AuthResponse{
-		User:         ToUserResponse(u),
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	u, err := h.MFA.Verify(r.Context(), dto.ChallengeToken, dto.Code, refreshtoken.ClientFromRequest(r).IP)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			response.Error(w, http.StatusUnauthorized, "invalid authentication code")
+			return
+		}
+		h.respondWithMFAError(w, err)
+		return
 	}
 
-	respondWithJSON(w, http.StatusOK, resp)
+	h.StartSession(w, r, u)
 }
 
 // Refresh godoc
@@ -142,52 +184,60 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 // @Param        request body domain.RefreshTokenDTO true "Refresh token"
 // @Success      200 {object} domain.AuthResponse "Successfully refreshed tokens"
 // @Failure      400 {object} domain.ErrorResponse "Invalid request body"
-// @Failure      401 {object} domain.ErrorResponse "Invalid or expired refresh token"
+// @Failure      401 {object} domain.ErrorResponse "Invalid or expired refresh token or session"
 // @Failure      500 {object} domain.ErrorResponse "Internal server error"
 // @Router       /auth/refresh [post]
 func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 	var dto domain.RefreshTokenDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		response.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if dto.RefreshToken == "" {
-		respondWithError(w, http.StatusBadRequest, "refresh token is required")
+		response.Error(w, http.StatusBadRequest, "refresh token is required")
 		return
 	}
 
-	newRefreshToken, userID, err := h.RefreshService.Rotate(r.Context(), dto.RefreshToken)
+	refreshToken, err := h.RefreshService.Rotate(r.Context(), dto.RefreshToken, refreshtoken.ClientFromRequest(r))
 	if err != nil {
 		if errors.Is(err, refreshtoken.ErrInvalidRefreshToken) ||
 			errors.Is(err, refreshtoken.ErrExpiredRefreshToken) ||
-			errors.Is(err, refreshtoken.ErrRevokedRefreshToken) {
-			respondWithError(w, http.StatusUnauthorized, "invalid or expired refresh token")
+			errors.Is(err, refreshtoken.ErrRevokedRefreshToken) ||
+			errors.Is(err, refreshtoken.ErrRefreshTokenReused) {
+			response.Error(w, http.StatusUnauthorized, "invalid or expired refresh token")
 			return
 		}
-		respondWithError(w, http.StatusInternalServerError, "internal server error")
+		if errors.Is(err, refreshtoken.ErrSessionLifetimeExceeded) ||
+			errors.Is(err, refreshtoken.ErrSessionIdleTimeout) {
+			response.Error(w, http.StatusUnauthorized, "session expired, please log in again")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	u, err := h.Service.GetByID(r.Context(), userID)
+	u, err := h.Service.GetByID(r.Context(), refreshToken.UserID)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "internal server error")
+		response.Error(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
-	accessToken, err := h.JWT.Generate(userID)
+	id := ToIdentity(u)
+	id.SessionID = refreshToken.SessionID
+	accessToken, err := h.JWT.Generate(id)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "failed to generate access token")
+		response.Error(w, http.StatusInternalServerError, "failed to generate access token")
 		return
 	}
 
 	resp := domain.AuthResponse{
 		User:         ToUserResponse(u),
 		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
+		RefreshToken: refreshToken.Token,
 	}
 
-	respondWithJSON(w, http.StatusOK, resp)
+	response.JSON(w, http.StatusOK, resp)
 }
 
 // Logout godoc
@@ -204,31 +254,514 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 	var dto domain.LogoutDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
-		respondWithError(w, http.StatusBadRequest, "invalid request body")
+		response.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if dto.RefreshToken == "" {
-		respondWithError(w, http.StatusBadRequest, "refresh token is required")
+		response.Error(w, http.StatusBadRequest, "refresh token is required")
 		return
 	}
 
 	if err := h.RefreshService.Revoke(r.Context(), dto.RefreshToken); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "failed to logout")
+		response.Error(w, http.StatusInternalServerError, "failed to logout")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func respondWithError(w http.ResponseWriter, code int, message string) {
-	respondWithJSON(w, code, map[string]string{"error": message})
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Confirm an email address with the token from the verification email. Each token works once.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.VerifyEmailDTO true "Verification token"
+// @Success      200 {object} domain.UserResponse "Email verified"
+// @Failure      400 {object} domain.ErrorResponse "Invalid or expired verification token"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/verify-email [post]
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var dto domain.VerifyEmailDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Token == "" {
+		response.Error(w, http.StatusBadRequest, "token is required")
+		return
+	}
+
+	u, err := h.Verifier.Verify(r.Context(), dto.Token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidVerificationToken) {
+			response.Error(w, http.StatusBadRequest, "invalid or expired verification token")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToUserResponse(u))
 }
 
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Send a new verification link if the address belongs to an unverified account. The response does not reveal whether it does.
+// @Tags         auth
+// @Accept       json
+// @Param        request body domain.ResendVerificationDTO true "Email address"
+// @Success      202 "Verification email sent if applicable"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /auth/verify-email/resend [post]
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var dto domain.ResendVerificationDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Email == "" {
+		response.Error(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	if err := h.Verifier.Resend(r.Context(), dto.Email); err != nil {
+		log.Error("Failed to resend verification email ", err.Error())
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ChangePassword godoc
+// @Summary      Change password
+// @Description  Change the password of the authenticated user. Every session is logged out and a new token pair is returned for the calling device.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.ChangePasswordDTO true "Current and new password"
+// @Success      200 {object} domain.AuthResponse "Password changed"
+// @Failure      400 {object} domain.PasswordPolicyErrorResponse "Invalid request body, wrong current password or password policy violation"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/password [post]
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dto domain.ChangePasswordDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.CurrentPassword == "" || dto.NewPassword == "" {
+		response.Error(w, http.StatusBadRequest, "current_password and new_password are required")
+		return
+	}
+
+	u, err := h.Service.ChangePassword(r.Context(), userID, dto.CurrentPassword, dto.NewPassword)
+	if err != nil {
+		var policyErr *password.PolicyError
+		switch {
+		case errors.Is(err, ErrInvalidCredentials):
+			response.Error(w, http.StatusBadRequest, "current password is incorrect")
+		case errors.As(err, &policyErr):
+			response.JSON(w, http.StatusBadRequest, ToPolicyErrorResponse(policyErr))
+		case errors.Is(err, ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "user not found")
+		default:
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	if err := h.RefreshService.RevokeAllForUser(r.Context(), u.ID); err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	h.StartSession(w, r, u)
+}
+
+// Me godoc
+// @Summary      Get current user
+// @Description  Return the profile of the authenticated user
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.UserResponse "Current user"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me [get]
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	u, err := h.Service.GetByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "user not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	response.JSON(w, http.StatusOK, ToUserResponse(u))
+}
+
+// Get godoc
+// @Summary      Get user
+// @Description  Return the profile of a user. For service clients granted the users:read scope.
+// @Tags         users
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "User ID"
+// @Success      200 {object} domain.UserResponse "User"
+// @Failure      400 {object} domain.ErrorResponse "Invalid user id"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      403 {object} domain.ErrorResponse "Forbidden or insufficient scope"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/{id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	u, err := h.Service.GetByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "user not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
 	}
+
+	response.JSON(w, http.StatusOK, ToUserResponse(u))
+}
+
+// UpdateMe godoc
+// @Summary      Update current user
+// @Description  Change the email and/or username of the authenticated user. A new email must be verified again.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.UpdateUserDTO true "Fields to change"
+// @Success      200 {object} domain.UserResponse "Updated user"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      409 {object} domain.ErrorResponse "Email already taken"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me [patch]
+func (h *Handler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var dto domain.UpdateUserDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if dto.Email == nil && dto.Username == nil {
+		response.Error(w, http.StatusBadRequest, "email or username is required")
+		return
+	}
+
+	u, err := h.Service.UpdateProfile(r.Context(), userID, dto.Email, dto.Username)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidEmail):
+			response.Error(w, http.StatusBadRequest, "invalid email")
+		case errors.Is(err, ErrEmailTaken):
+			response.Error(w, http.StatusConflict, "email is already taken")
+		case errors.Is(err, ErrUserNotFound):
+			response.Error(w, http.StatusNotFound, "user not found")
+		default:
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+		}
+		return
+	}
+
+	if dto.Email != nil && !u.EmailVerified {
+		if err := h.Verifier.Send(r.Context(), u); err != nil {
+			log.Error("Failed to send verification email ", err.Error())
+		}
+	}
+
+	response.JSON(w, http.StatusOK, ToUserResponse(u))
+}
+
+// DeleteMe godoc
+// @Summary      Delete current user
+// @Description  Permanently delete the authenticated user and all of their sessions
+// @Tags         users
+// @Security     BearerAuth
+// @Success      204 "Account deleted"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      404 {object} domain.ErrorResponse "User not found"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me [delete]
+func (h *Handler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if err := h.Service.Delete(r.Context(), userID); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			response.Error(w, http.StatusNotFound, "user not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnrollTOTP godoc
+// @Summary      Set up TOTP
+// @Description  Create a TOTP secret for the authenticated user. Add it to an authenticator app, e.g. by rendering otpauth_uri as a QR code, then confirm it with a code. Starting over replaces an unconfirmed secret.
+// @Tags         mfa
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.TOTPEnrollmentResponse "New secret"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      409 {object} domain.ErrorResponse "Two-factor authentication already enabled"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/mfa/totp [post]
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	e, err := h.MFA.Enroll(r.Context(), userID)
+	if err != nil {
+		h.respondWithMFAError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, domain.TOTPEnrollmentResponse{
+		Secret: e.Secret,
+		URI:    e.URI,
+	})
+}
+
+// ConfirmTOTP godoc
+// @Summary      Confirm TOTP
+// @Description  Enable two-factor authentication with a code from the authenticator app. Returns recovery codes, which are shown only this once.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.MFACodeDTO true "TOTP code"
+// @Success      200 {object} domain.RecoveryCodesResponse "Two-factor authentication enabled"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body, invalid code or no secret to confirm"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      409 {object} domain.ErrorResponse "Two-factor authentication already enabled"
+// @Failure      429 {object} domain.ErrorResponse "Too many attempts"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/mfa/totp/confirm [post]
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, dto, ok := h.decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.MFA.Confirm(r.Context(), userID, dto.Code, refreshtoken.ClientFromRequest(r).IP)
+	if err != nil {
+		h.respondWithMFAError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, domain.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTOTP godoc
+// @Summary      Disable TOTP
+// @Description  Turn off two-factor authentication with a current TOTP code or a recovery code. Remaining recovery codes are deleted.
+// @Tags         mfa
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body domain.MFACodeDTO true "TOTP or recovery code"
+// @Success      204 "Two-factor authentication disabled"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body, invalid code or not enabled"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      429 {object} domain.ErrorResponse "Too many attempts"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/mfa/totp [delete]
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, dto, ok := h.decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.MFA.Disable(r.Context(), userID, dto.Code, refreshtoken.ClientFromRequest(r).IP); err != nil {
+		h.respondWithMFAError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replace all recovery codes after checking a current TOTP code or a recovery code. The new codes are shown only this once.
+// @Tags         mfa
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.MFACodeDTO true "TOTP or recovery code"
+// @Success      200 {object} domain.RecoveryCodesResponse "New recovery codes"
+// @Failure      400 {object} domain.ErrorResponse "Invalid request body, invalid code or not enabled"
+// @Failure      401 {object} domain.ErrorResponse "Unauthorized"
+// @Failure      429 {object} domain.ErrorResponse "Too many attempts"
+// @Failure      500 {object} domain.ErrorResponse "Internal server error"
+// @Router       /users/me/mfa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, dto, ok := h.decodeMFACode(w, r)
+	if !ok {
+		return
+	}
+
+	codes, err := h.MFA.RegenerateRecoveryCodes(r.Context(), userID, dto.Code, refreshtoken.ClientFromRequest(r).IP)
+	if err != nil {
+		h.respondWithMFAError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, domain.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// decodeMFACode reads the authenticated user and the code of an MFA
+// management request, writing an error response if either is missing.
+func (h *Handler) decodeMFACode(w http.ResponseWriter, r *http.Request) (int, domain.MFACodeDTO, bool) {
+	var dto domain.MFACodeDTO
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return 0, dto, false
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return 0, dto, false
+	}
+
+	if dto.Code == "" {
+		response.Error(w, http.StatusBadRequest, "code is required")
+		return 0, dto, false
+	}
+
+	return userID, dto, true
+}
+
+func (h *Handler) respondWithMFAError(w http.ResponseWriter, err error) {
+	var throttled *ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		RespondThrottled(w, throttled)
+	case errors.Is(err, ErrInvalidMFAChallenge):
+		response.Error(w, http.StatusUnauthorized, "invalid or expired mfa challenge")
+	case errors.Is(err, ErrInvalidMFACode):
+		response.Error(w, http.StatusBadRequest, "invalid authentication code")
+	case errors.Is(err, ErrMFAAlreadyEnabled):
+		response.Error(w, http.StatusConflict, "two-factor authentication is already enabled")
+	case errors.Is(err, ErrMFANotEnrolled):
+		response.Error(w, http.StatusBadRequest, "two-factor authentication has not been set up")
+	case errors.Is(err, ErrMFANotEnabled):
+		response.Error(w, http.StatusBadRequest, "two-factor authentication is not enabled")
+	case errors.Is(err, ErrUserNotFound):
+		response.Error(w, http.StatusNotFound, "user not found")
+	default:
+		response.Error(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// RespondThrottled rejects a throttled attempt with 423 for a locked account
+// and 429 otherwise, telling the client when to retry.
+func RespondThrottled(w http.ResponseWriter, throttled *ThrottledError) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	if throttled.Locked {
+		response.Error(w, http.StatusLocked, "account is temporarily locked, try again later")
+		return
+	}
+	response.Error(w, http.StatusTooManyRequests, "too many login attempts, try again later")
+}
+
+// CompleteLogin finishes a login whose first factor has been checked. With
+// two-factor authentication enabled it responds with an MFA challenge,
+// otherwise it starts the session.
+func (h *Handler) CompleteLogin(w http.ResponseWriter, r *http.Request, u *ent.User) {
+	if u.TotpEnabled {
+		challenge, err := h.MFA.Challenge(u)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		response.JSON(w, http.StatusOK, domain.MFAChallengeResponse{
+			MFARequired:    true,
+			ChallengeToken: challenge,
+			ExpiresIn:      int(h.MFA.Config.ChallengeTTL.Seconds()),
+		})
+		return
+	}
+
+	h.StartSession(w, r, u)
+}
+
+// StartSession issues a refresh token for a new session on the requesting
+// device plus an access token bound to it, and writes them. Other ways of
+// logging in end here too, so that every login gets the same response.
+func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request, u *ent.User) {
+	refreshToken, err := h.RefreshService.Generate(r.Context(), u.ID, refreshtoken.ClientFromRequest(r))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to generate refresh token")
+		return
+	}
+
+	id := ToIdentity(u)
+	id.SessionID = refreshToken.SessionID
+	accessToken, err := h.JWT.Generate(id)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to generate access token")
+		return
+	}
+
+	resp := domain.AuthResponse{
+		User:         ToUserResponse(u),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken.Token,
+	}
+
+	response.JSON(w, http.StatusOK, resp)
 }
@@ -3,12 +3,49 @@ package user
 import (
 	"app/domain"
 	"app/ent"
+	"app/internal/auth"
+	"app/internal/password"
 )
 
 func ToUserResponse(u *ent.User) domain.UserResponse {
 	return domain.UserResponse{
-		ID:       u.ID,
-		Email:    u.Email,
-		Username: u.Username,
+		ID:            u.ID,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Username:      u.Username,
+		MFAEnabled:    u.TotpEnabled,
 	}
 }
+
+// ToIdentity collects the user's roles and the union of their permissions.
+// The roles edge must have been loaded with WithRoles.
+func ToIdentity(u *ent.User) auth.Identity {
+	id := auth.Identity{UserID: u.ID}
+
+	seen := make(map[string]bool)
+	for _, r := range u.Edges.Roles {
+		id.Roles = append(id.Roles, r.Name)
+		for _, p := range r.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				id.Permissions = append(id.Permissions, p)
+			}
+		}
+	}
+
+	return id
+}
+
+func ToPolicyErrorResponse(err *password.PolicyError) domain.PasswordPolicyErrorResponse {
+	resp := domain.PasswordPolicyErrorResponse{
+		Error:      "password does not meet the policy",
+		Violations: make([]domain.PasswordPolicyViolation, len(err.Violations)),
+	}
+	for i, v := range err.Violations {
+		resp.Violations[i] = domain.PasswordPolicyViolation{
+			Rule:    v.Rule,
+			Message: v.Message,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,281 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/refreshtoken"
+	"app/internal/totp"
+
+	"github.com/labstack/gommon/log"
+)
+
+// PurposeMFAChallenge is the purpose of the token that carries a login from
+// the password step to the second factor.
+const PurposeMFAChallenge = "mfa_challenge"
+
+// RecoveryCodeCount is how many recovery codes are issued at a time.
+const RecoveryCodeCount = 10
+
+var (
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled      = errors.New("two-factor authentication has not been set up")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrInvalidMFACode      = errors.New("invalid authentication code")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired mfa challenge")
+)
+
+// MFAConfig controls TOTP enrollment and login challenges.
+type MFAConfig struct {
+	// Issuer names the account in authenticator apps.
+	Issuer string
+	// ChallengeTTL is how long the second step of a login may take.
+	ChallengeTTL time.Duration
+}
+
+// Enrollment is a new TOTP secret waiting for confirmation.
+type Enrollment struct {
+	Secret string
+	URI    string
+}
+
+// MFA manages TOTP two-factor authentication. A user enrolls, confirms with
+// a first code and receives single-use recovery codes, which are stored only
+// as SHA-256 hashes. Logins of enrolled users stop after the password with a
+// challenge token that must be completed with a code. Failed codes count as
+// failed logins.
+type MFA struct {
+	Repo     Repository
+	JWT      *auth.JWT
+	Throttle LoginThrottle
+	Config   MFAConfig
+}
+
+func NewMFA(repo Repository, jwt *auth.JWT, throttle LoginThrottle, cfg MFAConfig) *MFA {
+	return &MFA{
+		Repo:     repo,
+		JWT:      jwt,
+		Throttle: throttle,
+		Config:   cfg,
+	}
+}
+
+// Enroll creates a TOTP secret for the user, replacing any unconfirmed one.
+func (m *MFA) Enroll(ctx context.Context, id int) (*Enrollment, error) {
+	u, err := m.Repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.TotpEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Repo.SetTOTPSecret(ctx, id, secret); err != nil {
+		return nil, err
+	}
+
+	return &Enrollment{
+		Secret: secret,
+		URI:    totp.URI(m.Config.Issuer, u.Email, secret),
+	}, nil
+}
+
+// Confirm enables TOTP once code proves that the authenticator app has the
+// secret, and returns the recovery codes.
+func (m *MFA) Confirm(ctx context.Context, id int, code string, ip string) ([]string, error) {
+	u, err := m.Repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.TotpEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if u.TotpSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	if err := m.check(ctx, u.Email, ip); err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(u.TotpSecret, code, time.Now())
+	if !ok {
+		m.failed(ctx, u.Email, ip)
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Repo.EnableTOTP(ctx, id, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns TOTP off after checking a current code or recovery code.
+func (m *MFA) Disable(ctx context.Context, id int, code string, ip string) error {
+	u, err := m.enabled(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := m.verifyCode(ctx, u, code, ip); err != nil {
+		return err
+	}
+	return m.Repo.DisableTOTP(ctx, id)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a
+// current code or recovery code.
+func (m *MFA) RegenerateRecoveryCodes(ctx context.Context, id int, code string, ip string) ([]string, error) {
+	u, err := m.enabled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verifyCode(ctx, u, code, ip); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Repo.ReplaceRecoveryCodes(ctx, id, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Challenge issues the token a user who passed the password step presents
+// with their code. It is bound to the last used TOTP step, so completing
+// the login with a TOTP code invalidates every outstanding challenge.
+func (m *MFA) Challenge(u *ent.User) (string, error) {
+	return m.JWT.GenerateAction(PurposeMFAChallenge, strconv.Itoa(u.ID), strconv.FormatInt(u.TotpLastStep, 10), m.Config.ChallengeTTL)
+}
+
+// Verify completes a login started with Challenge and returns the user.
+func (m *MFA) Verify(ctx context.Context, challenge string, code string, ip string) (*ent.User, error) {
+	claims, err := m.JWT.ParseAction(challenge, PurposeMFAChallenge)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	u, err := m.Repo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+	if !u.TotpEnabled || claims.Binding != strconv.FormatInt(u.TotpLastStep, 10) {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := m.verifyCode(ctx, u, code, ip); err != nil {
+		return nil, err
+	}
+
+	if m.Throttle != nil {
+		if err := m.Throttle.Succeeded(ctx, u.Email); err != nil {
+			log.Error("Failed to reset login failures ", err.Error())
+		}
+	}
+	return u, nil
+}
+
+func (m *MFA) enabled(ctx context.Context, id int) (*ent.User, error) {
+	u, err := m.Repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !u.TotpEnabled {
+		return nil, ErrMFANotEnabled
+	}
+	return u, nil
+}
+
+// verifyCode accepts a TOTP code of the current time, or else an unused
+// recovery code, and uses it up.
+func (m *MFA) verifyCode(ctx context.Context, u *ent.User, code string, ip string) error {
+	if err := m.check(ctx, u.Email, ip); err != nil {
+		return err
+	}
+
+	ok, err := m.useCode(ctx, u, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		m.failed(ctx, u.Email, ip)
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (m *MFA) useCode(ctx context.Context, u *ent.User, code string) (bool, error) {
+	code = normalizeCode(code)
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(u.TotpSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return m.Repo.UseTOTPStep(ctx, u.ID, step)
+	}
+
+	return m.Repo.UseRecoveryCode(ctx, u.ID, refreshtoken.Hash(code))
+}
+
+func (m *MFA) check(ctx context.Context, email string, ip string) error {
+	if m.Throttle == nil {
+		return nil
+	}
+	return m.Throttle.Check(ctx, email, ip)
+}
+
+func (m *MFA) failed(ctx context.Context, email string, ip string) {
+	if m.Throttle == nil {
+		return
+	}
+	if err := m.Throttle.Failed(ctx, email, ip); err != nil {
+		log.Error("Failed to record failed login ", err.Error())
+	}
+}
+
+// newRecoveryCodes returns fresh recovery codes formatted for display, and
+// their hashes for storage.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = refreshtoken.Hash(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeCode strips the formatting users may type along with a code.
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
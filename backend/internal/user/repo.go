@@ -3,18 +3,35 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"app/ent"
+	"app/ent/recoverycode"
 	"app/ent/user"
 	"app/internal/db"
 )
 
-var ErrUserNotFound = errors.New("User not found")
+var (
+	ErrUserNotFound = errors.New("User not found")
+	ErrEmailTaken   = errors.New("email is already taken")
+)
 
 type Repository interface {
 	Create(ctx context.Context, emailDto string, passwordHash string, username string) (*ent.User, error)
 	GetByEmail(ctx context.Context, emailDto string) (*ent.User, error)
 	GetById(ctx context.Context, id int) (*ent.User, error)
+	Update(ctx context.Context, id int, email *string, username *string) (*ent.User, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	ReplacePassword(ctx context.Context, id int, oldHash string, newHash string) (bool, error)
+	MarkEmailVerified(ctx context.Context, id int, email string) (bool, error)
+	SetTOTPSecret(ctx context.Context, id int, secret string) error
+	EnableTOTP(ctx context.Context, id int, step int64, recoveryCodeHashes []string) error
+	UseTOTPStep(ctx context.Context, id int, step int64) (bool, error)
+	DisableTOTP(ctx context.Context, id int) error
+	ReplaceRecoveryCodes(ctx context.Context, id int, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, id int, codeHash string) (bool, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type PostgresRepo struct {
@@ -22,11 +39,18 @@ type PostgresRepo struct {
 }
 
 func (p *PostgresRepo) Create(ctx context.Context, emailDto string, passwordHash string, username string) (*ent.User, error) {
-	return p.Db.Client.User.Create().SetEmail(emailDto).SetPassword(passwordHash).SetNillableUsername(&username).Save(ctx)
+	u, err := p.Db.Client.User.Create().SetEmail(emailDto).SetPassword(passwordHash).SetNillableUsername(&username).Save(ctx)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+	return u, nil
 }
 
 func (p *PostgresRepo) GetByEmail(ctx context.Context, emailDto string) (*ent.User, error) {
-	u, err := p.Db.Client.User.Query().Where(user.EmailEQ(emailDto)).Only(ctx)
+	u, err := p.Db.Client.User.Query().Where(user.EmailEQ(emailDto)).WithRoles().Only(ctx)
 	if err != nil {
 		if ent.IsNotFound(err) {
 			return nil, ErrUserNotFound
@@ -38,7 +62,7 @@ func (p *PostgresRepo) GetByEmail(ctx context.Context, emailDto string) (*ent.Us
 }
 
 func (p *PostgresRepo) GetById(ctx context.Context, id int) (*ent.User, error) {
-	u, err := p.Db.Client.User.Get(ctx, id)
+	u, err := p.Db.Client.User.Query().Where(user.ID(id)).WithRoles().Only(ctx)
 	if err != nil {
 		if ent.IsNotFound(err) {
 			return nil, ErrUserNotFound
@@ -49,6 +73,211 @@ func (p *PostgresRepo) GetById(ctx context.Context, id int) (*ent.User, error) {
 	return u, nil
 }
 
+// Update changes the given fields. A new email address starts out unverified.
+func (p *PostgresRepo) Update(ctx context.Context, id int, email *string, username *string) (*ent.User, error) {
+	update := p.Db.Client.User.UpdateOneID(id).
+		SetNillableUsername(username)
+	if email != nil {
+		update.SetEmail(*email).
+			SetEmailVerified(false).
+			ClearEmailVerifiedAt()
+	}
+
+	u, err := update.Save(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrUserNotFound
+		}
+		if ent.IsConstraintError(err) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (p *PostgresRepo) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	err := p.Db.Client.User.UpdateOneID(id).
+		SetPassword(passwordHash).
+		Exec(ctx)
+	if ent.IsNotFound(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// ReplacePassword swaps the password hash only if it is still oldHash. It
+// reports false otherwise, so an upgraded hash never overwrites a password
+// changed in the meantime.
+func (p *PostgresRepo) ReplacePassword(ctx context.Context, id int, oldHash string, newHash string) (bool, error) {
+	n, err := p.Db.Client.User.Update().
+		Where(
+			user.ID(id),
+			user.PasswordEQ(oldHash),
+		).
+		SetPassword(newHash).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// MarkEmailVerified verifies the user's email only if it is still the given
+// address and not yet verified. It reports false otherwise, so a verification
+// token can succeed only once.
+func (p *PostgresRepo) MarkEmailVerified(ctx context.Context, id int, email string) (bool, error) {
+	n, err := p.Db.Client.User.Update().
+		Where(
+			user.ID(id),
+			user.EmailEQ(email),
+			user.EmailVerifiedEQ(false),
+		).
+		SetEmailVerified(true).
+		SetEmailVerifiedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// SetTOTPSecret stores a new, not yet confirmed TOTP secret.
+func (p *PostgresRepo) SetTOTPSecret(ctx context.Context, id int, secret string) error {
+	err := p.Db.Client.User.UpdateOneID(id).
+		SetTotpSecret(secret).
+		SetTotpEnabled(false).
+		SetTotpLastStep(0).
+		Exec(ctx)
+	if ent.IsNotFound(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// EnableTOTP turns on the stored TOTP secret, records the step of the code
+// that confirmed it and replaces the recovery codes, in one transaction.
+func (p *PostgresRepo) EnableTOTP(ctx context.Context, id int, step int64, recoveryCodeHashes []string) error {
+	tx, err := p.Db.Client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.User.UpdateOneID(id).
+		SetTotpEnabled(true).
+		SetTotpLastStep(step).
+		Exec(ctx)
+	if err == nil {
+		err = replaceRecoveryCodes(ctx, tx.Client(), id, recoveryCodeHashes)
+	}
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("%w: rolling back transaction: %v", err, rerr)
+		}
+		if ent.IsNotFound(err) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep records step as used only if it is later than the last used
+// one. It reports false otherwise, so each code is accepted once even by
+// concurrent requests.
+func (p *PostgresRepo) UseTOTPStep(ctx context.Context, id int, step int64) (bool, error) {
+	n, err := p.Db.Client.User.Update().
+		Where(
+			user.ID(id),
+			user.TotpEnabledEQ(true),
+			user.TotpLastStepLT(step),
+		).
+		SetTotpLastStep(step).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// DisableTOTP removes the TOTP secret and the recovery codes, in one
+// transaction.
+func (p *PostgresRepo) DisableTOTP(ctx context.Context, id int) error {
+	tx, err := p.Db.Client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = tx.User.UpdateOneID(id).
+		ClearTotpSecret().
+		SetTotpEnabled(false).
+		SetTotpLastStep(0).
+		Exec(ctx)
+	if err == nil {
+		err = replaceRecoveryCodes(ctx, tx.Client(), id, nil)
+	}
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("%w: rolling back transaction: %v", err, rerr)
+		}
+		if ent.IsNotFound(err) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and stores new ones.
+func (p *PostgresRepo) ReplaceRecoveryCodes(ctx context.Context, id int, codeHashes []string) error {
+	return replaceRecoveryCodes(ctx, p.Db.Client, id, codeHashes)
+}
+
+func replaceRecoveryCodes(ctx context.Context, client *ent.Client, id int, codeHashes []string) error {
+	if _, err := client.RecoveryCode.Delete().Where(recoverycode.UserIDEQ(id)).Exec(ctx); err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	builders := make([]*ent.RecoveryCodeCreate, len(codeHashes))
+	for i, hash := range codeHashes {
+		builders[i] = client.RecoveryCode.Create().SetUserID(id).SetCodeHash(hash)
+	}
+	return client.RecoveryCode.CreateBulk(builders...).Exec(ctx)
+}
+
+// UseRecoveryCode marks the user's recovery code as used if it is unused.
+// It reports false for unknown or used codes.
+func (p *PostgresRepo) UseRecoveryCode(ctx context.Context, id int, codeHash string) (bool, error) {
+	n, err := p.Db.Client.RecoveryCode.Update().
+		Where(
+			recoverycode.UserIDEQ(id),
+			recoverycode.CodeHashEQ(codeHash),
+			recoverycode.UsedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Delete removes the user; refresh tokens are removed by the ON DELETE CASCADE
+// foreign key.
+func (p *PostgresRepo) Delete(ctx context.Context, id int) error {
+	err := p.Db.Client.User.DeleteOneID(id).Exec(ctx)
+	if ent.IsNotFound(err) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
 func NewPostgresRepo(db *db.Db) Repository {
 	return &PostgresRepo{Db: db}
 }
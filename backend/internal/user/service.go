@@ -2,32 +2,106 @@ package user
 
 import (
 	"app/ent"
+	"app/internal/refreshtoken"
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/labstack/gommon/log"
 )
 
+// ScopeUsersRead is the scope a service client needs to look up users, and
+// an API key to read the profile of its user.
+const ScopeUsersRead = "users:read"
+
 var (
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrInvalidPassword    = errors.New("invalid password")
+	ErrInvalidEmail       = errors.New("invalid email")
+	ErrEmailNotVerified   = errors.New("email is not verified")
 )
 
+// Config controls account policies.
+type Config struct {
+	// RequireVerifiedEmail rejects logins until the email is verified.
+	RequireVerifiedEmail bool
+	// PasswordPolicy validates new passwords. Nil accepts any password.
+	PasswordPolicy PasswordPolicy
+	// LoginThrottle limits failed logins. Nil allows unlimited attempts.
+	LoginThrottle LoginThrottle
+}
+
+// LoginThrottle tracks failed logins per account email and client IP. It
+// must treat unknown emails like existing ones so that throttling does not
+// reveal which accounts exist.
+type LoginThrottle interface {
+	// Check returns a *ThrottledError if no login for email from ip may be
+	// attempted now.
+	Check(ctx context.Context, email string, ip string) error
+	Failed(ctx context.Context, email string, ip string) error
+	Succeeded(ctx context.Context, email string) error
+}
+
+// ThrottledError rejects a login attempt before the password is checked.
+type ThrottledError struct {
+	// Locked is set when the account is locked out, as opposed to the
+	// attempt being delayed or the IP being blocked.
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("account locked, retry after %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("too many login attempts, retry after %s", e.RetryAfter)
+}
+
+// PasswordPolicy decides whether a new password is acceptable. Personal
+// values such as the email address must not be usable as the password.
+type PasswordPolicy interface {
+	Check(password string, personal ...string) error
+}
+
+// PasswordHasher hashes passwords into self-describing strings and verifies
+// them, including hashes made with older algorithms or parameters.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (bool, error)
+	// NeedsRehash reports whether encoded should be replaced by a hash with
+	// the current algorithm and parameters.
+	NeedsRehash(encoded string) bool
+}
+
 type Service struct {
-	Repo Repository
+	Repo   Repository
+	Hasher PasswordHasher
+	Config Config
+
+	dummyOnce sync.Once
+	dummyHash string
 }
 
-func NewSercie(repo Repository) *Service {
-	return &Service{Repo: repo}
+func NewSercie(repo Repository, hasher PasswordHasher, cfg Config) *Service {
+	return &Service{Repo: repo, Hasher: hasher, Config: cfg}
 }
 
+// Register creates an account. A taken email fails with ErrEmailTaken only
+// after the password has been hashed, so both outcomes take equally long.
 func (s *Service) Register(ctx context.Context, email string, password string, username string) (*ent.User, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := s.validatePassword(password, email, username); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.Hasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := s.Repo.Create(ctx, email, string(hash), username)
+	u, err := s.Repo.Create(ctx, email, hash, username)
 	if err != nil {
 		return nil, err
 	}
@@ -35,17 +109,64 @@ func (s *Service) Register(ctx context.Context, email string, password string, u
 	return u, nil
 }
 
-func (s *Service) Login(ctx context.Context, email string, password string) (*ent.User, error) {
+// RegisterPasswordless creates an account for email without a usable
+// password, for users who sign in by email only. They can still choose a
+// password through a password reset.
+func (s *Service) RegisterPasswordless(ctx context.Context, email string) (*ent.User, error) {
+	secret, err := refreshtoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := s.Hasher.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.Create(ctx, email, hash, "")
+}
+
+// Login checks the credentials of a client at ip. A password hash made with
+// an outdated algorithm or cost is replaced in the background once the
+// password has been verified, so that the response takes no longer than for
+// an unknown account. Throttled attempts fail with a *ThrottledError.
+func (s *Service) Login(ctx context.Context, email string, password string, ip string) (*ent.User, error) {
+	if s.Config.LoginThrottle != nil {
+		if err := s.Config.LoginThrottle.Check(ctx, email, ip); err != nil {
+			return nil, err
+		}
+	}
+
 	u, err := s.Repo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
+			// Spend the time a real comparison would take, so the response
+			// time does not reveal that the account does not exist.
+			s.Hasher.Verify(password, s.dummy())
+			s.loginFailed(ctx, email, ip)
 			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
-		return nil, ErrInvalidCredentials
+	if err := s.checkPassword(u, password); err != nil {
+		s.loginFailed(ctx, email, ip)
+		return nil, err
+	}
+	if s.Hasher.NeedsRehash(u.Password) {
+		go s.upgradePassword(context.WithoutCancel(ctx), u.ID, u.Password, password)
+	}
+
+	// With two-factor authentication the failures are cleared once the
+	// second factor has been verified.
+	if s.Config.LoginThrottle != nil && !u.TotpEnabled {
+		if err := s.Config.LoginThrottle.Succeeded(ctx, email); err != nil {
+			log.Error("Failed to reset login failures ", err.Error())
+		}
+	}
+
+	if s.Config.RequireVerifiedEmail && !u.EmailVerified {
+		return nil, ErrEmailNotVerified
 	}
 
 	return u, nil
@@ -58,3 +179,130 @@ func (s *Service) GetByEmail(ctx context.Context, email string) (*ent.User, erro
 func (s *Service) GetByID(ctx context.Context, id int) (*ent.User, error) {
 	return s.Repo.GetById(ctx, id)
 }
+
+// UpdateProfile changes the email and/or username of a user. Nil values are
+// left unchanged. Changing the email resets its verification.
+func (s *Service) UpdateProfile(ctx context.Context, id int, email *string, username *string) (*ent.User, error) {
+	if email != nil {
+		trimmed := strings.TrimSpace(*email)
+		if trimmed == "" || !strings.Contains(trimmed, "@") {
+			return nil, ErrInvalidEmail
+		}
+		email = &trimmed
+
+		u, err := s.Repo.GetById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if u.Email == trimmed {
+			email = nil
+		}
+	}
+
+	return s.Repo.Update(ctx, id, email, username)
+}
+
+// SetPassword replaces the password of a user. Callers are responsible for
+// ending existing sessions.
+func (s *Service) SetPassword(ctx context.Context, id int, password string) error {
+	if err := s.ValidateNewPassword(ctx, id, password); err != nil {
+		return err
+	}
+
+	hash, err := s.Hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return s.Repo.UpdatePassword(ctx, id, hash)
+}
+
+// ChangePassword replaces the password after checking the current one and
+// returns the user. Callers are responsible for ending existing sessions.
+func (s *Service) ChangePassword(ctx context.Context, id int, current string, password string) (*ent.User, error) {
+	u, err := s.Repo.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPassword(u, current); err != nil {
+		return nil, err
+	}
+
+	if err := s.SetPassword(ctx, id, password); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id int) error {
+	return s.Repo.Delete(ctx, id)
+}
+
+// ValidateNewPassword checks password against the password policy for the
+// given user.
+func (s *Service) ValidateNewPassword(ctx context.Context, id int, password string) error {
+	u, err := s.Repo.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.validatePassword(password, u.Email, u.Username)
+}
+
+func (s *Service) validatePassword(password string, personal ...string) error {
+	if s.Config.PasswordPolicy == nil {
+		return nil
+	}
+	return s.Config.PasswordPolicy.Check(password, personal...)
+}
+
+// dummy returns a hash made with the current algorithm and parameters to
+// compare against when there is no account.
+func (s *Service) dummy() string {
+	s.dummyOnce.Do(func() {
+		hash, err := s.Hasher.Hash("dummy password for unknown accounts")
+		if err != nil {
+			log.Error("Failed to create dummy password hash ", err.Error())
+		}
+		s.dummyHash = hash
+	})
+	return s.dummyHash
+}
+
+// loginFailed records a failed login. Errors are only logged so that the
+// caller still gets the credentials error.
+func (s *Service) loginFailed(ctx context.Context, email string, ip string) {
+	if s.Config.LoginThrottle == nil {
+		return
+	}
+	if err := s.Config.LoginThrottle.Failed(ctx, email, ip); err != nil {
+		log.Error("Failed to record failed login ", err.Error())
+	}
+}
+
+// checkPassword verifies password against the stored hash of u.
+func (s *Service) checkPassword(u *ent.User, password string) error {
+	ok, err := s.Hasher.Verify(password, u.Password)
+	if err != nil {
+		log.Error("Failed to verify password hash ", err.Error())
+		return ErrInvalidCredentials
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// upgradePassword replaces the hash old of a verified password with one made
+// with the current algorithm and parameters, unless the password has been
+// changed since. Errors are only logged, the old hash still verifies.
+func (s *Service) upgradePassword(ctx context.Context, id int, old string, password string) {
+	hash, err := s.Hasher.Hash(password)
+	if err == nil {
+		_, err = s.Repo.ReplacePassword(ctx, id, old, hash)
+	}
+	if err != nil {
+		log.Error("Failed to upgrade password hash ", err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/password"
+	"app/internal/testutil"
+	"app/internal/user"
+)
+
+// blockingHasher holds back new hashes until release is closed and announces
+// each one on hashing.
+type blockingHasher struct {
+	*password.Hasher
+	hashing chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHasher) Hash(password string) (string, error) {
+	h.hashing <- struct{}{}
+	<-h.release
+	return h.Hasher.Hash(password)
+}
+
+func TestLoginUpgradesHashInBackground(t *testing.T) {
+	d := testutil.DB(t)
+	ctx := context.Background()
+
+	// Registered with bcrypt cost 4, logging in with cost 5 preferred.
+	u, err := testutil.Users(d, user.Config{}).Register(ctx, "alice@example.com", "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher := &blockingHasher{
+		Hasher:  password.NewHasher(password.NewBcrypt(5)),
+		hashing: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	users := user.NewSercie(user.NewPostgresRepo(d), hasher, user.Config{})
+
+	if _, err := users.Login(ctx, "alice@example.com", "password123", "127.0.0.1"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	// Login has returned while the new hash is still being made.
+	select {
+	case <-hasher.hashing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("password hash not upgraded")
+	}
+	close(hasher.release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := users.GetByID(ctx, u.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Password != u.Password {
+			if hasher.NeedsRehash(got.Password) {
+				t.Fatalf("upgraded hash %q still needs a rehash", got.Password)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("password hash not replaced")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := users.Login(ctx, "alice@example.com", "password123", "127.0.0.1"); err != nil {
+		t.Fatalf("Login with the upgraded hash: %v", err)
+	}
+}
+
+func TestReplacePasswordKeepsChangedPassword(t *testing.T) {
+	d := testutil.DB(t)
+	ctx := context.Background()
+	users := testutil.Users(d, user.Config{})
+
+	u, err := users.Register(ctx, "alice@example.com", "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.SetPassword(ctx, u.ID, "another password"); err != nil {
+		t.Fatal(err)
+	}
+
+	// An upgrade of the hash verified before the change comes too late.
+	replaced, err := users.Repo.ReplacePassword(ctx, u.ID, u.Password, "upgraded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaced {
+		t.Fatal("stale upgrade replaced the changed password")
+	}
+	if _, err := users.Login(ctx, "alice@example.com", "another password", "127.0.0.1"); err != nil {
+		t.Fatalf("Login with the changed password: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"app/ent"
+	"app/internal/auth"
+	"app/internal/mailer"
+)
+
+// PurposeVerifyEmail is the purpose of email verification action tokens.
+const PurposeVerifyEmail = "verify_email"
+
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+// VerifierConfig controls verification links.
+type VerifierConfig struct {
+	// TTL is how long a verification link stays valid.
+	TTL time.Duration
+	// LinkBaseURL is the frontend URL the link points at; the token is
+	// appended as the token query parameter.
+	LinkBaseURL string
+}
+
+// Verifier sends email verification links and confirms them. Links carry a
+// signed action token bound to the address being verified, so each one works
+// once and stops working when the address changes.
+type Verifier struct {
+	Repo   Repository
+	JWT    *auth.JWT
+	Mailer mailer.Mailer
+	Config VerifierConfig
+}
+
+func NewVerifier(repo Repository, jwt *auth.JWT, m mailer.Mailer, cfg VerifierConfig) *Verifier {
+	return &Verifier{
+		Repo:   repo,
+		JWT:    jwt,
+		Mailer: m,
+		Config: cfg,
+	}
+}
+
+// Send mails a verification link for the user's current address.
+func (v *Verifier) Send(ctx context.Context, u *ent.User) error {
+	token, err := v.JWT.GenerateAction(PurposeVerifyEmail, strconv.Itoa(u.ID), u.Email, v.Config.TTL)
+	if err != nil {
+		return err
+	}
+
+	link, err := v.link(token)
+	if err != nil {
+		return err
+	}
+
+	return v.Mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not create an account, ignore this email.\n",
+			link, v.Config.TTL,
+		),
+	})
+}
+
+// SendAccountExists tells the owner of email that someone tried to register
+// it again. Registration responds the same either way, so this mail is how a
+// returning user learns that they already have an account.
+func (v *Verifier) SendAccountExists(ctx context.Context, email string) error {
+	return v.Mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "You already have an account",
+		Body:    "Someone, hopefully you, tried to create an account with this email address, but it is already registered.\n\nSign in with your password, or reset it if you have forgotten it. If this was not you, ignore this email.\n",
+	})
+}
+
+// Resend mails a new link to email if it belongs to an unverified account.
+// Unknown and already verified addresses are ignored so callers cannot probe
+// which accounts exist.
+func (v *Verifier) Resend(ctx context.Context, email string) error {
+	u, err := v.Repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if u.EmailVerified {
+		return nil
+	}
+
+	return v.Send(ctx, u)
+}
+
+// Verify marks the address named by token as verified and returns the user.
+func (v *Verifier) Verify(ctx context.Context, token string) (*ent.User, error) {
+	claims, err := v.JWT.ParseAction(token, PurposeVerifyEmail)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	id, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	ok, err := v.Repo.MarkEmailVerified(ctx, id, claims.Binding)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	return v.Repo.GetById(ctx, id)
+}
+
+func (v *Verifier) link(token string) (string, error) {
+	u, err := url.Parse(v.Config.LinkBaseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid verification link base url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,120 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/internal/auth"
+	"app/internal/mailer"
+	"app/internal/testutil"
+	"app/internal/user"
+)
+
+const testLinkBaseURL = "http://localhost:3000/verify-email"
+
+func newTestVerifier(t *testing.T) (*user.Verifier, *user.Service, *mailer.Memory) {
+	t.Helper()
+
+	d := testutil.DB(t)
+	key, err := auth.GenerateKey("ES256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt := auth.New(auth.NewKeyRing(key), auth.Config{TTL: time.Hour})
+
+	m := mailer.NewMemory()
+	v := user.NewVerifier(user.NewPostgresRepo(d), jwt, m, user.VerifierConfig{TTL: time.Hour, LinkBaseURL: testLinkBaseURL})
+	return v, testutil.Users(d, user.Config{}), m
+}
+
+func TestVerificationEmail(t *testing.T) {
+	v, users, m := newTestVerifier(t)
+	ctx := context.Background()
+
+	u, err := users.Register(ctx, "alice@example.com", "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Send(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, ok := m.Last("alice@example.com")
+	if !ok {
+		t.Fatal("no verification email sent")
+	}
+	if msg.Subject != "Verify your email address" {
+		t.Fatalf("subject = %q", msg.Subject)
+	}
+
+	token := testutil.LinkToken(t, msg, testLinkBaseURL)
+	verified, err := v.Verify(ctx, token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if verified.ID != u.ID || !verified.EmailVerified {
+		t.Fatalf("verified user = %+v", verified)
+	}
+
+	if _, err := v.Verify(ctx, token); !errors.Is(err, user.ErrInvalidVerificationToken) {
+		t.Fatalf("reused link: got %v, want ErrInvalidVerificationToken", err)
+	}
+}
+
+func TestVerificationEmailAfterAddressChange(t *testing.T) {
+	v, users, m := newTestVerifier(t)
+	ctx := context.Background()
+
+	u, err := users.Register(ctx, "alice@example.com", "password123", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Send(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	msg, _ := m.Last("alice@example.com")
+
+	email := "alice@example.org"
+	if _, err := users.UpdateProfile(ctx, u.ID, &email, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Verify(ctx, testutil.LinkToken(t, msg, testLinkBaseURL)); !errors.Is(err, user.ErrInvalidVerificationToken) {
+		t.Fatalf("link for the old address: got %v, want ErrInvalidVerificationToken", err)
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	v, users, m := newTestVerifier(t)
+	ctx := context.Background()
+
+	if err := v.Resend(ctx, "nobody@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Messages()) != 0 {
+		t.Fatalf("email sent to an unknown address: %+v", m.Messages())
+	}
+
+	if _, err := users.Register(ctx, "alice@example.com", "password123", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Resend(ctx, "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	msg, ok := m.Last("alice@example.com")
+	if !ok {
+		t.Fatal("no verification email sent")
+	}
+	if _, err := v.Verify(ctx, testutil.LinkToken(t, msg, testLinkBaseURL)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if err := v.Resend(ctx, "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(m.Messages()); n != 1 {
+		t.Fatalf("%d emails sent, want 1 as the address is verified", n)
+	}
+}